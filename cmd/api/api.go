@@ -15,11 +15,14 @@ import (
 	"github.com/gustavobelfort/42-jitsi/internal/handler"
 	"github.com/gustavobelfort/42-jitsi/internal/intra"
 	"github.com/gustavobelfort/42-jitsi/internal/logging"
+	"github.com/gustavobelfort/42-jitsi/internal/notify"
+	// Registers the slack_that and slack notifiers.
+	_ "github.com/gustavobelfort/42-jitsi/internal/slack"
 	"github.com/sirupsen/logrus"
 )
 
 func init() {
-	config.AddRequired("intra.app_id", "intra.app_secret", "intra.webhooks")
+	config.AddRequired("intra.app_id", "intra.app_secret", "intra.webhooks", "jitsi.room_secret")
 	if err := config.Initiate(); err != nil {
 		logrus.WithError(err).Fatalf("could not load configuration: %v", err)
 	}
@@ -44,9 +47,22 @@ func main() {
 	if err != nil {
 		logrus.WithError(err).Fatalf("could not initiate intra api client: %v", err)
 	}
+	if config.Conf.Intra.CacheTTL > 0 {
+		client = intra.NewCachedClient(client, db.GlobalDB, config.Conf.Intra.CacheTTL)
+	}
+
+	notifier, err := notify.NewFromConfig(client)
+	if err != nil {
+		logrus.WithError(err).Fatalf("could not initiate the notifier: %v", err)
+	}
+
+	hdl := handler.NewScaleTeamHandler(client, notifier, db.GlobalDB)
 
-	hdl := handler.NewScaleTeamHandler(client, db.GlobalDB)
-	consumer := router.NewRouter(server, hdl, config.Conf.Intra.Webhooks, "/", config.Conf.Timeout)
+	var options []router.Option
+	if secret := config.Conf.Jitsi.EventsSecret; secret != "" {
+		options = append(options, router.JitsiEventsOption(handler.NewJitsiEventHandler(db.GlobalDB), secret))
+	}
+	consumer := router.NewRouter(server, hdl, config.Conf.Intra.Webhooks, "/", config.Conf.Timeout, options...)
 
 	waitForShutdown(consumer)
 }
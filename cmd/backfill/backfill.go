@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gustavobelfort/42-jitsi/internal/backfill"
+	"github.com/gustavobelfort/42-jitsi/internal/config"
+	"github.com/gustavobelfort/42-jitsi/internal/db"
+	"github.com/gustavobelfort/42-jitsi/internal/handler"
+	"github.com/gustavobelfort/42-jitsi/internal/intra"
+	"github.com/gustavobelfort/42-jitsi/internal/logging"
+	"github.com/gustavobelfort/42-jitsi/internal/notify"
+	// Registers the slack_that and slack notifiers.
+	_ "github.com/gustavobelfort/42-jitsi/internal/slack"
+	"github.com/sirupsen/logrus"
+)
+
+func init() {
+	config.AddRequired("intra.app_id", "intra.app_secret", "jitsi.room_secret")
+	if err := config.Initiate(); err != nil {
+		logrus.WithError(err).Fatalf("could not load configuration: %v", err)
+	}
+	logging.Initiate()
+	if err := db.Init(); err != nil {
+		logrus.WithError(err).Fatalf("could not connect to the db: %v", err)
+	}
+}
+
+// backfill imports the upcoming evaluations from the intra API, e.g: the ones whose webhooks were missed.
+func main() {
+	window := flag.Duration("window", time.Hour*24*7, "how far ahead the evaluations are imported")
+	campusID := flag.Int("campus", config.Conf.Intra.CampusID, "campus whose evaluations are imported, 0 for every campus")
+	dryRun := flag.Bool("dry-run", false, "print the differences with the database without changing it")
+	flag.Parse()
+
+	client, err := intra.NewClient(config.Conf.Intra.AppID, config.Conf.Intra.AppSecret, http.DefaultClient)
+	if err != nil {
+		logrus.WithError(err).Fatalf("could not initiate intra api client: %v", err)
+	}
+	if config.Conf.Intra.CacheTTL > 0 {
+		client = intra.NewCachedClient(client, db.GlobalDB, config.Conf.Intra.CacheTTL)
+	}
+
+	notifier, err := notify.NewFromConfig(client)
+	if err != nil {
+		logrus.WithError(err).Fatalf("could not initiate the notifier: %v", err)
+	}
+
+	hdl := handler.NewScaleTeamHandler(client, notifier, db.GlobalDB)
+
+	from := time.Now()
+	result, err := backfill.Backfill(context.Background(), client, hdl, *campusID, from, from.Add(*window), *dryRun)
+	if err != nil {
+		logrus.WithError(err).Fatalf("could not backfill the scale teams: %v", err)
+	}
+
+	if *dryRun {
+		writer := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(writer, "SCALE TEAM\tACTION\tFROM\tTO\tMEMBERS")
+		for _, change := range result.Changes {
+			fmt.Fprintf(writer, "%d\t%s\t%s\t%s\t%s\n",
+				change.ScaleTeamID,
+				change.Action,
+				formatBeginAt(change.From),
+				formatBeginAt(change.To),
+				formatMembers(change),
+			)
+		}
+		writer.Flush()
+	}
+	fmt.Printf("%d scale teams listed, %d skipped, %d failed\n", result.Listed, result.Skipped, result.Failed)
+	if result.Failed != 0 {
+		os.Exit(1)
+	}
+}
+
+func formatBeginAt(beginAt time.Time) string {
+	if beginAt.IsZero() {
+		return "-"
+	}
+	return beginAt.UTC().Format(config.Conf.BeginAtTimeLayout)
+}
+
+func formatMembers(change handler.Change) string {
+	var members []string
+	for _, member := range change.Joined {
+		members = append(members, "+"+member.Login)
+	}
+	for _, member := range change.Left {
+		members = append(members, "-"+member.Login)
+	}
+	if len(members) == 0 {
+		return "-"
+	}
+	return strings.Join(members, " ")
+}
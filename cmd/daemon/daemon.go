@@ -5,19 +5,26 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/gustavobelfort/42-jitsi/internal/config"
 	"github.com/gustavobelfort/42-jitsi/internal/consumers"
 	"github.com/gustavobelfort/42-jitsi/internal/db"
+	"github.com/gustavobelfort/42-jitsi/internal/handler"
 	"github.com/gustavobelfort/42-jitsi/internal/intra"
 	"github.com/gustavobelfort/42-jitsi/internal/logging"
+	"github.com/gustavobelfort/42-jitsi/internal/notify"
+	"github.com/gustavobelfort/42-jitsi/internal/reminder"
+	"github.com/gustavobelfort/42-jitsi/internal/room"
 	"github.com/gustavobelfort/42-jitsi/internal/scheduler"
-	"github.com/gustavobelfort/42-jitsi/internal/slack"
+	// Registers the slack_that and slack notifiers.
+	_ "github.com/gustavobelfort/42-jitsi/internal/slack"
 	"github.com/gustavobelfort/42-jitsi/internal/tasks"
 	"github.com/sirupsen/logrus"
 )
 
 func init() {
+	config.AddRequired("jitsi.room_secret")
 	if err := config.Initiate(); err != nil {
 		logrus.WithError(err).Fatalf("could not load configuration: %v", err)
 	}
@@ -25,6 +32,12 @@ func init() {
 	if err := db.Init(); err != nil {
 		logrus.WithError(err).Fatalf("could not connect to the db: %v", err)
 	}
+	if err := room.CheckMeetings(db.GlobalDB, db.GlobalScaleTeamManager); err != nil {
+		logrus.WithError(err).Fatalf("could not check the scale teams' meetings: %v", err)
+	}
+	if err := reminder.CheckScaleTeams(db.GlobalDB, db.GlobalScaleTeamManager); err != nil {
+		logrus.WithError(err).Fatalf("could not check the scale teams' reminders: %v", err)
+	}
 }
 
 func main() {
@@ -33,19 +46,39 @@ func main() {
 	if err != nil {
 		logrus.WithError(err).Fatalf("could not initiate intra api client: %v", err)
 	}
+	if config.Conf.Intra.CacheTTL > 0 {
+		iClient = intra.NewCachedClient(iClient, db.GlobalDB, config.Conf.Intra.CacheTTL)
+	}
 
-	sClient, err := slack.New(iClient, config.Conf.SlackThat.URL)
+	notifier, err := notify.NewFromConfig(iClient)
 	if err != nil {
-		logrus.WithError(err).Fatalf("could not initiate slack_that client: %v", err)
+		logrus.WithError(err).Fatalf("could not initiate the notifier: %v", err)
 	}
 
-	tHdl := tasks.NewTasksHandler(sClient, db.GlobalDB)
-	tasks := scheduler.Task{
-		Task:     tHdl.Notify,
-		Interval: config.Conf.WarnBefore,
+	stHdl := handler.NewScaleTeamHandler(iClient, notifier, db.GlobalDB)
+	tHdl := tasks.NewTasksHandler(notifier, iClient, stHdl, db.GlobalDB)
+	tasks := []scheduler.Task{
+		{
+			Task:     tHdl.Notify,
+			Interval: config.Conf.NotifyInterval,
+		},
+		{
+			Task:     tHdl.CheckAttendance,
+			Interval: config.Conf.NotifyInterval,
+		},
+		{
+			Task:     tHdl.PurgeDeliveries,
+			Interval: time.Hour,
+		},
+	}
+	if config.Conf.ReconcileInterval > 0 {
+		tasks = append(tasks, scheduler.Task{
+			Task:     tHdl.Reconcile,
+			Interval: config.Conf.ReconcileInterval,
+		})
 	}
 
-	scheduler, err := scheduler.New([]scheduler.Task{tasks})
+	scheduler, err := scheduler.New(tasks)
 	if err != nil {
 		logrus.WithError(err).Fatalf("could not create scheduler: %v", err)
 	}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"flag"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gustavobelfort/42-jitsi/internal/intra/intratest"
+	"github.com/sirupsen/logrus"
+)
+
+// fakeintra serves a fake of the intra API seeded with fixtures, so that the other processes can run locally without
+// the network by pointing INTRA_URL to it.
+func main() {
+	addr := flag.String("addr", "localhost:4242", "address the fake API listens on")
+	fixturesPath := flag.String("fixtures", "internal/intra/intratest/testdata/fixtures.json", "JSON file of the served users, teams and scale teams")
+	start := flag.Duration("start", time.Minute*10, "delay after which the first scale team begins, their dates are kept if 0")
+	rateLimit := flag.Int("rate-limit", 2, "requests allowed per second before answering 429, unlimited if 0")
+	clientID := flag.String("client-id", "", "client id required to get a token, any if empty")
+	clientSecret := flag.String("client-secret", "", "client secret required to get a token")
+	flag.Parse()
+
+	fixtures, err := intratest.LoadFixtures(*fixturesPath)
+	if err != nil {
+		logrus.WithError(err).Fatalf("could not load the fixtures: %v", err)
+	}
+	if *start != 0 {
+		if err := fixtures.ShiftScaleTeams(time.Now().Add(*start)); err != nil {
+			logrus.WithError(err).Fatalf("could not shift the scale teams: %v", err)
+		}
+	}
+
+	options := []intratest.Option{intratest.RateLimitOption(*rateLimit)}
+	if *clientID != "" {
+		options = append(options, intratest.CredentialsOption(*clientID, *clientSecret))
+	}
+
+	gin.SetMode(gin.ReleaseMode)
+	logrus.WithField("addr", *addr).Info("serving the fake intra api")
+	if err := http.ListenAndServe(*addr, intratest.NewAPI(fixtures, options...)); err != nil {
+		logrus.WithError(err).Fatalf("could not serve the fake intra api: %v", err)
+	}
+}
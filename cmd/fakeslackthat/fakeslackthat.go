@@ -0,0 +1,24 @@
+package main
+
+import (
+	"flag"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gustavobelfort/42-jitsi/internal/slack/slackthattest"
+	"github.com/sirupsen/logrus"
+)
+
+// fakeslackthat serves a fake of slack_that logging the messages posted to it instead of sending them, so that the
+// other processes can run locally by pointing SLACK_THAT_URL to it.
+func main() {
+	addr := flag.String("addr", "localhost:4343", "address the fake slack_that listens on")
+	latency := flag.Duration("latency", 0, "delay of every response")
+	flag.Parse()
+
+	gin.SetMode(gin.ReleaseMode)
+	logrus.WithField("addr", *addr).Info("serving the fake slack_that")
+	if err := http.ListenAndServe(*addr, slackthattest.NewAPI(slackthattest.LatencyOption(*latency))); err != nil {
+		logrus.WithError(err).Fatalf("could not serve the fake slack_that: %v", err)
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/gustavobelfort/42-jitsi/internal/config"
+	"github.com/gustavobelfort/42-jitsi/internal/db"
+	"github.com/gustavobelfort/42-jitsi/internal/logging"
+	"github.com/gustavobelfort/42-jitsi/internal/reminder"
+	"github.com/gustavobelfort/42-jitsi/internal/room"
+	"github.com/jinzhu/gorm"
+	"github.com/sirupsen/logrus"
+)
+
+const usage = `usage: migrate <command>
+
+commands:
+  up          apply every pending migration, then backfill the scale teams stored by the previous versions
+  down [n]    revert the n last applied migrations (1 by default)
+  status      list the migrations and when they were applied
+`
+
+func init() {
+	if err := config.Initiate(); err != nil {
+		logrus.WithError(err).Fatalf("could not load configuration: %v", err)
+	}
+	logging.Initiate()
+}
+
+// migrate manages the versioned migrations of the database schema.
+func main() {
+	flag.Usage = func() { fmt.Fprint(flag.CommandLine.Output(), usage) }
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	gormDB, err := db.Open()
+	if err != nil {
+		logrus.WithError(err).Fatalf("could not connect to the db: %v", err)
+	}
+	defer gormDB.Close()
+	sqlDB := gormDB.DB()
+
+	switch flag.Arg(0) {
+	case "up":
+		count, err := db.MigrateUp(sqlDB)
+		if err != nil {
+			logrus.WithError(err).Fatalf("could not apply the migrations: %v", err)
+		}
+		fmt.Printf("applied %d migrations, the schema is at version %d\n", count, db.LatestSchemaVersion())
+		if err := backfill(gormDB); err != nil {
+			logrus.WithError(err).Fatalf("could not backfill the scale teams: %v", err)
+		}
+	case "down":
+		steps := 1
+		if flag.NArg() > 1 {
+			if steps, err = strconv.Atoi(flag.Arg(1)); err != nil || steps < 1 {
+				logrus.Fatalf("invalid number of migrations to revert: '%s'", flag.Arg(1))
+			}
+		}
+		count, err := db.MigrateDown(sqlDB, steps)
+		if err != nil {
+			logrus.WithError(err).Fatalf("could not revert the migrations: %v", err)
+		}
+		fmt.Printf("reverted %d migrations\n", count)
+	case "status":
+		states, err := db.MigrationStatus(sqlDB)
+		if err != nil {
+			logrus.WithError(err).Fatalf("could not get the migrations' status: %v", err)
+		}
+		writer := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(writer, "VERSION\tNAME\tAPPLIED AT")
+		for _, state := range states {
+			appliedAt := "pending"
+			if state.AppliedAt != nil {
+				appliedAt = state.AppliedAt.UTC().Format(config.Conf.BeginAtTimeLayout)
+			}
+			fmt.Fprintf(writer, "%d\t%s\t%s\n", state.Version, state.Name, appliedAt)
+		}
+		writer.Flush()
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+// backfill generates what the scale teams stored by the previous versions miss, in a single transaction. The daemon
+// refuses to start until it is done.
+func backfill(gormDB *gorm.DB) error {
+	scaleTeamManager := db.NewScaleTeamManager(gormDB)
+	reminderManager := db.NewReminderManager(gormDB)
+	return gormDB.Transaction(func(tx *gorm.DB) error {
+		if err := room.MigrateMeetings(tx, scaleTeamManager); err != nil {
+			return err
+		}
+		return reminder.MigrateScaleTeams(tx, scaleTeamManager, reminderManager)
+	})
+}
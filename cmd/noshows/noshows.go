@@ -0,0 +1,46 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/gustavobelfort/42-jitsi/internal/config"
+	"github.com/gustavobelfort/42-jitsi/internal/db"
+	"github.com/gustavobelfort/42-jitsi/internal/logging"
+	"github.com/sirupsen/logrus"
+)
+
+func init() {
+	if err := config.Initiate(); err != nil {
+		logrus.WithError(err).Fatalf("could not load configuration: %v", err)
+	}
+	logging.Initiate()
+	if err := db.Init(); err != nil {
+		logrus.WithError(err).Fatalf("could not connect to the db: %v", err)
+	}
+}
+
+// noshows lists the evaluations a user never joined, from the latest to the oldest.
+func main() {
+	login := flag.String("login", "", "login of the user whose no-shows are listed")
+	flag.Parse()
+	if *login == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	noShows, err := db.GlobalNoShowManager.Get(db.GlobalDB, db.NoShowLoginOption(*login), db.NoShowLatestFirstOption())
+	if err != nil {
+		logrus.WithError(err).Fatalf("could not get the no-shows of %s: %v", *login, err)
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(writer, "SCALE TEAM\tBEGIN AT\tSTATUS")
+	for _, noShow := range noShows {
+		fmt.Fprintf(writer, "%d\t%s\t%s\n", noShow.GetScaleTeamID(), noShow.GetBeginAt().UTC().Format(config.Conf.BeginAtTimeLayout), noShow.GetStatus())
+	}
+	writer.Flush()
+	fmt.Printf("%s missed %d evaluations\n", *login, len(noShows))
+}
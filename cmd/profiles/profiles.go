@@ -0,0 +1,63 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/gustavobelfort/42-jitsi/internal/config"
+	"github.com/gustavobelfort/42-jitsi/internal/db"
+	"github.com/gustavobelfort/42-jitsi/internal/logging"
+	"github.com/sirupsen/logrus"
+)
+
+func init() {
+	if err := config.Initiate(); err != nil {
+		logrus.WithError(err).Fatalf("could not load configuration: %v", err)
+	}
+	logging.Initiate()
+	if err := db.Init(); err != nil {
+		logrus.WithError(err).Fatalf("could not connect to the db: %v", err)
+	}
+}
+
+// profiles shows the cached profile of a user, or forgets it with -invalidate so that it is requested again to the
+// intra API on its next lookup.
+func main() {
+	login := flag.String("login", "", "login of the user whose cached profile is shown")
+	invalidate := flag.Bool("invalidate", false, "forget the cached profile instead of showing it")
+	flag.Parse()
+	if *login == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if *invalidate {
+		invalidated, err := db.GlobalProfileManager.Invalidate(db.GlobalDB, *login)
+		if err != nil {
+			logrus.WithError(err).Fatalf("could not invalidate the cached profile of %s: %v", *login, err)
+		}
+		if !invalidated {
+			fmt.Printf("no cached profile for %s\n", *login)
+			return
+		}
+		fmt.Printf("invalidated the cached profile of %s\n", *login)
+		return
+	}
+
+	profiles, err := db.GlobalProfileManager.Get(db.GlobalDB, db.ProfileLoginOption(*login))
+	if err != nil {
+		logrus.WithError(err).Fatalf("could not get the cached profile of %s: %v", *login, err)
+	}
+	if len(profiles) == 0 {
+		fmt.Printf("no cached profile for %s\n", *login)
+		return
+	}
+	profile := profiles[0]
+	expired := ""
+	if time.Since(profile.GetFetchedAt()) >= config.Conf.Intra.CacheTTL {
+		expired = " (expired)"
+	}
+	fmt.Printf("%s: %s, fetched at %s%s\n", profile.GetLogin(), profile.GetEmail(), profile.GetFetchedAt().UTC().Format(config.Conf.BeginAtTimeLayout), expired)
+}
@@ -15,11 +15,15 @@ import (
 	"github.com/gustavobelfort/42-jitsi/internal/handler"
 	"github.com/gustavobelfort/42-jitsi/internal/intra"
 	"github.com/gustavobelfort/42-jitsi/internal/logging"
+	"github.com/gustavobelfort/42-jitsi/internal/notify"
+	// Registers the slack_that and slack notifiers.
+	_ "github.com/gustavobelfort/42-jitsi/internal/slack"
 	"github.com/sirupsen/logrus"
 	"github.com/streadway/amqp"
 )
 
 func init() {
+	config.AddRequired("jitsi.room_secret")
 	if err := config.Initiate(); err != nil {
 		logrus.Fatalf("could not load configuration: %v", err)
 	}
@@ -37,6 +41,9 @@ func main() {
 	if err != nil {
 		logrus.Fatalf("could not initiate intra api client: %v", err)
 	}
+	if config.Conf.Intra.CacheTTL > 0 {
+		client = intra.NewCachedClient(client, db.GlobalDB, config.Conf.Intra.CacheTTL)
+	}
 
 	conn, err := amqp.Dial(config.Conf.RabbitMQ.URL())
 	if err != nil {
@@ -48,7 +55,12 @@ func main() {
 		logrus.Fatalf("could not initiate rabbitmq channel: %v", err)
 	}
 
-	hdl := handler.NewScaleTeamHandler(client, db.GlobalDB)
+	notifier, err := notify.NewFromConfig(client)
+	if err != nil {
+		logrus.Fatalf("could not initiate the notifier: %v", err)
+	}
+
+	hdl := handler.NewScaleTeamHandler(client, notifier, db.GlobalDB)
 	consumer := amqp2.NewAMQP(channel, config.Conf.RabbitMQ.Queue, nil, hdl, config.Conf.Timeout)
 
 	waitForShutdown(consumer)
@@ -0,0 +1,79 @@
+package backfill
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gustavobelfort/42-jitsi/internal/handler"
+	"github.com/gustavobelfort/42-jitsi/internal/intra"
+	"github.com/gustavobelfort/42-jitsi/internal/logging"
+	"github.com/sirupsen/logrus"
+)
+
+// Result sums up a backfill.
+type Result struct {
+	// Listed is the number of scale teams intra returned.
+	Listed int
+	// Skipped is the number of scale teams which were left out as they do not have any corrector, e.g: the automatic
+	// evaluations of the intranet.
+	Skipped int
+	// Failed is the number of scale teams which could not be handled or previewed.
+	Failed int
+	// Changes are the previewed changes of a dry run, in the order of the listing.
+	Changes []handler.Change
+}
+
+// Backfill lists the scale teams beginning between `from` and `to` of the campus, or of every campus if `campusID` is
+// 0, and handles each of them as an update, the same way the webhooks are.
+//
+// During a dry run, nothing is changed and the changes the scale teams would make are returned instead.
+func Backfill(ctx context.Context, client intra.Client, hdl handler.ScaleTeamHandler, campusID int, from, to time.Time, dryRun bool) (*Result, error) {
+	logger := logging.ContextLog(ctx, logrus.StandardLogger()).WithFields(logrus.Fields{
+		"campus_id": campusID,
+		"from":      from,
+		"to":        to,
+		"dry_run":   dryRun,
+	})
+
+	logger.Info("listing the scale teams from intra")
+	scaleTeams, err := client.ListScaleTeams(ctx, campusID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	logger.Infof("found %d scale teams to backfill", len(scaleTeams))
+
+	result := &Result{Listed: len(scaleTeams)}
+	for i := range scaleTeams {
+		scaleTeam := &scaleTeams[i]
+		stCtx := logging.ContextWithField(ctx, "scale_team_id", scaleTeam.ID)
+		if dryRun {
+			change, err := hdl.Preview(stCtx, scaleTeam)
+			if errors.Is(err, handler.NoCorrectorError) {
+				result.Skipped++
+				logging.ContextLog(stCtx, logger).Debug("skipping the scale team: it does not have any corrector")
+				continue
+			}
+			if err != nil {
+				result.Failed++
+				logging.LogError(logging.ContextLog(stCtx, logger), err, "previewing the scale team")
+				continue
+			}
+			result.Changes = append(result.Changes, change)
+			continue
+		}
+		err := hdl.HandleScaleTeam(stCtx, scaleTeam)
+		if errors.Is(err, handler.NoCorrectorError) {
+			result.Skipped++
+			logging.ContextLog(stCtx, logger).Debug("skipping the scale team: it does not have any corrector")
+		} else if err != nil {
+			result.Failed++
+			logging.LogError(logging.ContextLog(stCtx, logger), err, "backfilling the scale team")
+		}
+	}
+	logger.WithFields(logrus.Fields{
+		"skipped": result.Skipped,
+		"failed":  result.Failed,
+	}).Infof("backfilled %d scale teams", result.Listed-result.Skipped-result.Failed)
+	return result, nil
+}
@@ -0,0 +1,85 @@
+package backfill
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gustavobelfort/42-jitsi/internal/handler"
+	"github.com/gustavobelfort/42-jitsi/internal/intra"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type BackfillSuite struct {
+	suite.Suite
+
+	cMock *ClientMock
+	hMock *HandlerMock
+
+	from, to   time.Time
+	scaleTeams []intra.ScaleTeam
+}
+
+func (s *BackfillSuite) SetupTest() {
+	s.cMock = &ClientMock{}
+	s.hMock = &HandlerMock{}
+
+	s.from = time.Date(2020, 7, 15, 0, 0, 0, 0, time.UTC)
+	s.to = s.from.Add(time.Hour * 24 * 7)
+	s.scaleTeams = []intra.ScaleTeam{
+		{ID: 21, BeginAt: time.Date(2020, 7, 15, 21, 0, 0, 0, time.UTC)},
+		{ID: 22, BeginAt: time.Date(2020, 7, 16, 21, 0, 0, 0, time.UTC)},
+		{ID: 23, BeginAt: time.Date(2020, 7, 17, 21, 0, 0, 0, time.UTC)},
+	}
+}
+
+func (s *BackfillSuite) Test00_Backfill() {
+	ctx := context.Background()
+	s.cMock.On("ListScaleTeams", ctx, 21, s.from, s.to).Return(s.scaleTeams, nil).Once()
+	s.hMock.On("HandleScaleTeam", mock.Anything, &s.scaleTeams[0]).Return(nil).Once()
+	s.hMock.On("HandleScaleTeam", mock.Anything, &s.scaleTeams[1]).Return(errors.New("testing")).Once()
+	s.hMock.On("HandleScaleTeam", mock.Anything, &s.scaleTeams[2]).Return(handler.NoCorrectorError).Once()
+
+	result, err := Backfill(ctx, s.cMock, s.hMock, 21, s.from, s.to, false)
+	s.Require().NoError(err)
+	s.Equal(&Result{Listed: 3, Skipped: 1, Failed: 1}, result)
+}
+
+func (s *BackfillSuite) Test01_Backfill_DryRun() {
+	expectedChange := handler.Change{
+		ScaleTeamID: 21,
+		Action:      handler.CreateAction,
+		To:          time.Date(2020, 7, 15, 21, 0, 0, 0, time.UTC),
+	}
+
+	ctx := context.Background()
+	s.cMock.On("ListScaleTeams", ctx, 0, s.from, s.to).Return(s.scaleTeams, nil).Once()
+	s.hMock.On("Preview", mock.Anything, &s.scaleTeams[0]).Return(expectedChange, nil).Once()
+	s.hMock.On("Preview", mock.Anything, &s.scaleTeams[1]).Return(handler.Change{}, errors.New("testing")).Once()
+	s.hMock.On("Preview", mock.Anything, &s.scaleTeams[2]).Return(handler.Change{}, handler.NoCorrectorError).Once()
+
+	result, err := Backfill(ctx, s.cMock, s.hMock, 0, s.from, s.to, true)
+	s.Require().NoError(err)
+	s.Equal(&Result{Listed: 3, Skipped: 1, Failed: 1, Changes: []handler.Change{expectedChange}}, result)
+}
+
+func (s *BackfillSuite) Test02_Backfill_ListError() {
+	expectedError := errors.New("testing")
+
+	ctx := context.Background()
+	s.cMock.On("ListScaleTeams", ctx, 0, s.from, s.to).Return([]intra.ScaleTeam{}, expectedError).Once()
+
+	_, err := Backfill(ctx, s.cMock, s.hMock, 0, s.from, s.to, false)
+	s.Equal(expectedError, err)
+}
+
+func (s *BackfillSuite) TearDownTest() {
+	s.cMock.AssertExpectations(s.T())
+	s.hMock.AssertExpectations(s.T())
+}
+
+func TestBackfill(t *testing.T) {
+	suite.Run(t, new(BackfillSuite))
+}
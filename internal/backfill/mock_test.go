@@ -0,0 +1,65 @@
+package backfill
+
+import (
+	"context"
+	"time"
+
+	"github.com/gustavobelfort/42-jitsi/internal/handler"
+	"github.com/gustavobelfort/42-jitsi/internal/intra"
+	"github.com/stretchr/testify/mock"
+)
+
+type ClientMock struct {
+	mock.Mock
+}
+
+func (m *ClientMock) GetTeamMembers(ctx context.Context, teamID int) ([]string, error) {
+	toReturn := m.Called(ctx, teamID)
+	return toReturn.Get(0).([]string), toReturn.Error(1)
+}
+
+func (m *ClientMock) GetUserEmail(ctx context.Context, login string) (string, error) {
+	toReturn := m.Called(ctx, login)
+	return toReturn.String(0), toReturn.Error(1)
+}
+
+func (m *ClientMock) GetUserEmails(ctx context.Context, logins []string) (map[string]string, error) {
+	toReturn := m.Called(ctx, logins)
+	emails, _ := toReturn.Get(0).(map[string]string)
+	return emails, toReturn.Error(1)
+}
+
+func (m *ClientMock) ListScaleTeams(ctx context.Context, campusID int, from, to time.Time) ([]intra.ScaleTeam, error) {
+	toReturn := m.Called(ctx, campusID, from, to)
+	return toReturn.Get(0).([]intra.ScaleTeam), toReturn.Error(1)
+}
+
+func (m *ClientMock) GetScaleTeam(ctx context.Context, id int) (*intra.ScaleTeam, error) {
+	toReturn := m.Called(ctx, id)
+	return toReturn.Get(0).(*intra.ScaleTeam), toReturn.Error(1)
+}
+
+type HandlerMock struct {
+	mock.Mock
+}
+
+func (m *HandlerMock) HandleCreate(ctx context.Context, data []byte) error {
+	return m.Called(ctx, data).Error(0)
+}
+
+func (m *HandlerMock) HandleUpdate(ctx context.Context, data []byte) error {
+	return m.Called(ctx, data).Error(0)
+}
+
+func (m *HandlerMock) HandleDestroy(ctx context.Context, data []byte) error {
+	return m.Called(ctx, data).Error(0)
+}
+
+func (m *HandlerMock) HandleScaleTeam(ctx context.Context, st *intra.ScaleTeam) error {
+	return m.Called(ctx, st).Error(0)
+}
+
+func (m *HandlerMock) Preview(ctx context.Context, st *intra.ScaleTeam) (handler.Change, error) {
+	toReturn := m.Called(ctx, st)
+	return toReturn.Get(0).(handler.Change), toReturn.Error(1)
+}
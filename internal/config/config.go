@@ -17,9 +17,20 @@ type Configuration struct {
 
 	EmailSuffix       string          `mapstructure:"email_suffix"`
 	SlackThat         SlackThatConfig `mapstructure:"slack_that"`
-	WarnBefore        time.Duration   `mapstructure:"warn_before"`
-	BeginAtTimeLayout string          `mapstructure:"begin_at_time_layout"`
-
+	Reminders         []time.Duration
+	NotifyInterval    time.Duration `mapstructure:"notify_interval"`
+	CancelWindow      time.Duration `mapstructure:"cancel_window"`
+	NoShowDelay       time.Duration `mapstructure:"no_show_delay"`
+	DeliveryRetention time.Duration `mapstructure:"delivery_retention"`
+	ReconcileInterval time.Duration `mapstructure:"reconcile_interval"`
+	ReconcileWindow   time.Duration `mapstructure:"reconcile_window"`
+	BeginAtTimeLayout string        `mapstructure:"begin_at_time_layout"`
+
+	// Notifier is the backend notifying the participants: "slack_that", or "slack" to use the Slack Web API directly.
+	Notifier string
+	Slack    SlackConfig
+
+	Jitsi    Jitsi
 	Intra    Intra
 	Postgres Database
 	RabbitMQ RabbitMQ
@@ -75,16 +86,60 @@ func (r *RabbitMQ) URL() string {
 
 // Intra is the type that will hold the Intranet configurations
 type Intra struct {
+	// URL is the base url of the API, which may be changed to use a fake one during development.
+	URL       string
 	AppID     string `mapstructure:"app_id"`
 	AppSecret string `mapstructure:"app_secret"`
 	Webhooks  map[string]string
+	// CampusID restricts the scale teams listed from the API to a campus. Every campus is listed if it is 0.
+	CampusID int `mapstructure:"campus_id"`
+	// RateLimit and HourlyLimit are the number of requests the application may make per second and per hour, 0 for no
+	// limit. MaxRetries is the number of times a rate limited request is retried.
+	RateLimit   int `mapstructure:"rate_limit"`
+	HourlyLimit int `mapstructure:"hourly_limit"`
+	MaxRetries  int `mapstructure:"max_retries"`
+	// TokenRefreshSkew is how long before its expiry the API token is refreshed.
+	TokenRefreshSkew time.Duration `mapstructure:"token_refresh_skew"`
+	// CacheTTL is how long the users' profiles are cached in the database, 0 to disable the cache.
+	CacheTTL time.Duration `mapstructure:"cache_ttl"`
+}
+
+// Jitsi is the type that will hold the Jitsi server configurations
+type Jitsi struct {
+	URL          string
+	RoomTemplate string `mapstructure:"room_template"`
+	RoomSecret   string `mapstructure:"room_secret"`
+	RoomSalt     string `mapstructure:"room_salt"`
+	Options      []string
+	JWT          JitsiJWT
+	EventsSecret string `mapstructure:"events_secret"`
+}
+
+// JitsiJWT is the type that will hold the configurations of the tokens signed for jitsi's token authentication
+type JitsiJWT struct {
+	Enabled   bool
+	AppID     string `mapstructure:"app_id"`
+	AppSecret string `mapstructure:"app_secret"`
+	Audience  string
+	Subject   string
+	Duration  time.Duration
 }
 
 // Configurations for the Slackthat Microsservice
 type SlackThatConfig struct {
-	URL       string
-	Workspace string
-	Username  string
+	URL          string
+	Workspace    string
+	Username     string
+	StaffChannel string `mapstructure:"staff_channel"`
+}
+
+// SlackConfig is the type that will hold the Slack Web API configurations, used by the "slack" notifier
+type SlackConfig struct {
+	URL string
+	// Token is the bot token of the Slack application, with the scopes `chat:write`, `im:write`, `mpim:write` and
+	// `users:read.email`.
+	Token        string
+	StaffChannel string `mapstructure:"staff_channel"`
 }
 
 // stringToMapstringHookFunc will decode a string to a mapstring.
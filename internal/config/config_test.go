@@ -76,10 +76,13 @@ func TestInitiate(t *testing.T) {
 		os.Setenv("INTRA_APP_SECRET", "intra_app_secret")
 		os.Setenv("INTRA_WEBHOOKS", "key:value")
 		os.Setenv("SLACK_THAT_WORKSPACE", "42born2code")
+		os.Setenv("JITSI_ROOM_SECRET", "secret")
 		os.Setenv("POSTGRES_PASSWORD", "changeme")
 
 		// Testing unmarshalling of not required env var fields
 		os.Setenv("POSTGRES_HOST", "testinghost")
+		os.Setenv("REMINDERS", "1h,15m")
+		os.Setenv("INTRA_CAMPUS_ID", "21")
 
 		defer os.Clearenv()
 
@@ -87,22 +90,50 @@ func TestInitiate(t *testing.T) {
 			Environment: "development",
 			Service:     "42-jitsi",
 
+			Notifier: "slack_that",
 			SlackThat: SlackThatConfig{
 				URL:       "http://localhost:8080",
 				Workspace: "42born2code",
 				Username:  "Evaluation Master",
 			},
+			Slack: SlackConfig{
+				URL: "https://slack.com/api/",
+			},
+			Jitsi: Jitsi{
+				URL:          "https://meet.jit.si",
+				RoomTemplate: "{{.Hash}}",
+				RoomSecret:   "secret",
+				JWT: JitsiJWT{
+					Enabled:  false,
+					Audience: "jitsi",
+					Subject:  "*",
+					Duration: time.Hour,
+				},
+			},
 			EmailSuffix:       "student.42campus.org",
 			BeginAtTimeLayout: "2006-01-02 15:04:05 UTC",
-			WarnBefore:        time.Minute * 15,
+			Reminders:         []time.Duration{time.Hour, time.Minute * 15},
+			NotifyInterval:    time.Minute,
+			CancelWindow:      time.Hour,
+			NoShowDelay:       time.Minute * 15,
+			DeliveryRetention: time.Hour * 72,
+			ReconcileInterval: time.Hour,
+			ReconcileWindow:   time.Hour * 24 * 7,
 			HTTPAddr:          "0.0.0.0:5000",
 			Timeout:           time.Second * 10,
 			Intra: Intra{
+				URL:       "https://api.intra.42.fr/",
 				AppID:     "intra_app_id",
 				AppSecret: "intra_app_secret",
 				Webhooks: map[string]string{
 					"key": "value",
 				},
+				CampusID:         21,
+				RateLimit:        2,
+				HourlyLimit:      1200,
+				MaxRetries:       3,
+				CacheTTL:         time.Hour * 24,
+				TokenRefreshSkew: time.Minute * 5,
 			},
 			LogLevel: logrus.DebugLevel,
 			Logstash: Logstash{
@@ -144,6 +175,25 @@ func TestInitiate(t *testing.T) {
 		assert.Error(t, Initiate())
 	})
 
+	t.Run("DeprecatedWarnBefore", func(t *testing.T) {
+		os.Setenv("CONFIG_FILE", "")
+		os.Setenv("POSTGRES_PASSWORD", "changeme")
+		os.Setenv("WARN_BEFORE", "30m")
+		defer os.Clearenv()
+
+		require.NoError(t, Initiate())
+		assert.Equal(t, []time.Duration{time.Minute * 30}, Conf.Reminders)
+
+		// The reminders are used over the deprecated key if they are set too.
+		os.Setenv("REMINDERS", "1h,15m")
+		require.NoError(t, Initiate())
+		assert.Equal(t, []time.Duration{time.Hour, time.Minute * 15}, Conf.Reminders)
+
+		os.Unsetenv("REMINDERS")
+		os.Setenv("WARN_BEFORE", "soon")
+		assert.Error(t, Initiate())
+	})
+
 	t.Run("FullConfigFile", func(t *testing.T) {
 		os.Setenv("CONFIG_FILE", "../../configs/configs.sample.yml")
 
@@ -151,22 +201,60 @@ func TestInitiate(t *testing.T) {
 			Environment: "development",
 			Service:     "42-jitsi",
 
+			Notifier: "slack_that",
 			SlackThat: SlackThatConfig{
-				URL:       "http://localhost:8080",
-				Workspace: "42born2code",
-				Username:  "Evaluation Master",
+				URL:          "http://localhost:8080",
+				Workspace:    "42born2code",
+				Username:     "Evaluation Master",
+				StaffChannel: "#pedago-no-shows",
+			},
+			Slack: SlackConfig{
+				URL:          "https://slack.com/api/",
+				Token:        "--FILL ME--",
+				StaffChannel: "#pedago-no-shows",
+			},
+			Jitsi: Jitsi{
+				URL:          "https://meet.jit.si",
+				RoomTemplate: "evaluation-{{.Hash}}",
+				RoomSecret:   "--FILL ME--",
+				RoomSalt:     "--FILL ME--",
+				Options: []string{
+					"config.prejoinPageEnabled=false",
+					`config.subject="42 Evaluation"`,
+				},
+				JWT: JitsiJWT{
+					Enabled:   false,
+					AppID:     "--FILL ME--",
+					AppSecret: "--FILL ME--",
+					Audience:  "jitsi",
+					Subject:   "meet.jitsi",
+					Duration:  time.Hour,
+				},
+				EventsSecret: "--FILL ME--",
 			},
 			EmailSuffix:       "student.42campus.org",
 			BeginAtTimeLayout: "2006-01-02 15:04:05 UTC",
-			WarnBefore:        time.Minute * 15,
+			Reminders:         []time.Duration{time.Hour, 0},
+			NotifyInterval:    time.Minute,
+			CancelWindow:      time.Hour,
+			NoShowDelay:       time.Minute * 15,
+			DeliveryRetention: time.Hour * 72,
+			ReconcileInterval: time.Hour,
+			ReconcileWindow:   time.Hour * 24 * 7,
 			HTTPAddr:          "0.0.0.0:5000",
 			Timeout:           time.Second * 10,
 			Intra: Intra{
+				URL:       "https://api.intra.42.fr/",
 				AppID:     "--FILL ME--",
 				AppSecret: "--FILL ME--",
 				Webhooks: map[string]string{
 					"--FILL": "ME--",
 				},
+				RateLimit:        2,
+				HourlyLimit:      1200,
+				MaxRetries:       3,
+				CacheTTL:         time.Hour * 24,
+				TokenRefreshSkew: time.Minute * 5,
 			},
 			LogLevel: logrus.DebugLevel,
 			Logstash: Logstash{
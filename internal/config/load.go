@@ -32,20 +32,44 @@ func setDefaults() {
 
 	viper.SetDefault("begin_at_time_layout", "2006-01-02 15:04:05 UTC")
 
-	viper.SetDefault("warn_before", time.Minute*15)
+	viper.SetDefault("reminders", []time.Duration{time.Minute * 15})
+	viper.SetDefault("notify_interval", time.Minute)
+	viper.SetDefault("cancel_window", time.Hour)
+	viper.SetDefault("no_show_delay", time.Minute*15)
+	viper.SetDefault("delivery_retention", time.Hour*72)
+	viper.SetDefault("reconcile_interval", time.Hour)
+	viper.SetDefault("reconcile_window", time.Hour*24*7)
 
 	viper.SetDefault("http_addr", "0.0.0.0:5000")
 
 	viper.SetDefault("timeout", time.Second*10)
 
+	viper.SetDefault("intra.url", "https://api.intra.42.fr/")
+	viper.SetDefault("intra.rate_limit", 2)
+	viper.SetDefault("intra.hourly_limit", 1200)
+	viper.SetDefault("intra.max_retries", 3)
+	viper.SetDefault("intra.token_refresh_skew", time.Minute*5)
+	viper.SetDefault("intra.cache_ttl", time.Hour*24)
+
 	viper.SetDefault("postgres.host", "localhost")
 	viper.SetDefault("postgres.port", 5432)
 	viper.SetDefault("postgres.db", "postgres")
 	viper.SetDefault("postgres.user", "postgres")
 
+	viper.SetDefault("jitsi.url", "https://meet.jit.si")
+	viper.SetDefault("jitsi.room_template", "{{.Hash}}")
+	viper.SetDefault("jitsi.jwt.enabled", false)
+	viper.SetDefault("jitsi.jwt.audience", "jitsi")
+	viper.SetDefault("jitsi.jwt.subject", "*")
+	viper.SetDefault("jitsi.jwt.duration", time.Hour)
+
+	viper.SetDefault("notifier", "slack_that")
+
 	viper.SetDefault("slack_that.url", "http://localhost:8080")
 	viper.SetDefault("slack_that.username", "Evaluation Master")
 
+	viper.SetDefault("slack.url", "https://slack.com/api/")
+
 	viper.SetDefault("rabbitmq.host", "localhost")
 	viper.SetDefault("rabbitmq.port", "5672")
 	viper.SetDefault("rabbitmq.vhost", "")
@@ -88,7 +112,14 @@ func bindEnv() {
 
 	logBinding("begin_at_time_layout", "BEGIN_AT_TIME_LAYOUT")
 
+	logBinding("reminders", "REMINDERS")
 	logBinding("warn_before", "WARN_BEFORE")
+	logBinding("notify_interval", "NOTIFY_INTERVAL")
+	logBinding("cancel_window", "CANCEL_WINDOW")
+	logBinding("no_show_delay", "NO_SHOW_DELAY")
+	logBinding("delivery_retention", "DELIVERY_RETENTION")
+	logBinding("reconcile_interval", "RECONCILE_INTERVAL")
+	logBinding("reconcile_window", "RECONCILE_WINDOW")
 
 	logBinding("timeout", "TIMEOUT")
 
@@ -97,8 +128,24 @@ func bindEnv() {
 	logBinding("postgres.db", "POSTGRES_DB")
 	logBinding("postgres.user", "POSTGRES_USER")
 
+	logBinding("jitsi.url", "JITSI_URL")
+	logBinding("jitsi.room_template", "JITSI_ROOM_TEMPLATE")
+	logBinding("jitsi.room_salt", "JITSI_ROOM_SALT")
+	logBinding("jitsi.options", "JITSI_OPTIONS")
+	logBinding("jitsi.jwt.enabled", "JITSI_JWT_ENABLED")
+	logBinding("jitsi.jwt.app_id", "JITSI_JWT_APP_ID")
+	logBinding("jitsi.jwt.audience", "JITSI_JWT_AUDIENCE")
+	logBinding("jitsi.jwt.subject", "JITSI_JWT_SUBJECT")
+	logBinding("jitsi.jwt.duration", "JITSI_JWT_DURATION")
+
+	logBinding("notifier", "NOTIFIER")
+
 	logBinding("slack_that.url", "SLACK_THAT_URL")
 	logBinding("slack_that.username", "SLACK_THAT_USERNAME")
+	logBinding("slack_that.staff_channel", "SLACK_THAT_STAFF_CHANNEL")
+
+	logBinding("slack.url", "SLACK_URL")
+	logBinding("slack.staff_channel", "SLACK_STAFF_CHANNEL")
 
 	logBinding("rabbitmq.host", "RABBITMQ_HOST")
 	logBinding("rabbitmq.port", "RABBITMQ_PORT")
@@ -122,11 +169,23 @@ func bindEnv() {
 	logBinding("sentry.dsn", "SENTRY_DSN")
 
 	logBinding("postgres.password", "POSTGRES_PASSWORD")
+	logBinding("intra.url", "INTRA_URL")
 	logBinding("intra.app_id", "INTRA_APP_ID")
 	logBinding("intra.app_secret", "INTRA_APP_SECRET")
 	logBinding("intra.webhooks", "INTRA_WEBHOOKS")
+	logBinding("intra.campus_id", "INTRA_CAMPUS_ID")
+	logBinding("intra.rate_limit", "INTRA_RATE_LIMIT")
+	logBinding("intra.hourly_limit", "INTRA_HOURLY_LIMIT")
+	logBinding("intra.max_retries", "INTRA_MAX_RETRIES")
+	logBinding("intra.token_refresh_skew", "INTRA_TOKEN_REFRESH_SKEW")
+	logBinding("intra.cache_ttl", "INTRA_CACHE_TTL")
 
 	logBinding("slack_that.workspace", "SLACK_THAT_WORKSPACE")
+	logBinding("slack.token", "SLACK_TOKEN")
+
+	logBinding("jitsi.room_secret", "JITSI_ROOM_SECRET")
+	logBinding("jitsi.jwt.app_secret", "JITSI_JWT_APP_SECRET")
+	logBinding("jitsi.events_secret", "JITSI_EVENTS_SECRET")
 
 }
 
@@ -146,6 +205,19 @@ func loadFile() {
 	log.WithField("config_file", filename).Infof("loaded config from: '%s'", filename)
 }
 
+// mapDeprecated maps the deprecated keys onto the ones replacing them, unless those are set too, and warns about them.
+func mapDeprecated() error {
+	if viper.GetString("warn_before") != "" {
+		warnBefore, err := time.ParseDuration(viper.GetString("warn_before"))
+		if err != nil {
+			return err
+		}
+		log.Warn("'warn_before' is deprecated: it is only used as the single offset of 'reminders' unless they are set")
+		viper.SetDefault("reminders", []time.Duration{warnBefore})
+	}
+	return nil
+}
+
 func unmarshalConfig() error {
 	decodeHook := mapstructure.ComposeDecodeHookFunc(
 		stringToMapstringHookFunc,
@@ -166,6 +238,9 @@ func Initiate() error {
 	if err := checkRequired(requiredConf...); err != nil {
 		return err
 	}
+	if err := mapDeprecated(); err != nil {
+		return err
+	}
 
 	Conf = Configuration{}
 	return unmarshalConfig()
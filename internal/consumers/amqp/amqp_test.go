@@ -7,6 +7,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/gustavobelfort/42-jitsi/internal/handler"
+	"github.com/gustavobelfort/42-jitsi/internal/intra"
 	"github.com/gustavobelfort/42-jitsi/internal/logging"
 	"github.com/sirupsen/logrus"
 	"github.com/streadway/amqp"
@@ -32,6 +34,15 @@ func (m *HandlerMock) HandleDestroy(ctx context.Context, data []byte) error {
 	return m.Called(ctx, data).Error(0)
 }
 
+func (m *HandlerMock) HandleScaleTeam(ctx context.Context, st *intra.ScaleTeam) error {
+	return m.Called(ctx, st).Error(0)
+}
+
+func (m *HandlerMock) Preview(ctx context.Context, st *intra.ScaleTeam) (handler.Change, error) {
+	toReturn := m.Called(ctx, st)
+	return toReturn.Get(0).(handler.Change), toReturn.Error(1)
+}
+
 type ChannelMock struct {
 	confirm chan struct{}
 	mock.Mock
@@ -401,6 +412,36 @@ func (s *TestAMQPSuite) Test10_BadEvent() {
 	s.cMock.Wait()
 }
 
+func (s *TestAMQPSuite) Test11_HandleCreate_WithDelivery() {
+	expectedBody := []byte(`{
+	"id": 21,
+	"team": {"id": "42"},
+	"user": {"login": "xlogin"},
+	"begin_at": "2020-05-05T16:00:00.051Z"
+}`)
+
+	expectedDelivery := amqp.Delivery{
+		Acknowledger: s.cMock,
+		Headers: amqp.Table{
+			"X-Model":    "scale_team",
+			"X-Event":    "create",
+			"X-Delivery": "delivery",
+		},
+		MessageCount: 0,
+		DeliveryTag:  42,
+		Body:         expectedBody,
+	}
+
+	withDelivery := mock.MatchedBy(func(ctx context.Context) bool {
+		return handler.ContextGetDelivery(ctx) == "delivery"
+	})
+	s.hMock.On("HandleCreate", withDelivery, expectedBody).Return(nil).Once()
+	s.cMock.On("Ack", expectedDelivery.DeliveryTag, false).Return(nil).Once()
+
+	go func() { s.deliveries <- expectedDelivery }()
+	s.cMock.Wait()
+}
+
 func (s *TestAMQPSuite) TearDownTest() {
 	s.cMock.AssertExpectations(s.T())
 	s.hMock.AssertExpectations(s.T())
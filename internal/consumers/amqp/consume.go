@@ -4,17 +4,20 @@ import (
 	"context"
 	"errors"
 
+	"github.com/gustavobelfort/42-jitsi/internal/handler"
 	"github.com/gustavobelfort/42-jitsi/internal/logging"
 	"github.com/sirupsen/logrus"
 	"github.com/streadway/amqp"
 )
 
 func messageContext(ctx context.Context, msg amqp.Delivery) context.Context {
-	return logging.ContextWithFields(ctx, logrus.Fields{
+	ctx = logging.ContextWithFields(ctx, logrus.Fields{
 		"model":       msg.Headers["X-Model"],
 		"event":       msg.Headers["X-Event"],
 		"delivery_id": msg.Headers["X-Delivery"],
 	})
+	delivery, _ := msg.Headers["X-Delivery"].(string)
+	return handler.ContextWithDelivery(ctx, delivery)
 }
 
 func validateMessage(msg amqp.Delivery) error {
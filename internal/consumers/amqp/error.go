@@ -4,6 +4,7 @@ import (
 	"errors"
 
 	"github.com/gustavobelfort/42-jitsi/internal/logging"
+	"github.com/gustavobelfort/42-jitsi/internal/resilient"
 	"github.com/sirupsen/logrus"
 	"github.com/streadway/amqp"
 )
@@ -23,7 +24,8 @@ var (
 
 func handleError(msg amqp.Delivery, err error) error {
 	logError := &logging.WithLogError{}
-	if errors.As(err, &logError) {
+	// An unavailable upstream is not the message's fault, thus it must not be acknowledged as handled.
+	if errors.As(err, &logError) && !resilient.IsUnavailable(err) {
 		if logError.LogLevel <= logrus.WarnLevel {
 			return msg.Ack(false)
 		}
@@ -20,7 +20,7 @@ func (r *Router) setupEngine(prefix string) {
 	// However some precautions were taken just in case.
 	//
 	// The context should be populated for log too.
-	group.POST("/webhooks", func(ctx *gin.Context) {
+	group.POST("/webhooks", r.validationMiddleware(), func(ctx *gin.Context) {
 		rCtx := ctx.Request.Context()
 		ctxlogger := logging.ContextLog(rCtx, logrus.StandardLogger())
 
@@ -49,4 +49,30 @@ func (r *Router) setupEngine(prefix string) {
 
 		ctx.Status(http.StatusNoContent)
 	})
+
+	if r.jitsiHandler == nil {
+		return
+	}
+
+	// Jitsi events receiving part, used to keep track of the meetings' attendance.
+	group.POST("/jitsi/events", r.jitsiAuthMiddleware(), func(ctx *gin.Context) {
+		rCtx := ctx.Request.Context()
+		ctxlogger := logging.ContextLog(rCtx, logrus.StandardLogger())
+
+		body, err := ioutil.ReadAll(ctx.Request.Body)
+		if err != nil {
+			ctxlogger.Errorf("while reading the request's body: %v", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error.", "details": nil})
+			return
+		}
+
+		err = r.jitsiHandler.HandleEvent(rCtx, body)
+		logging.LogError(ctxlogger, err, "while handling jitsi event")
+		if err != nil {
+			handleError(ctx, err)
+			return
+		}
+
+		ctx.Status(http.StatusNoContent)
+	})
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/gustavobelfort/42-jitsi/internal/logging"
+	"github.com/gustavobelfort/42-jitsi/internal/resilient"
 	"github.com/sirupsen/logrus"
 )
 
@@ -15,6 +16,11 @@ var (
 )
 
 func handleError(ctx *gin.Context, err error) {
+	// An unavailable upstream is not the payload's fault: the sender should deliver it again later.
+	if resilient.IsUnavailable(err) {
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": "Service unavailable.", "details": nil})
+		return
+	}
 	logError := &logging.WithLogError{}
 	if errors.As(err, &logError) {
 		if logError.LogLevel <= logrus.WarnLevel {
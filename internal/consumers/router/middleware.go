@@ -2,10 +2,12 @@ package router
 
 import (
 	"context"
+	"crypto/subtle"
 	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gustavobelfort/42-jitsi/internal/handler"
 	"github.com/gustavobelfort/42-jitsi/internal/logging"
 	"github.com/sirupsen/logrus"
 )
@@ -33,7 +35,7 @@ func (r *Router) contextMiddleware() gin.HandlerFunc {
 		})
 		rCtx, cancel := context.WithTimeout(ctx, r.timeout)
 		defer cancel()
-		ctx.Request = ctx.Request.WithContext(rCtx)
+		ctx.Request = ctx.Request.WithContext(handler.ContextWithDelivery(rCtx, delivery))
 		ctx.Next()
 	}
 }
@@ -83,10 +85,23 @@ func (r *Router) validationMiddleware() gin.HandlerFunc {
 	}
 }
 
+// jitsiAuthMiddleware verifies that the request is authenticated with the jitsi events' bearer secret.
+func (r *Router) jitsiAuthMiddleware() gin.HandlerFunc {
+	expected := []byte("Bearer " + r.jitsiSecret)
+	return func(ctx *gin.Context) {
+		ctxlogger := logging.ContextLog(ctx.Request.Context(), logrus.StandardLogger())
+
+		if subtle.ConstantTimeCompare([]byte(ctx.GetHeader("Authorization")), expected) != 1 {
+			ctxlogger.Warn("unauthorized jitsi event: denying request")
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized."})
+			return
+		}
+	}
+}
+
 func (r *Router) setupMiddlewares(group *gin.RouterGroup) {
 	group.Use(
 		r.recoverMiddleware(),
 		r.contextMiddleware(),
-		r.validationMiddleware(),
 	)
 }
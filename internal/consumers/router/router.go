@@ -24,13 +24,28 @@ type Router struct {
 
 	registries map[string]string
 
+	jitsiHandler handler.JitsiEventHandler
+	jitsiSecret  string
+
 	mu     *sync.Mutex
 	ctx    context.Context
 	cancel context.CancelFunc
 }
 
+// Option configures optional features of the router.
+type Option func(*Router)
+
+// JitsiEventsOption enables the `/jitsi/events` endpoint, receiving the events of the jitsi server authenticated with
+// the given bearer secret.
+func JitsiEventsOption(hdl handler.JitsiEventHandler, secret string) Option {
+	return func(router *Router) {
+		router.jitsiHandler = hdl
+		router.jitsiSecret = secret
+	}
+}
+
 // NewRouter returns a new router consumer.
-func NewRouter(server *http.Server, hdl handler.ScaleTeamHandler, registries map[string]string, prefix string, timeout time.Duration) consumers.Consumer {
+func NewRouter(server *http.Server, hdl handler.ScaleTeamHandler, registries map[string]string, prefix string, timeout time.Duration, options ...Option) consumers.Consumer {
 	router := &Router{
 		engine:  nil,
 		server:  server,
@@ -42,6 +57,9 @@ func NewRouter(server *http.Server, hdl handler.ScaleTeamHandler, registries map
 
 		mu: new(sync.Mutex),
 	}
+	for _, option := range options {
+		option(router)
+	}
 	router.setupEngine(prefix)
 	return router
 }
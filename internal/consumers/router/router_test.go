@@ -9,7 +9,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/gustavobelfort/42-jitsi/internal/handler"
+	"github.com/gustavobelfort/42-jitsi/internal/intra"
 	"github.com/gustavobelfort/42-jitsi/internal/logging"
+	"github.com/gustavobelfort/42-jitsi/internal/resilient"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -33,6 +36,23 @@ func (m *HandlerMock) HandleDestroy(ctx context.Context, data []byte) error {
 	return m.Called(ctx, data).Error(0)
 }
 
+func (m *HandlerMock) HandleScaleTeam(ctx context.Context, st *intra.ScaleTeam) error {
+	return m.Called(ctx, st).Error(0)
+}
+
+func (m *HandlerMock) Preview(ctx context.Context, st *intra.ScaleTeam) (handler.Change, error) {
+	toReturn := m.Called(ctx, st)
+	return toReturn.Get(0).(handler.Change), toReturn.Error(1)
+}
+
+type JitsiHandlerMock struct {
+	mock.Mock
+}
+
+func (m *JitsiHandlerMock) HandleEvent(ctx context.Context, data []byte) error {
+	return m.Called(ctx, data).Error(0)
+}
+
 func TestRouter(t *testing.T) {
 	t.Run("TestRouter_StartStop", func(t *testing.T) {
 		server := &http.Server{
@@ -72,6 +92,7 @@ type TestRouterSuite struct {
 	suite.Suite
 
 	mock       *HandlerMock
+	jitsiMock  *JitsiHandlerMock
 	registries map[string]string
 
 	listener net.Listener
@@ -86,6 +107,7 @@ func (s *TestRouterSuite) SetupSuite() {
 	s.Require().NoError(err)
 
 	s.mock = &HandlerMock{}
+	s.jitsiMock = &JitsiHandlerMock{}
 	s.registries = map[string]string{
 		"scale_team.create":  "create_secret",
 		"scale_team.update":  "update_secret",
@@ -98,7 +120,7 @@ func (s *TestRouterSuite) SetupSuite() {
 		ReadTimeout:  time.Second * 10,
 		WriteTimeout: time.Second * 10,
 		IdleTimeout:  time.Second * 10,
-	}, s.mock, s.registries, "/", time.Second*10).(*Router)
+	}, s.mock, s.registries, "/", time.Second*10, JitsiEventsOption(s.jitsiMock, "jitsi_secret")).(*Router)
 
 	s.stop = make(chan error)
 	go func(c chan<- error, r *Router) { c <- r.start(s.listener) }(s.stop, s.router)
@@ -115,6 +137,8 @@ func (s *TestRouterSuite) SetupTest() {
 
 	s.mock.Calls = []mock.Call{}
 	s.mock.ExpectedCalls = []*mock.Call{}
+	s.jitsiMock.Calls = []mock.Call{}
+	s.jitsiMock.ExpectedCalls = []*mock.Call{}
 }
 
 func (s *TestRouterSuite) Test00_CreateWebhook() {
@@ -305,8 +329,115 @@ func (s *TestRouterSuite) Test07_UnknownEventWebhook() {
 	s.Equal(http.StatusNotFound, resp.StatusCode)
 }
 
+// emitJitsiEvent posts the given event to the jitsi events endpoint the way the prosody event sync module does.
+func (s *TestRouterSuite) emitJitsiEvent(body []byte, secret string) *http.Response {
+	request, err := http.NewRequest(http.MethodPost, "http://"+s.listener.Addr().String()+"/jitsi/events", bytes.NewBuffer(body))
+	s.Require().NoError(err)
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Authorization", "Bearer "+secret)
+
+	resp, err := http.DefaultClient.Do(request)
+	s.Require().NoError(err)
+	return resp
+}
+
+func (s *TestRouterSuite) Test08_JitsiEvent() {
+	body := []byte(`{
+	"event_name": "muc-occupant-joined",
+	"room_name": "bd28ee142ca5b46259f6e27fc3a4216f",
+	"occupant": {"id": "xlogin", "name": "Xavier Login", "joined_at": 1594846800}
+}`)
+
+	s.jitsiMock.On("HandleEvent", mock.Anything, body).Return(nil).Once()
+
+	resp := s.emitJitsiEvent(body, "jitsi_secret")
+	s.Equal(http.StatusNoContent, resp.StatusCode)
+}
+
+func (s *TestRouterSuite) Test09_JitsiEvent_Unauthorized() {
+	body := []byte(`{"event_name": "muc-room-destroyed", "room_name": "bd28ee142ca5b46259f6e27fc3a4216f"}`)
+
+	resp := s.emitJitsiEvent(body, "wrong_secret")
+	s.Equal(http.StatusUnauthorized, resp.StatusCode)
+}
+
+func (s *TestRouterSuite) Test10_JitsiEvent_BadPayload() {
+	body := []byte(`{"room_name": "bd28ee142ca5b46259f6e27fc3a4216f"}`)
+
+	expectedErr := logging.WithLog(errors.New("testing"), logrus.WarnLevel, nil)
+	s.jitsiMock.On("HandleEvent", mock.Anything, body).Return(expectedErr).Once()
+
+	resp := s.emitJitsiEvent(body, "jitsi_secret")
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *TestRouterSuite) Test11_JitsiEvent_InternalServerError() {
+	body := []byte(`{"event_name": "muc-room-destroyed", "room_name": "bd28ee142ca5b46259f6e27fc3a4216f"}`)
+
+	s.jitsiMock.On("HandleEvent", mock.Anything, body).Return(errors.New("testing")).Once()
+
+	resp := s.emitJitsiEvent(body, "jitsi_secret")
+	s.Equal(http.StatusInternalServerError, resp.StatusCode)
+}
+
+func (s *TestRouterSuite) Test12_CreateWebhook_WithDelivery() {
+	body := []byte(`{
+	"id": 21,
+	"team": {"id": "42"},
+	"user": {"login": "xlogin"},
+	"begin_at": "2020-05-05T16:00:00.051Z"
+}`)
+	buffer := bytes.NewBuffer(body)
+
+	request, err := http.NewRequest(http.MethodPost, "http://"+s.listener.Addr().String()+"/webhooks", buffer)
+	s.Require().NoError(err)
+
+	request.Header.Set("X-Model", "scale_team")
+	request.Header.Set("X-Event", "create")
+	request.Header.Set("X-Delivery", "delivery")
+	request.Header.Set("X-Secret", s.registries["scale_team.create"])
+
+	withDelivery := mock.MatchedBy(func(ctx context.Context) bool {
+		return handler.ContextGetDelivery(ctx) == "delivery"
+	})
+	s.mock.On("HandleCreate", withDelivery, body).Return(nil).Once()
+
+	resp, err := http.DefaultClient.Do(request)
+	s.Require().NoError(err)
+
+	s.Equal(http.StatusNoContent, resp.StatusCode)
+}
+
+func (s *TestRouterSuite) Test13_UpdateWebhook_UpstreamUnavailable() {
+	body := []byte(`{
+	"id": 21,
+	"team": {"id": "42"},
+	"user": {"login": "xlogin"},
+	"begin_at": "2020-05-05T16:00:00.051Z"
+}`)
+	buffer := bytes.NewBuffer(body)
+
+	request, err := http.NewRequest(http.MethodPost, "http://"+s.listener.Addr().String()+"/webhooks", buffer)
+	s.Require().NoError(err)
+
+	request.Header.Set("X-Model", "scale_team")
+	request.Header.Set("X-Event", "update")
+	request.Header.Set("X-Secret", s.registries["scale_team.update"])
+
+	// The team members could not be fetched from the intra: the event is to be delivered again.
+	expectedErr := logging.WithLog(&resilient.UnavailableError{Upstream: "api.intra.42.fr"}, logrus.ErrorLevel, nil)
+	s.mock.On("HandleUpdate", mock.Anything, body).Return(expectedErr).Once()
+
+	resp, err := http.DefaultClient.Do(request)
+	s.Require().NoError(err)
+
+	s.Equal(http.StatusServiceUnavailable, resp.StatusCode)
+}
+
 func (s *TestRouterSuite) TearDownTest() {
 	s.mock.AssertExpectations(s.T())
+	s.jitsiMock.AssertExpectations(s.T())
 }
 
 func (s *TestRouterSuite) TearDownSuite() {
@@ -9,10 +9,8 @@ import (
 	_ "github.com/jinzhu/gorm/dialects/postgres"
 )
 
-// Init database environment. Creates the database connection and initiates the models managers.
-//
-// Returns err and do not initiate anything on error.
-func Init() error {
+// Open creates the database connection.
+func Open() (*gorm.DB, error) {
 	pgConf := config.Conf.Postgres
 	url := fmt.Sprintf(
 		"postgres://%s:%s@%s:%s/%s?sslmode=disable",
@@ -22,24 +20,42 @@ func Init() error {
 		pgConf.Port,
 		pgConf.DB,
 	)
-	db, err := gorm.Open("postgres", url)
+	return gorm.Open("postgres", url)
+}
+
+// Init database environment. Creates the database connection, verifies that its schema is up to date and initiates
+// the models managers. The schema is migrated with the migrate command.
+//
+// Returns err and do not initiate anything on error.
+func Init() error {
+	db, err := Open()
 	if err != nil {
 		return err
 	}
-	if err := db.AutoMigrate(&userModel{}, &scaleTeamModel{}).Error; err != nil {
-		return err
-	}
-	if err := db.Model(&userModel{}).AddForeignKey("scale_team_id", "scale_teams(id)", "CASCADE", "CASCADE").Error; err != nil {
+	if err := CheckSchemaVersion(db.DB()); err != nil {
+		db.Close()
 		return err
 	}
 	GlobalScaleTeamManager = NewScaleTeamManager(db)
 	GlobalUserManager = NewUserManager(db)
+	GlobalReminderManager = NewReminderManager(db)
+	GlobalAttendanceManager = NewAttendanceManager(db)
+	GlobalNoShowManager = NewNoShowManager(db)
+	GlobalDeliveryManager = NewDeliveryManager(db)
+	GlobalTombstoneManager = NewTombstoneManager(db)
+	GlobalProfileManager = NewProfileManager(db)
 	GlobalDB = db
 	return nil
 }
 
 var (
-	GlobalScaleTeamManager ScaleTeamManager = nil
-	GlobalUserManager      UserManager      = nil
-	GlobalDB               *gorm.DB         = nil
+	GlobalScaleTeamManager  ScaleTeamManager  = nil
+	GlobalUserManager       UserManager       = nil
+	GlobalReminderManager   ReminderManager   = nil
+	GlobalAttendanceManager AttendanceManager = nil
+	GlobalNoShowManager     NoShowManager     = nil
+	GlobalDeliveryManager   DeliveryManager   = nil
+	GlobalTombstoneManager  TombstoneManager  = nil
+	GlobalProfileManager    ProfileManager    = nil
+	GlobalDB                *gorm.DB          = nil
 )
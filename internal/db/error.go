@@ -0,0 +1,62 @@
+package db
+
+import "fmt"
+
+// SchemaVersionError is returned when the database schema is not at the version expected by the binary.
+type SchemaVersionError struct {
+	current  int
+	expected int
+}
+
+// Error formats the SchemaVersionError with the current and expected versions.
+func (err *SchemaVersionError) Error() string {
+	return fmt.Sprintf(
+		"the database schema is at version %d while version %d is expected: run the migrate command",
+		err.current,
+		err.expected,
+	)
+}
+
+// UnknownMigrationError is returned when reverting a migration applied by a more recent binary.
+type UnknownMigrationError struct {
+	version int
+}
+
+// Error formats the UnknownMigrationError with the version of the migration.
+func (err *UnknownMigrationError) Error() string {
+	return fmt.Sprintf("unknown migration version %d: it was applied by a more recent version", err.version)
+}
+
+// PendingBackfillError is returned when some records still miss the data that a backfill of the migrate command
+// generates.
+type PendingBackfillError struct {
+	Backfill string
+	Count    int
+}
+
+// Error formats the PendingBackfillError with the backfill and the number of records pending it.
+func (err *PendingBackfillError) Error() string {
+	return fmt.Sprintf("%d scale teams are pending the %s backfill: run the migrate command", err.Count, err.Backfill)
+}
+
+// ScaleTeamExistsError is returned when creating a scale team which was created in the meantime, e.g: by a concurrent
+// event.
+type ScaleTeamExistsError struct {
+	ID int
+}
+
+// Error formats the ScaleTeamExistsError with the id of the scale team.
+func (err *ScaleTeamExistsError) Error() string {
+	return fmt.Sprintf("the scale team %d already exists", err.ID)
+}
+
+// GoneError is returned when updating a record which was deleted in the meantime.
+type GoneError struct {
+	Table string
+	ID    int
+}
+
+// Error formats the GoneError with the table and the id of the record.
+func (err *GoneError) Error() string {
+	return fmt.Sprintf("the %s record %d was deleted", err.Table, err.ID)
+}
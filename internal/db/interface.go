@@ -15,12 +15,28 @@ var (
 	Corrector UserStatus = "corrector"
 )
 
+// Meeting describes the video conference room of a scale team.
+type Meeting struct {
+	Provider string
+	RoomName string
+	URL      string
+}
+
+// IsZero reports whether the meeting was never generated.
+func (meeting Meeting) IsZero() bool {
+	return meeting == Meeting{}
+}
+
 // ScaleTeamManager will be a wrapper to manage ScaleTeams in the database.
 //
 // It shall be used by a constant "GlobalScaleTeamManager".
 type ScaleTeamManager interface {
-	Create(tx *gorm.DB, id int, beginAt time.Time, notified bool) (ScaleTeam, error)
+	// Create inserts the scale team. It returns a ScaleTeamExistsError if it was created in the meantime.
+	Create(tx *gorm.DB, id int, beginAt, intraUpdatedAt time.Time, meeting Meeting) (ScaleTeam, error)
 	Update(tx *gorm.DB, scaleTeam ScaleTeam) error
+	// SetAttendanceChecked only updates the attendance_checked column of the scale team, so that the concurrent
+	// changes of its other columns are kept. It returns a GoneError if the scale team was deleted.
+	SetAttendanceChecked(tx *gorm.DB, id int) error
 	Delete(tx *gorm.DB, scaleTeam ScaleTeam) error
 	Get(tx *gorm.DB, options ...GetOption) ([]ScaleTeam, error)
 
@@ -39,6 +55,82 @@ type UserManager interface {
 	DB() *gorm.DB
 }
 
+// ReminderManager will be a wrapper to manage Reminders in the database.
+//
+// It shall be used by a constant "GlobalReminderManager".
+type ReminderManager interface {
+	Create(tx *gorm.DB, scaleTeamID int, remindBefore time.Duration, remindAt time.Time) (Reminder, error)
+	Update(tx *gorm.DB, reminder Reminder) error
+	// SetSent only updates the sent_at column of the reminder. It returns a GoneError if the reminder was deleted,
+	// e.g: when the scale team was rescheduled.
+	SetSent(tx *gorm.DB, id int, sentAt time.Time) error
+	Delete(tx *gorm.DB, reminder Reminder) error
+	Get(tx *gorm.DB, options ...GetOption) ([]Reminder, error)
+
+	DB() *gorm.DB
+}
+
+// AttendanceManager will be a wrapper to manage Attendances in the database.
+//
+// It shall be used by a constant "GlobalAttendanceManager".
+type AttendanceManager interface {
+	Create(tx *gorm.DB, scaleTeamID int, login string, joinedAt time.Time) (Attendance, error)
+	Update(tx *gorm.DB, attendance Attendance) error
+	Delete(tx *gorm.DB, attendance Attendance) error
+	Get(tx *gorm.DB, options ...GetOption) ([]Attendance, error)
+
+	DB() *gorm.DB
+}
+
+// NoShowManager will be a wrapper to manage NoShows in the database.
+//
+// It shall be used by a constant "GlobalNoShowManager".
+type NoShowManager interface {
+	Create(tx *gorm.DB, scaleTeamID int, login string, status UserStatus, beginAt time.Time) (NoShow, error)
+	Update(tx *gorm.DB, noShow NoShow) error
+	Delete(tx *gorm.DB, noShow NoShow) error
+	Get(tx *gorm.DB, options ...GetOption) ([]NoShow, error)
+
+	DB() *gorm.DB
+}
+
+// DeliveryManager keeps track of the webhooks' deliveries which were already processed.
+//
+// It shall be used by a constant "GlobalDeliveryManager".
+type DeliveryManager interface {
+	// Record marks the delivery as processed. It returns false if it already was.
+	Record(tx *gorm.DB, id string, processedAt time.Time) (bool, error)
+	// Purge forgets the deliveries processed before `before`. It returns the number of forgotten deliveries.
+	Purge(tx *gorm.DB, before time.Time) (int64, error)
+
+	DB() *gorm.DB
+}
+
+// TombstoneManager will be a wrapper to manage Tombstones in the database.
+//
+// It shall be used by a constant "GlobalTombstoneManager".
+type TombstoneManager interface {
+	Create(tx *gorm.DB, scaleTeamID int, intraUpdatedAt, destroyedAt time.Time) (Tombstone, error)
+	Update(tx *gorm.DB, tombstone Tombstone) error
+	Delete(tx *gorm.DB, tombstone Tombstone) error
+	Get(tx *gorm.DB, options ...GetOption) ([]Tombstone, error)
+
+	DB() *gorm.DB
+}
+
+// ProfileManager caches the profiles of the intra users, so that every process shares them.
+//
+// It shall be used by a constant "GlobalProfileManager".
+type ProfileManager interface {
+	// Store creates or replaces the cached profile of the user.
+	Store(tx *gorm.DB, login, email string, fetchedAt time.Time) error
+	// Invalidate forgets the cached profile of the user. It returns false if there was none.
+	Invalidate(tx *gorm.DB, login string) (bool, error)
+	Get(tx *gorm.DB, options ...GetOption) ([]Profile, error)
+
+	DB() *gorm.DB
+}
+
 // ManagedModel is a base interface for managed data models.
 type ManagedModel interface {
 	// Delete the data inheriting this model.
@@ -49,16 +141,22 @@ type ManagedModel interface {
 }
 
 // ScaleTeam and manages wraps the scale_teams records.
+//
+// A scale team is notified once any of its reminders was sent, see `ScaleTeamNotifiedOption`.
 type ScaleTeam interface {
 	GetID() int
 	GetBeginAt() time.Time
-	GetNotified() bool
+	GetMeeting() Meeting
+	GetAttendanceChecked() bool
+	GetIntraUpdatedAt() time.Time
 
 	Get(tx *gorm.DB, options ...GetOption) ([]User, error)
 
 	SetID(int)
 	SetBeginAt(time.Time)
-	SetNotified(bool)
+	SetMeeting(Meeting)
+	SetAttendanceChecked(bool)
+	SetIntraUpdatedAt(time.Time)
 
 	ManagedModel
 }
@@ -76,3 +174,69 @@ type User interface {
 
 	ManagedModel
 }
+
+// Reminder wraps and manages the reminders records.
+//
+// A reminder is a notification planned `RemindBefore` the beginning of a scale team.
+type Reminder interface {
+	GetID() int
+	GetScaleTeamID() int
+	GetRemindBefore() time.Duration
+	GetRemindAt() time.Time
+	GetSentAt() *time.Time
+
+	SetRemindAt(time.Time)
+	SetSentAt(*time.Time)
+
+	ManagedModel
+}
+
+// Attendance wraps and manages the attendances records.
+//
+// An attendance is the presence of a user in the meeting of a scale team, from the moment they joined until they left.
+type Attendance interface {
+	GetID() int
+	GetScaleTeamID() int
+	GetLogin() string
+	GetJoinedAt() time.Time
+	GetLeftAt() *time.Time
+
+	SetLeftAt(*time.Time)
+
+	ManagedModel
+}
+
+// NoShow wraps and manages the no_shows records.
+//
+// A no-show is a participant of a scale team who never joined its meeting.
+type NoShow interface {
+	GetID() int
+	GetScaleTeamID() int
+	GetLogin() string
+	GetStatus() UserStatus
+	GetBeginAt() time.Time
+
+	ManagedModel
+}
+
+// Tombstone wraps and manages the tombstones records.
+//
+// A tombstone remembers a destroyed scale team, so that the intra events older than its destruction are ignored.
+type Tombstone interface {
+	GetScaleTeamID() int
+	GetIntraUpdatedAt() time.Time
+	GetDestroyedAt() time.Time
+
+	SetIntraUpdatedAt(time.Time)
+
+	ManagedModel
+}
+
+// Profile wraps the profiles records.
+//
+// A profile is what is cached of an intra user, as it was when fetched from the API at `FetchedAt`.
+type Profile interface {
+	GetLogin() string
+	GetEmail() string
+	GetFetchedAt() time.Time
+}
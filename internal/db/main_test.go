@@ -28,7 +28,7 @@ func (sMock *ScaleTeamManagerMock) DB() *gorm.DB {
 	return nil
 }
 
-func (sMock *ScaleTeamManagerMock) Create(_ *gorm.DB, _ int, _ time.Time, _ bool) (ScaleTeam, error) {
+func (sMock *ScaleTeamManagerMock) Create(_ *gorm.DB, _ int, _, _ time.Time, _ Meeting) (ScaleTeam, error) {
 	sMock.Called()
 	return nil, nil
 }
@@ -42,6 +42,10 @@ func (sMock *ScaleTeamManagerMock) Update(tx *gorm.DB, scaleTeam ScaleTeam) erro
 	return sMock.Called(tx, scaleTeam).Error(0)
 }
 
+func (sMock *ScaleTeamManagerMock) SetAttendanceChecked(tx *gorm.DB, id int) error {
+	return sMock.Called(tx, id).Error(0)
+}
+
 func (sMock *ScaleTeamManagerMock) Delete(tx *gorm.DB, scaleTeam ScaleTeam) error {
 	return sMock.Called(tx, scaleTeam).Error(0)
 }
@@ -52,7 +56,7 @@ func TestScaleTeamModel(t *testing.T) {
 	var (
 		expectedID      = 1
 		expectedBeginAt = time.Now()
-		expectedNotifed = true
+		expectedMeeting = Meeting{Provider: "jitsi", RoomName: "room", URL: "https://meet.jit.si/room"}
 	)
 
 	scaleTeam := &scaleTeamModel{}
@@ -61,11 +65,18 @@ func TestScaleTeamModel(t *testing.T) {
 
 	scaleTeam.SetID(expectedID)
 	scaleTeam.SetBeginAt(expectedBeginAt)
-	scaleTeam.SetNotified(expectedNotifed)
+	assert.False(scaleTeam.GetAttendanceChecked())
+	scaleTeam.SetAttendanceChecked(true)
+	assert.True(scaleTeam.GetMeeting().IsZero())
+	scaleTeam.SetMeeting(expectedMeeting)
+	assert.True(scaleTeam.GetIntraUpdatedAt().IsZero())
+	scaleTeam.SetIntraUpdatedAt(expectedBeginAt)
 
 	assert.Equal(expectedID, scaleTeam.GetID())
 	assert.Equal(expectedBeginAt, scaleTeam.GetBeginAt())
-	assert.Equal(expectedNotifed, scaleTeam.GetNotified())
+	assert.Equal(expectedMeeting, scaleTeam.GetMeeting())
+	assert.True(scaleTeam.GetAttendanceChecked())
+	assert.Equal(expectedBeginAt, scaleTeam.GetIntraUpdatedAt())
 
 	expectedError := errors.New("testing error")
 
@@ -158,3 +169,303 @@ func TestUserModel(t *testing.T) {
 
 	mock.AssertExpectations(t)
 }
+
+type ReminderManagerMock struct {
+	mock.Mock
+}
+
+func (sMock *ReminderManagerMock) DB() *gorm.DB {
+	sMock.Called()
+	return nil
+}
+
+func (sMock *ReminderManagerMock) Create(_ *gorm.DB, _ int, _ time.Duration, _ time.Time) (Reminder, error) {
+	sMock.Called()
+	return nil, nil
+}
+
+func (sMock *ReminderManagerMock) Get(_ *gorm.DB, _ ...GetOption) ([]Reminder, error) {
+	sMock.Called()
+	return nil, nil
+}
+
+func (sMock *ReminderManagerMock) Update(tx *gorm.DB, reminder Reminder) error {
+	return sMock.Called(tx, reminder).Error(0)
+}
+
+func (sMock *ReminderManagerMock) SetSent(tx *gorm.DB, id int, sentAt time.Time) error {
+	return sMock.Called(tx, id, sentAt).Error(0)
+}
+
+func (sMock *ReminderManagerMock) Delete(tx *gorm.DB, reminder Reminder) error {
+	return sMock.Called(tx, reminder).Error(0)
+}
+
+func TestReminderModel(t *testing.T) {
+	assert := assert.New(t)
+
+	var (
+		expectedID           = 1
+		expectedScaleTeamID  = 2
+		expectedRemindBefore = time.Hour
+		expectedRemindAt     = time.Now()
+		expectedSentAt       = time.Now()
+	)
+
+	reminder := &reminderModel{
+		ID:           expectedID,
+		ScaleTeamID:  expectedScaleTeamID,
+		RemindBefore: expectedRemindBefore,
+	}
+
+	assert.Implements((*Reminder)(nil), reminder)
+
+	assert.Nil(reminder.GetSentAt())
+	reminder.SetRemindAt(expectedRemindAt)
+	reminder.SetSentAt(&expectedSentAt)
+
+	assert.Equal(expectedID, reminder.GetID())
+	assert.Equal(expectedScaleTeamID, reminder.GetScaleTeamID())
+	assert.Equal(expectedRemindBefore, reminder.GetRemindBefore())
+	assert.Equal(expectedRemindAt, reminder.GetRemindAt())
+	assert.Equal(&expectedSentAt, reminder.GetSentAt())
+
+	expectedError := errors.New("testing error")
+
+	mock := &ReminderManagerMock{}
+	reminder.reminderManager = mock
+
+	db, _, err := sqlmock.New()
+	require.NoError(t, err)
+
+	tx, err := gorm.Open("postgres", db)
+	require.NoError(t, err)
+
+	mock.On("Update", tx, reminder).Return(expectedError)
+	mock.On("Delete", tx, reminder).Return(expectedError)
+
+	assert.Equal(expectedError, reminder.Save(tx))
+	assert.Equal(expectedError, reminder.Delete(tx))
+
+	mock.AssertExpectations(t)
+}
+
+type AttendanceManagerMock struct {
+	mock.Mock
+}
+
+func (sMock *AttendanceManagerMock) DB() *gorm.DB {
+	sMock.Called()
+	return nil
+}
+
+func (sMock *AttendanceManagerMock) Create(_ *gorm.DB, _ int, _ string, _ time.Time) (Attendance, error) {
+	sMock.Called()
+	return nil, nil
+}
+
+func (sMock *AttendanceManagerMock) Get(_ *gorm.DB, _ ...GetOption) ([]Attendance, error) {
+	sMock.Called()
+	return nil, nil
+}
+
+func (sMock *AttendanceManagerMock) Update(tx *gorm.DB, attendance Attendance) error {
+	return sMock.Called(tx, attendance).Error(0)
+}
+
+func (sMock *AttendanceManagerMock) Delete(tx *gorm.DB, attendance Attendance) error {
+	return sMock.Called(tx, attendance).Error(0)
+}
+
+func TestAttendanceModel(t *testing.T) {
+	assert := assert.New(t)
+
+	var (
+		expectedID          = 1
+		expectedScaleTeamID = 2
+		expectedLogin       = "xlogin"
+		expectedJoinedAt    = time.Now()
+		expectedLeftAt      = time.Now()
+	)
+
+	attendance := &attendanceModel{
+		ID:          expectedID,
+		ScaleTeamID: expectedScaleTeamID,
+		Login:       expectedLogin,
+		JoinedAt:    expectedJoinedAt,
+	}
+
+	assert.Implements((*Attendance)(nil), attendance)
+
+	assert.Nil(attendance.GetLeftAt())
+	attendance.SetLeftAt(&expectedLeftAt)
+
+	assert.Equal(expectedID, attendance.GetID())
+	assert.Equal(expectedScaleTeamID, attendance.GetScaleTeamID())
+	assert.Equal(expectedLogin, attendance.GetLogin())
+	assert.Equal(expectedJoinedAt, attendance.GetJoinedAt())
+	assert.Equal(&expectedLeftAt, attendance.GetLeftAt())
+
+	expectedError := errors.New("testing error")
+
+	mock := &AttendanceManagerMock{}
+	attendance.attendanceManager = mock
+
+	db, _, err := sqlmock.New()
+	require.NoError(t, err)
+
+	tx, err := gorm.Open("postgres", db)
+	require.NoError(t, err)
+
+	mock.On("Update", tx, attendance).Return(expectedError)
+	mock.On("Delete", tx, attendance).Return(expectedError)
+
+	assert.Equal(expectedError, attendance.Save(tx))
+	assert.Equal(expectedError, attendance.Delete(tx))
+
+	mock.AssertExpectations(t)
+}
+
+type NoShowManagerMock struct {
+	mock.Mock
+}
+
+func (sMock *NoShowManagerMock) DB() *gorm.DB {
+	sMock.Called()
+	return nil
+}
+
+func (sMock *NoShowManagerMock) Create(_ *gorm.DB, _ int, _ string, _ UserStatus, _ time.Time) (NoShow, error) {
+	sMock.Called()
+	return nil, nil
+}
+
+func (sMock *NoShowManagerMock) Get(_ *gorm.DB, _ ...GetOption) ([]NoShow, error) {
+	sMock.Called()
+	return nil, nil
+}
+
+func (sMock *NoShowManagerMock) Update(tx *gorm.DB, noShow NoShow) error {
+	return sMock.Called(tx, noShow).Error(0)
+}
+
+func (sMock *NoShowManagerMock) Delete(tx *gorm.DB, noShow NoShow) error {
+	return sMock.Called(tx, noShow).Error(0)
+}
+
+func TestNoShowModel(t *testing.T) {
+	assert := assert.New(t)
+
+	var (
+		expectedID          = 1
+		expectedScaleTeamID = 2
+		expectedLogin       = "xlogin"
+		expectedStatus      = Corrector
+		expectedBeginAt     = time.Now()
+	)
+
+	noShow := &noShowModel{
+		ID:          expectedID,
+		ScaleTeamID: expectedScaleTeamID,
+		Login:       expectedLogin,
+		Status:      expectedStatus,
+		BeginAt:     expectedBeginAt,
+	}
+
+	assert.Implements((*NoShow)(nil), noShow)
+
+	assert.Equal(expectedID, noShow.GetID())
+	assert.Equal(expectedScaleTeamID, noShow.GetScaleTeamID())
+	assert.Equal(expectedLogin, noShow.GetLogin())
+	assert.Equal(expectedStatus, noShow.GetStatus())
+	assert.Equal(expectedBeginAt, noShow.GetBeginAt())
+
+	expectedError := errors.New("testing error")
+
+	mock := &NoShowManagerMock{}
+	noShow.noShowManager = mock
+
+	db, _, err := sqlmock.New()
+	require.NoError(t, err)
+
+	tx, err := gorm.Open("postgres", db)
+	require.NoError(t, err)
+
+	mock.On("Update", tx, noShow).Return(expectedError)
+	mock.On("Delete", tx, noShow).Return(expectedError)
+
+	assert.Equal(expectedError, noShow.Save(tx))
+	assert.Equal(expectedError, noShow.Delete(tx))
+
+	mock.AssertExpectations(t)
+}
+
+type TombstoneManagerMock struct {
+	mock.Mock
+}
+
+func (sMock *TombstoneManagerMock) DB() *gorm.DB {
+	sMock.Called()
+	return nil
+}
+
+func (sMock *TombstoneManagerMock) Create(_ *gorm.DB, _ int, _, _ time.Time) (Tombstone, error) {
+	sMock.Called()
+	return nil, nil
+}
+
+func (sMock *TombstoneManagerMock) Get(_ *gorm.DB, _ ...GetOption) ([]Tombstone, error) {
+	sMock.Called()
+	return nil, nil
+}
+
+func (sMock *TombstoneManagerMock) Update(tx *gorm.DB, tombstone Tombstone) error {
+	return sMock.Called(tx, tombstone).Error(0)
+}
+
+func (sMock *TombstoneManagerMock) Delete(tx *gorm.DB, tombstone Tombstone) error {
+	return sMock.Called(tx, tombstone).Error(0)
+}
+
+func TestTombstoneModel(t *testing.T) {
+	assert := assert.New(t)
+
+	var (
+		expectedScaleTeamID = 1
+		expectedVersion     = time.Now().Add(-time.Hour)
+		expectedDestroyedAt = time.Now()
+	)
+
+	tombstone := &tombstoneModel{
+		ScaleTeamID:    expectedScaleTeamID,
+		IntraUpdatedAt: time.Now().Add(-time.Hour * 2),
+		DestroyedAt:    expectedDestroyedAt,
+	}
+
+	assert.Implements((*Tombstone)(nil), tombstone)
+
+	tombstone.SetIntraUpdatedAt(expectedVersion)
+
+	assert.Equal(expectedScaleTeamID, tombstone.GetScaleTeamID())
+	assert.Equal(expectedVersion, tombstone.GetIntraUpdatedAt())
+	assert.Equal(expectedDestroyedAt, tombstone.GetDestroyedAt())
+
+	expectedError := errors.New("testing error")
+
+	mock := &TombstoneManagerMock{}
+	tombstone.tombstoneManager = mock
+
+	db, _, err := sqlmock.New()
+	require.NoError(t, err)
+
+	tx, err := gorm.Open("postgres", db)
+	require.NoError(t, err)
+
+	mock.On("Update", tx, tombstone).Return(expectedError)
+	mock.On("Delete", tx, tombstone).Return(expectedError)
+
+	assert.Equal(expectedError, tombstone.Save(tx))
+	assert.Equal(expectedError, tombstone.Delete(tx))
+
+	mock.AssertExpectations(t)
+}
@@ -24,18 +24,37 @@ func (stManager *scaleTeamManager) DB() *gorm.DB {
 	return stManager.db
 }
 
-func (stManager *scaleTeamManager) Create(tx *gorm.DB, id int, beginAt time.Time, notified bool) (ScaleTeam, error) {
+func (stManager *scaleTeamManager) Create(tx *gorm.DB, id int, beginAt, intraUpdatedAt time.Time, meeting Meeting) (ScaleTeam, error) {
 	scaleTeam := &scaleTeamModel{
-		ID:       id,
-		BeginAt:  beginAt,
-		Notified: notified,
+		ID:             id,
+		BeginAt:        beginAt,
+		RoomProvider:   meeting.Provider,
+		RoomName:       meeting.RoomName,
+		RoomURL:        meeting.URL,
+		IntraUpdatedAt: intraUpdatedAt,
 
 		scaleTeamManager: stManager,
 		userManager:      &userManager{db: stManager.db},
 	}
 
-	if err := tx.Create(scaleTeam).Error; err != nil {
-		return nil, err
+	// A concurrent creation waits for this one to be either committed or rolled back instead of failing on the
+	// primary key, which would abort the whole transaction.
+	result := tx.Exec(
+		`INSERT INTO "scale_teams" ("id","begin_at","room_provider","room_name","room_url","attendance_checked","intra_updated_at") `+
+			`VALUES (?,?,?,?,?,?,?) ON CONFLICT ("id") DO NOTHING`,
+		scaleTeam.ID,
+		scaleTeam.BeginAt,
+		scaleTeam.RoomProvider,
+		scaleTeam.RoomName,
+		scaleTeam.RoomURL,
+		scaleTeam.AttendanceChecked,
+		scaleTeam.IntraUpdatedAt,
+	)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, &ScaleTeamExistsError{ID: id}
 	}
 	return scaleTeam, nil
 }
@@ -44,6 +63,17 @@ func (stManager *scaleTeamManager) Update(tx *gorm.DB, scaleTeam ScaleTeam) erro
 	return tx.Save(scaleTeam).Error
 }
 
+func (stManager *scaleTeamManager) SetAttendanceChecked(tx *gorm.DB, id int) error {
+	result := tx.Model(&scaleTeamModel{}).Where("id = ?", id).UpdateColumn("attendance_checked", true)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return &GoneError{Table: "scale_teams", ID: id}
+	}
+	return nil
+}
+
 func (stManager *scaleTeamManager) Delete(tx *gorm.DB, scaleTeam ScaleTeam) error {
 	return tx.Delete(scaleTeam).Error
 }
@@ -127,3 +157,339 @@ func (uManager *userManager) Get(tx *gorm.DB, options ...GetOption) ([]User, err
 	}
 	return returned, nil
 }
+
+/*
+ * Reminders Manager
+ */
+
+type reminderManager struct {
+	db *gorm.DB
+}
+
+// NewReminderManager returns a new manager with the passed GlobalDB object.
+func NewReminderManager(db *gorm.DB) ReminderManager {
+	return &reminderManager{db: db}
+}
+
+// Returns the underlying database object.
+func (rManager *reminderManager) DB() *gorm.DB {
+	return rManager.db
+}
+
+func (rManager *reminderManager) Create(tx *gorm.DB, scaleTeamID int, remindBefore time.Duration, remindAt time.Time) (Reminder, error) {
+	reminder := &reminderModel{
+		ScaleTeamID:  scaleTeamID,
+		RemindBefore: remindBefore,
+		RemindAt:     remindAt,
+
+		reminderManager: rManager,
+	}
+	if err := tx.Create(reminder).Error; err != nil {
+		return nil, err
+	}
+	return reminder, nil
+}
+
+func (rManager *reminderManager) Update(tx *gorm.DB, reminder Reminder) error {
+	return tx.Save(reminder).Error
+}
+
+func (rManager *reminderManager) SetSent(tx *gorm.DB, id int, sentAt time.Time) error {
+	result := tx.Model(&reminderModel{}).Where("id = ?", id).UpdateColumn("sent_at", sentAt)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return &GoneError{Table: "reminders", ID: id}
+	}
+	return nil
+}
+
+func (rManager *reminderManager) Delete(tx *gorm.DB, reminder Reminder) error {
+	return tx.Delete(reminder).Error
+}
+
+func (rManager *reminderManager) Get(tx *gorm.DB, options ...GetOption) ([]Reminder, error) {
+	for _, opt := range options {
+		tx = opt(tx)
+	}
+	var reminders []reminderModel
+
+	if err := tx.Find(&reminders).Error; err != nil {
+		return nil, err
+	}
+
+	returned := make([]Reminder, len(reminders))
+	for i := range reminders {
+		reminders[i].reminderManager = rManager
+		returned[i] = &reminders[i]
+	}
+	return returned, nil
+}
+
+/*
+ * Attendances Manager
+ */
+
+type attendanceManager struct {
+	db *gorm.DB
+}
+
+// NewAttendanceManager returns a new manager with the passed GlobalDB object.
+func NewAttendanceManager(db *gorm.DB) AttendanceManager {
+	return &attendanceManager{db: db}
+}
+
+// Returns the underlying database object.
+func (aManager *attendanceManager) DB() *gorm.DB {
+	return aManager.db
+}
+
+func (aManager *attendanceManager) Create(tx *gorm.DB, scaleTeamID int, login string, joinedAt time.Time) (Attendance, error) {
+	attendance := &attendanceModel{
+		ScaleTeamID: scaleTeamID,
+		Login:       login,
+		JoinedAt:    joinedAt,
+
+		attendanceManager: aManager,
+	}
+	if err := tx.Create(attendance).Error; err != nil {
+		return nil, err
+	}
+	return attendance, nil
+}
+
+func (aManager *attendanceManager) Update(tx *gorm.DB, attendance Attendance) error {
+	return tx.Save(attendance).Error
+}
+
+func (aManager *attendanceManager) Delete(tx *gorm.DB, attendance Attendance) error {
+	return tx.Delete(attendance).Error
+}
+
+func (aManager *attendanceManager) Get(tx *gorm.DB, options ...GetOption) ([]Attendance, error) {
+	for _, opt := range options {
+		tx = opt(tx)
+	}
+	var attendances []attendanceModel
+
+	if err := tx.Find(&attendances).Error; err != nil {
+		return nil, err
+	}
+
+	returned := make([]Attendance, len(attendances))
+	for i := range attendances {
+		attendances[i].attendanceManager = aManager
+		returned[i] = &attendances[i]
+	}
+	return returned, nil
+}
+
+/*
+ * NoShows Manager
+ */
+
+type noShowManager struct {
+	db *gorm.DB
+}
+
+// NewNoShowManager returns a new manager with the passed GlobalDB object.
+func NewNoShowManager(db *gorm.DB) NoShowManager {
+	return &noShowManager{db: db}
+}
+
+// Returns the underlying database object.
+func (nsManager *noShowManager) DB() *gorm.DB {
+	return nsManager.db
+}
+
+func (nsManager *noShowManager) Create(tx *gorm.DB, scaleTeamID int, login string, status UserStatus, beginAt time.Time) (NoShow, error) {
+	noShow := &noShowModel{
+		ScaleTeamID: scaleTeamID,
+		Login:       login,
+		Status:      status,
+		BeginAt:     beginAt,
+
+		noShowManager: nsManager,
+	}
+	if err := tx.Create(noShow).Error; err != nil {
+		return nil, err
+	}
+	return noShow, nil
+}
+
+func (nsManager *noShowManager) Update(tx *gorm.DB, noShow NoShow) error {
+	return tx.Save(noShow).Error
+}
+
+func (nsManager *noShowManager) Delete(tx *gorm.DB, noShow NoShow) error {
+	return tx.Delete(noShow).Error
+}
+
+func (nsManager *noShowManager) Get(tx *gorm.DB, options ...GetOption) ([]NoShow, error) {
+	for _, opt := range options {
+		tx = opt(tx)
+	}
+	var noShows []noShowModel
+
+	if err := tx.Find(&noShows).Error; err != nil {
+		return nil, err
+	}
+
+	returned := make([]NoShow, len(noShows))
+	for i := range noShows {
+		noShows[i].noShowManager = nsManager
+		returned[i] = &noShows[i]
+	}
+	return returned, nil
+}
+
+/*
+ * Deliveries Manager
+ */
+
+type deliveryManager struct {
+	db *gorm.DB
+}
+
+// NewDeliveryManager returns a new manager with the passed GlobalDB object.
+func NewDeliveryManager(db *gorm.DB) DeliveryManager {
+	return &deliveryManager{db: db}
+}
+
+// Returns the underlying database object.
+func (dManager *deliveryManager) DB() *gorm.DB {
+	return dManager.db
+}
+
+// Record inserts the delivery unless it exists. Within a transaction, a concurrent insertion of the same delivery
+// waits for the first one to be either committed or rolled back.
+func (dManager *deliveryManager) Record(tx *gorm.DB, id string, processedAt time.Time) (bool, error) {
+	result := tx.Exec(
+		`INSERT INTO "deliveries" ("id","processed_at") VALUES (?,?) ON CONFLICT DO NOTHING`,
+		id,
+		processedAt,
+	)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (dManager *deliveryManager) Purge(tx *gorm.DB, before time.Time) (int64, error) {
+	result := tx.Where("processed_at < ?", before).Delete(&deliveryModel{})
+	return result.RowsAffected, result.Error
+}
+
+/*
+ * Tombstones Manager
+ */
+
+type tombstoneManager struct {
+	db *gorm.DB
+}
+
+// NewTombstoneManager returns a new manager with the passed GlobalDB object.
+func NewTombstoneManager(db *gorm.DB) TombstoneManager {
+	return &tombstoneManager{db: db}
+}
+
+// Returns the underlying database object.
+func (tManager *tombstoneManager) DB() *gorm.DB {
+	return tManager.db
+}
+
+func (tManager *tombstoneManager) Create(tx *gorm.DB, scaleTeamID int, intraUpdatedAt, destroyedAt time.Time) (Tombstone, error) {
+	tombstone := &tombstoneModel{
+		ScaleTeamID:    scaleTeamID,
+		IntraUpdatedAt: intraUpdatedAt,
+		DestroyedAt:    destroyedAt,
+
+		tombstoneManager: tManager,
+	}
+
+	if err := tx.Create(tombstone).Error; err != nil {
+		return nil, err
+	}
+	return tombstone, nil
+}
+
+func (tManager *tombstoneManager) Update(tx *gorm.DB, tombstone Tombstone) error {
+	return tx.Save(tombstone).Error
+}
+
+func (tManager *tombstoneManager) Delete(tx *gorm.DB, tombstone Tombstone) error {
+	return tx.Delete(tombstone).Error
+}
+
+func (tManager *tombstoneManager) Get(tx *gorm.DB, options ...GetOption) ([]Tombstone, error) {
+	for _, opt := range options {
+		tx = opt(tx)
+	}
+	var tombstones []tombstoneModel
+
+	if err := tx.Find(&tombstones).Error; err != nil {
+		return nil, err
+	}
+
+	returned := make([]Tombstone, len(tombstones))
+	for i := range tombstones {
+		tombstones[i].tombstoneManager = tManager
+		returned[i] = &tombstones[i]
+	}
+	return returned, nil
+}
+
+/*
+ * Profiles Manager
+ */
+
+type profileManager struct {
+	db *gorm.DB
+}
+
+// NewProfileManager returns a new manager with the passed GlobalDB object.
+func NewProfileManager(db *gorm.DB) ProfileManager {
+	return &profileManager{db: db}
+}
+
+// Returns the underlying database object.
+func (pManager *profileManager) DB() *gorm.DB {
+	return pManager.db
+}
+
+// Store inserts the profile, or replaces the one of the same login.
+func (pManager *profileManager) Store(tx *gorm.DB, login, email string, fetchedAt time.Time) error {
+	return tx.Exec(
+		`INSERT INTO "profiles" ("login","email","fetched_at") VALUES (?,?,?) `+
+			`ON CONFLICT ("login") DO UPDATE SET "email" = EXCLUDED."email", "fetched_at" = EXCLUDED."fetched_at"`,
+		login,
+		email,
+		fetchedAt,
+	).Error
+}
+
+func (pManager *profileManager) Invalidate(tx *gorm.DB, login string) (bool, error) {
+	result := tx.Exec(`DELETE FROM "profiles" WHERE "login" = ?`, login)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (pManager *profileManager) Get(tx *gorm.DB, options ...GetOption) ([]Profile, error) {
+	for _, opt := range options {
+		tx = opt(tx)
+	}
+	var profiles []profileModel
+
+	if err := tx.Find(&profiles).Error; err != nil {
+		return nil, err
+	}
+
+	returned := make([]Profile, len(profiles))
+	for i := range profiles {
+		returned[i] = &profiles[i]
+	}
+	return returned, nil
+}
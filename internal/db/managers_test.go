@@ -21,6 +21,22 @@ type ManagerSuite struct {
 	userManager *userManager
 	corrected   *userModel
 	corrector   *userModel
+
+	reminderManager *reminderManager
+	reminder        *reminderModel
+
+	attendanceManager *attendanceManager
+	attendance        *attendanceModel
+
+	noShowManager *noShowManager
+	noShow        *noShowModel
+
+	deliveryManager *deliveryManager
+
+	tombstoneManager *tombstoneManager
+	tombstone        *tombstoneModel
+
+	profileManager *profileManager
 }
 
 /*
@@ -39,6 +55,17 @@ func (s *ManagerSuite) SetupSuite() {
 	s.Require().Implements((*ScaleTeam)(nil), &scaleTeamModel{})
 	s.Require().Implements((*UserManager)(nil), &userManager{})
 	s.Require().Implements((*User)(nil), &userModel{})
+	s.Require().Implements((*ReminderManager)(nil), &reminderManager{})
+	s.Require().Implements((*Reminder)(nil), &reminderModel{})
+	s.Require().Implements((*AttendanceManager)(nil), &attendanceManager{})
+	s.Require().Implements((*Attendance)(nil), &attendanceModel{})
+	s.Require().Implements((*NoShowManager)(nil), &noShowManager{})
+	s.Require().Implements((*NoShow)(nil), &noShowModel{})
+	s.Require().Implements((*DeliveryManager)(nil), &deliveryManager{})
+	s.Require().Implements((*TombstoneManager)(nil), &tombstoneManager{})
+	s.Require().Implements((*Tombstone)(nil), &tombstoneModel{})
+	s.Require().Implements((*ProfileManager)(nil), &profileManager{})
+	s.Require().Implements((*Profile)(nil), &profileModel{})
 
 	db, s.mock, err = sqlmock.New()
 	s.Require().NoError(err)
@@ -48,6 +75,12 @@ func (s *ManagerSuite) SetupSuite() {
 
 	s.scaleTeamManager = &scaleTeamManager{db: s.db}
 	s.userManager = &userManager{db: s.db}
+	s.reminderManager = &reminderManager{db: s.db}
+	s.attendanceManager = &attendanceManager{db: s.db}
+	s.noShowManager = &noShowManager{db: s.db}
+	s.deliveryManager = &deliveryManager{db: s.db}
+	s.tombstoneManager = &tombstoneManager{db: s.db}
+	s.profileManager = &profileManager{db: s.db}
 
 	s.db.LogMode(true)
 }
@@ -58,40 +91,50 @@ func (s *ManagerSuite) AfterTest() {
 
 func (s *ManagerSuite) Test00_CreateScaleTeam() {
 	var (
-		expectedID       = 1
-		expectedBeginAt  = time.Now()
-		expectedNotified = true
+		expectedID      = 1
+		expectedBeginAt = time.Now()
+		expectedMeeting = Meeting{Provider: "jitsi", RoomName: "room", URL: "https://meet.jit.si/room"}
+		expectedVersion = time.Now().Add(-time.Hour)
 	)
 
-	s.mock.ExpectBegin()
-	s.mock.ExpectQuery(
-		regexp.QuoteMeta(`INSERT INTO "scale_teams" ("id","begin_at","notified") VALUES ($1,$2,$3) RETURNING "scale_teams"."id"`),
+	s.mock.ExpectExec(
+		regexp.QuoteMeta(`INSERT INTO "scale_teams" ("id","begin_at","room_provider","room_name","room_url","attendance_checked","intra_updated_at") VALUES ($1,$2,$3,$4,$5,$6,$7) ON CONFLICT ("id") DO NOTHING`),
 	).
-		WithArgs(expectedID, expectedBeginAt, expectedNotified).
-		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(expectedID))
-	s.mock.ExpectCommit()
+		WithArgs(expectedID, expectedBeginAt, expectedMeeting.Provider, expectedMeeting.RoomName, expectedMeeting.URL, false, expectedVersion).
+		WillReturnResult(sqlmock.NewResult(0, 1))
 
-	scaleTeam, err := s.scaleTeamManager.Create(s.db, expectedID, expectedBeginAt, expectedNotified)
+	scaleTeam, err := s.scaleTeamManager.Create(s.db, expectedID, expectedBeginAt, expectedVersion, expectedMeeting)
 	s.Require().NoError(err)
 	s.Require().NotNil(scaleTeam)
 
 	s.scaleTeam = scaleTeam.(*scaleTeamModel)
 }
 
+func (s *ManagerSuite) Test00_CreateScaleTeam_Exists() {
+	s.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "scale_teams"`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	scaleTeam, err := s.scaleTeamManager.Create(s.db, 1, time.Now(), time.Now(), Meeting{})
+	s.Equal(&ScaleTeamExistsError{ID: 1}, err)
+	s.Nil(scaleTeam)
+}
+
 func (s *ManagerSuite) Test01_SelectScaleTeams() {
 	if s.scaleTeam == nil {
 		s.T().SkipNow()
 	}
 
 	var (
-		expectedID       = s.scaleTeam.ID
-		expectedBeginAt  = s.scaleTeam.BeginAt
-		expectedNotified = s.scaleTeam.Notified
+		expectedID      = s.scaleTeam.ID
+		expectedBeginAt = s.scaleTeam.BeginAt
+		expectedMeeting = s.scaleTeam.GetMeeting()
+		expectedVersion = s.scaleTeam.IntraUpdatedAt
 	)
 
 	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "scale_teams"`)).
 		WillReturnRows(
-			sqlmock.NewRows([]string{"id", "begin_at", "notified"}).AddRow(expectedID, expectedBeginAt, expectedNotified),
+			sqlmock.NewRows([]string{"id", "begin_at", "room_provider", "room_name", "room_url", "intra_updated_at"}).
+				AddRow(expectedID, expectedBeginAt, expectedMeeting.Provider, expectedMeeting.RoomName, expectedMeeting.URL, expectedVersion),
 		)
 
 	scaleTeams, err := s.scaleTeamManager.Get(s.db)
@@ -109,10 +152,10 @@ func (s *ManagerSuite) Test02_SelectScaleTeamsWithOptions_0() {
 		expectedNotified = false
 	)
 
-	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "scale_teams" WHERE (id = $1) AND (begin_at >= $2) AND ("scale_teams"."notified" = $3)`)).
-		WithArgs(expectedID, expectedBeginAt.Format(time.RFC3339), expectedNotified).
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "scale_teams" WHERE (id = $1) AND (begin_at >= $2) AND (NOT EXISTS (SELECT 1 FROM reminders WHERE reminders.scale_team_id = scale_teams.id AND sent_at IS NOT NULL))`)).
+		WithArgs(expectedID, expectedBeginAt.Format(time.RFC3339)).
 		WillReturnRows(
-			sqlmock.NewRows([]string{"id", "begin_at", "notified"}),
+			sqlmock.NewRows([]string{"id", "begin_at"}),
 		)
 
 	scaleTeams, err := s.scaleTeamManager.Get(s.db, ScaleTeamIDOption(expectedID), ScaleTeamBeginAtAfterOption(expectedBeginAt), ScaleTeamNotifiedOption(expectedNotified))
@@ -125,13 +168,13 @@ func (s *ManagerSuite) Test03_SelectScaleTeamsWithOptions_1() {
 	var (
 		expectedID       = 1000
 		expectedBeginAt  = time.Now()
-		expectedNotified = false
+		expectedNotified = true
 	)
 
-	s.mock.ExpectQuery(regexp.QuoteMeta(`WHERE (id = $1) AND (begin_at <= $2) AND ("scale_teams"."notified" = $3)`)).
-		WithArgs(expectedID, expectedBeginAt.Format(time.RFC3339), expectedNotified).
+	s.mock.ExpectQuery(regexp.QuoteMeta(`WHERE (id = $1) AND (begin_at <= $2) AND (EXISTS (SELECT 1 FROM reminders WHERE reminders.scale_team_id = scale_teams.id AND sent_at IS NOT NULL))`)).
+		WithArgs(expectedID, expectedBeginAt.Format(time.RFC3339)).
 		WillReturnRows(
-			sqlmock.NewRows([]string{"id", "begin_at", "notified"}),
+			sqlmock.NewRows([]string{"id", "begin_at"}),
 		)
 
 	scaleTeams, err := s.scaleTeamManager.Get(s.db, ScaleTeamIDOption(expectedID), ScaleTeamBeginAtBeforeOption(expectedBeginAt), ScaleTeamNotifiedOption(expectedNotified))
@@ -148,7 +191,7 @@ func (s *ManagerSuite) Test04_SelectScaleTeamsWithOptions_2() {
 	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "scale_teams" WHERE (id = $1)`)).
 		WithArgs(expectedID).
 		WillReturnRows(
-			sqlmock.NewRows([]string{"id", "begin_at", "notified"}),
+			sqlmock.NewRows([]string{"id", "begin_at"}),
 		)
 
 	scaleTeams, err := s.scaleTeamManager.Get(s.db, ScaleTeamIDOption(expectedID))
@@ -157,6 +200,69 @@ func (s *ManagerSuite) Test04_SelectScaleTeamsWithOptions_2() {
 	s.Require().Len(scaleTeams, 0)
 }
 
+func (s *ManagerSuite) Test04_SelectScaleTeamsWithOptions_3() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "scale_teams" WHERE (room_name IS NULL OR room_name = '')`)).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "begin_at"}),
+		)
+
+	scaleTeams, err := s.scaleTeamManager.Get(s.db, ScaleTeamMeetingMissingOption())
+	s.Require().NoError(err)
+	s.Require().NotNil(scaleTeams)
+	s.Require().Len(scaleTeams, 0)
+}
+
+func (s *ManagerSuite) Test04_SelectScaleTeamsWithOptions_4() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "scale_teams" WHERE (NOT EXISTS (SELECT 1 FROM reminders WHERE reminders.scale_team_id = scale_teams.id))`)).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "begin_at"}),
+		)
+
+	scaleTeams, err := s.scaleTeamManager.Get(s.db, ScaleTeamRemindersMissingOption())
+	s.Require().NoError(err)
+	s.Require().NotNil(scaleTeams)
+	s.Require().Len(scaleTeams, 0)
+}
+
+func (s *ManagerSuite) Test04_SelectScaleTeamsWithOptions_5() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "scale_teams" WHERE (LOWER(room_name) = LOWER($1))`)).
+		WithArgs("Room").
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "begin_at"}),
+		)
+
+	scaleTeams, err := s.scaleTeamManager.Get(s.db, ScaleTeamRoomNameOption("Room"))
+	s.Require().NoError(err)
+	s.Require().NotNil(scaleTeams)
+	s.Require().Len(scaleTeams, 0)
+}
+
+func (s *ManagerSuite) Test04_SelectScaleTeamsWithOptions_6() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "scale_teams" WHERE ("scale_teams"."attendance_checked" = $1) AND (begin_at >= (SELECT MIN(joined_at) FROM attendances))`)).
+		WithArgs(false).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "begin_at"}),
+		)
+
+	scaleTeams, err := s.scaleTeamManager.Get(s.db, ScaleTeamAttendanceCheckedOption(false), ScaleTeamAttendanceTrackedOption())
+	s.Require().NoError(err)
+	s.Require().NotNil(scaleTeams)
+	s.Require().Len(scaleTeams, 0)
+}
+
+func (s *ManagerSuite) Test04_SelectScaleTeamsWithOptions_7() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "scale_teams" WHERE (id = $1) FOR UPDATE`)).
+		WithArgs(1).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "begin_at"}),
+		)
+
+	scaleTeams, err := s.scaleTeamManager.Get(s.db, ScaleTeamIDOption(1), ScaleTeamForUpdateOption())
+	s.Require().NoError(err)
+	s.Require().NotNil(scaleTeams)
+	s.Require().Len(scaleTeams, 0)
+}
+
 func (s *ManagerSuite) Test05_UpdateScaleTeam() {
 	s.T().Skip("UPDATE and DELETE requests are not recognized by sqlmock.")
 	s.T().SkipNow()
@@ -293,7 +399,7 @@ func (s *ManagerSuite) Test15_DeleteScaleTeam() {
 }
 
 func (s *ManagerSuite) Test16_ScaleTeamErrorCases() {
-	scaleTeam, err := s.scaleTeamManager.Create(s.db, 1, time.Now(), false)
+	scaleTeam, err := s.scaleTeamManager.Create(s.db, 1, time.Now(), time.Now(), Meeting{})
 	s.Error(err)
 	s.Nil(scaleTeam)
 
@@ -302,6 +408,7 @@ func (s *ManagerSuite) Test16_ScaleTeamErrorCases() {
 	s.Nil(scaleTeams)
 
 	s.Error(s.scaleTeamManager.Update(s.db, s.scaleTeam))
+	s.Error(s.scaleTeamManager.SetAttendanceChecked(s.db, 1))
 	s.Error(s.scaleTeamManager.Delete(s.db, s.scaleTeam))
 }
 
@@ -317,3 +424,404 @@ func (s *ManagerSuite) Test17_UserErrorCases() {
 	s.Error(s.userManager.Update(s.db, s.corrected))
 	s.Error(s.userManager.Delete(s.db, s.corrector))
 }
+
+func (s *ManagerSuite) Test18_CreateReminder() {
+	var (
+		expectedScaleTeamID  = 1
+		expectedRemindBefore = time.Hour
+		expectedRemindAt     = time.Now()
+	)
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery(
+		regexp.QuoteMeta(`INSERT INTO "reminders" ("scale_team_id","remind_before","remind_at","sent_at") VALUES ($1,$2,$3,$4) RETURNING "reminders"."id"`),
+	).
+		WithArgs(expectedScaleTeamID, expectedRemindBefore, expectedRemindAt, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	s.mock.ExpectCommit()
+
+	reminder, err := s.reminderManager.Create(s.db, expectedScaleTeamID, expectedRemindBefore, expectedRemindAt)
+	s.Require().NoError(err)
+	s.Require().NotNil(reminder)
+
+	s.reminder = reminder.(*reminderModel)
+}
+
+func (s *ManagerSuite) Test19_SelectReminders() {
+	if s.reminder == nil {
+		s.T().SkipNow()
+	}
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "reminders"`)).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "scale_team_id", "remind_before", "remind_at", "sent_at"}).
+				AddRow(s.reminder.ID, s.reminder.ScaleTeamID, s.reminder.RemindBefore, s.reminder.RemindAt, nil),
+		)
+
+	reminders, err := s.reminderManager.Get(s.db)
+	s.Require().NoError(err)
+	s.Require().Len(reminders, 1)
+
+	s.Assert().Equal(s.reminder, reminders[0])
+}
+
+func (s *ManagerSuite) Test20_SelectRemindersWithOptions() {
+	var (
+		expectedScaleTeamID = 1
+		expectedNow         = time.Now()
+	)
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "reminders" WHERE (scale_team_id = $1) AND (sent_at IS NULL) AND (remind_at <= $2) `+
+		`AND (EXISTS (SELECT 1 FROM scale_teams WHERE scale_teams.id = reminders.scale_team_id AND scale_teams.begin_at > $3))`)).
+		WithArgs(expectedScaleTeamID, expectedNow.Format(time.RFC3339), expectedNow.Add(-time.Minute).Format(time.RFC3339)).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "scale_team_id", "remind_before", "remind_at", "sent_at"}),
+		)
+
+	reminders, err := s.reminderManager.Get(s.db,
+		ReminderScaleTeamOption(expectedScaleTeamID),
+		ReminderSentOption(false),
+		ReminderDueOption(expectedNow),
+		ReminderScaleTeamBeginAtAfterOption(expectedNow.Add(-time.Minute)),
+	)
+	s.Require().NoError(err)
+	s.Require().NotNil(reminders)
+	s.Require().Len(reminders, 0)
+}
+
+func (s *ManagerSuite) Test21_ReminderErrorCases() {
+	reminder, err := s.reminderManager.Create(s.db, 1, time.Hour, time.Now())
+	s.Error(err)
+	s.Nil(reminder)
+
+	reminders, err := s.reminderManager.Get(s.db, ReminderSentOption(true))
+	s.Error(err)
+	s.Nil(reminders)
+
+	s.Error(s.reminderManager.Update(s.db, s.reminder))
+	s.Error(s.reminderManager.SetSent(s.db, 1, time.Now()))
+	s.Error(s.reminderManager.Delete(s.db, s.reminder))
+}
+
+func (s *ManagerSuite) Test22_CreateAttendance() {
+	var (
+		expectedScaleTeamID = 1
+		expectedLogin       = "xlogin"
+		expectedJoinedAt    = time.Now()
+	)
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery(
+		regexp.QuoteMeta(`INSERT INTO "attendances" ("scale_team_id","login","joined_at","left_at") VALUES ($1,$2,$3,$4) RETURNING "attendances"."id"`),
+	).
+		WithArgs(expectedScaleTeamID, expectedLogin, expectedJoinedAt, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	s.mock.ExpectCommit()
+
+	attendance, err := s.attendanceManager.Create(s.db, expectedScaleTeamID, expectedLogin, expectedJoinedAt)
+	s.Require().NoError(err)
+	s.Require().NotNil(attendance)
+
+	s.attendance = attendance.(*attendanceModel)
+}
+
+func (s *ManagerSuite) Test23_SelectAttendances() {
+	if s.attendance == nil {
+		s.T().SkipNow()
+	}
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "attendances"`)).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "scale_team_id", "login", "joined_at", "left_at"}).
+				AddRow(s.attendance.ID, s.attendance.ScaleTeamID, s.attendance.Login, s.attendance.JoinedAt, nil),
+		)
+
+	attendances, err := s.attendanceManager.Get(s.db)
+	s.Require().NoError(err)
+	s.Require().Len(attendances, 1)
+
+	s.Assert().Equal(s.attendance, attendances[0])
+}
+
+func (s *ManagerSuite) Test24_SelectAttendancesWithOptions() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "attendances" WHERE (scale_team_id = $1) AND (login = $2) AND (left_at IS NULL)`)).
+		WithArgs(1, "xlogin").
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "scale_team_id", "login", "joined_at", "left_at"}),
+		)
+
+	attendances, err := s.attendanceManager.Get(s.db, AttendanceScaleTeamOption(1), AttendanceLoginOption("xlogin"), AttendanceOngoingOption())
+	s.Require().NoError(err)
+	s.Require().NotNil(attendances)
+	s.Require().Len(attendances, 0)
+}
+
+func (s *ManagerSuite) Test25_AttendanceErrorCases() {
+	attendance, err := s.attendanceManager.Create(s.db, 1, "xlogin", time.Now())
+	s.Error(err)
+	s.Nil(attendance)
+
+	attendances, err := s.attendanceManager.Get(s.db)
+	s.Error(err)
+	s.Nil(attendances)
+
+	s.Error(s.attendanceManager.Update(s.db, s.attendance))
+	s.Error(s.attendanceManager.Delete(s.db, s.attendance))
+}
+
+func (s *ManagerSuite) Test26_CreateNoShow() {
+	var (
+		expectedScaleTeamID = 1
+		expectedLogin       = "xlogin"
+		expectedStatus      = Corrected
+		expectedBeginAt     = time.Now()
+	)
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery(
+		regexp.QuoteMeta(`INSERT INTO "no_shows" ("scale_team_id","login","status","begin_at") VALUES ($1,$2,$3,$4) RETURNING "no_shows"."id"`),
+	).
+		WithArgs(expectedScaleTeamID, expectedLogin, expectedStatus, expectedBeginAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	s.mock.ExpectCommit()
+
+	noShow, err := s.noShowManager.Create(s.db, expectedScaleTeamID, expectedLogin, expectedStatus, expectedBeginAt)
+	s.Require().NoError(err)
+	s.Require().NotNil(noShow)
+
+	s.noShow = noShow.(*noShowModel)
+}
+
+func (s *ManagerSuite) Test27_SelectNoShows() {
+	if s.noShow == nil {
+		s.T().SkipNow()
+	}
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "no_shows"`)).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "scale_team_id", "login", "status", "begin_at"}).
+				AddRow(s.noShow.ID, s.noShow.ScaleTeamID, s.noShow.Login, s.noShow.Status, s.noShow.BeginAt),
+		)
+
+	noShows, err := s.noShowManager.Get(s.db)
+	s.Require().NoError(err)
+	s.Require().Len(noShows, 1)
+
+	s.Assert().Equal(s.noShow, noShows[0])
+}
+
+func (s *ManagerSuite) Test28_SelectNoShowsWithOptions() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "no_shows" WHERE (scale_team_id = $1) AND (login = $2) ORDER BY begin_at DESC`)).
+		WithArgs(1, "xlogin").
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "scale_team_id", "login", "status", "begin_at"}),
+		)
+
+	noShows, err := s.noShowManager.Get(s.db, NoShowScaleTeamOption(1), NoShowLoginOption("xlogin"), NoShowLatestFirstOption())
+	s.Require().NoError(err)
+	s.Require().NotNil(noShows)
+	s.Require().Len(noShows, 0)
+}
+
+func (s *ManagerSuite) Test29_NoShowErrorCases() {
+	noShow, err := s.noShowManager.Create(s.db, 1, "xlogin", Corrector, time.Now())
+	s.Error(err)
+	s.Nil(noShow)
+
+	noShows, err := s.noShowManager.Get(s.db)
+	s.Error(err)
+	s.Nil(noShows)
+
+	s.Error(s.noShowManager.Update(s.db, s.noShow))
+	s.Error(s.noShowManager.Delete(s.db, s.noShow))
+}
+
+func (s *ManagerSuite) Test30_RecordDelivery() {
+	processedAt := time.Now()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "deliveries" ("id","processed_at") VALUES ($1,$2) ON CONFLICT DO NOTHING`)).
+		WithArgs("delivery", processedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	recorded, err := s.deliveryManager.Record(s.db, "delivery", processedAt)
+	s.Require().NoError(err)
+	s.True(recorded)
+}
+
+func (s *ManagerSuite) Test31_RecordDelivery_AlreadyProcessed() {
+	processedAt := time.Now()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "deliveries" ("id","processed_at") VALUES ($1,$2) ON CONFLICT DO NOTHING`)).
+		WithArgs("delivery", processedAt).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	recorded, err := s.deliveryManager.Record(s.db, "delivery", processedAt)
+	s.Require().NoError(err)
+	s.False(recorded)
+}
+
+func (s *ManagerSuite) Test32_PurgeDeliveries() {
+	s.T().Skip("UPDATE and DELETE requests are not recognized by sqlmock.")
+	s.T().SkipNow()
+}
+
+func (s *ManagerSuite) Test33_DeliveryErrorCases() {
+	recorded, err := s.deliveryManager.Record(s.db, "delivery", time.Now())
+	s.Error(err)
+	s.False(recorded)
+
+	_, err = s.deliveryManager.Purge(s.db, time.Now())
+	s.Error(err)
+}
+
+func (s *ManagerSuite) Test34_CreateTombstone() {
+	var (
+		expectedScaleTeamID = 1
+		expectedVersion     = time.Now().Add(-time.Hour)
+		expectedDestroyedAt = time.Now()
+	)
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery(
+		regexp.QuoteMeta(`INSERT INTO "tombstones" ("scale_team_id","intra_updated_at","destroyed_at") VALUES ($1,$2,$3) RETURNING "tombstones"."scale_team_id"`),
+	).
+		WithArgs(expectedScaleTeamID, expectedVersion, expectedDestroyedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"scale_team_id"}).AddRow(expectedScaleTeamID))
+	s.mock.ExpectCommit()
+
+	tombstone, err := s.tombstoneManager.Create(s.db, expectedScaleTeamID, expectedVersion, expectedDestroyedAt)
+	s.Require().NoError(err)
+	s.Require().NotNil(tombstone)
+
+	s.tombstone = tombstone.(*tombstoneModel)
+}
+
+func (s *ManagerSuite) Test35_SelectTombstonesWithOptions() {
+	if s.tombstone == nil {
+		s.T().SkipNow()
+	}
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "tombstones" WHERE (scale_team_id = $1)`)).
+		WithArgs(s.tombstone.ScaleTeamID).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"scale_team_id", "intra_updated_at", "destroyed_at"}).
+				AddRow(s.tombstone.ScaleTeamID, s.tombstone.IntraUpdatedAt, s.tombstone.DestroyedAt),
+		)
+
+	tombstones, err := s.tombstoneManager.Get(s.db, TombstoneScaleTeamOption(s.tombstone.ScaleTeamID))
+	s.Require().NoError(err)
+	s.Require().Len(tombstones, 1)
+
+	s.Assert().Equal(s.tombstone, tombstones[0])
+}
+
+func (s *ManagerSuite) Test36_TombstoneErrorCases() {
+	tombstone, err := s.tombstoneManager.Create(s.db, 1, time.Now(), time.Now())
+	s.Error(err)
+	s.Nil(tombstone)
+
+	tombstones, err := s.tombstoneManager.Get(s.db)
+	s.Error(err)
+	s.Nil(tombstones)
+
+	s.Error(s.tombstoneManager.Update(s.db, s.tombstone))
+	s.Error(s.tombstoneManager.Delete(s.db, s.tombstone))
+}
+
+func (s *ManagerSuite) Test37_StoreProfile() {
+	fetchedAt := time.Now()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`INSERT INTO "profiles" ("login","email","fetched_at") VALUES ($1,$2,$3) `+
+			`ON CONFLICT ("login") DO UPDATE SET "email" = EXCLUDED."email", "fetched_at" = EXCLUDED."fetched_at"`,
+	)).
+		WithArgs("xlogin", "xlogin@student.42campus.org", fetchedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.NoError(s.profileManager.Store(s.db, "xlogin", "xlogin@student.42campus.org", fetchedAt))
+}
+
+func (s *ManagerSuite) Test38_SelectProfilesWithOptions() {
+	var (
+		fetchedAt = time.Now()
+		after     = fetchedAt.Add(-time.Hour)
+	)
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "profiles" WHERE (login = $1) AND (fetched_at > $2)`)).
+		WithArgs("xlogin", after.Format(time.RFC3339)).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"login", "email", "fetched_at"}).
+				AddRow("xlogin", "xlogin@student.42campus.org", fetchedAt),
+		)
+
+	profiles, err := s.profileManager.Get(s.db, ProfileLoginOption("xlogin"), ProfileFetchedAfterOption(after))
+	s.Require().NoError(err)
+	s.Require().Len(profiles, 1)
+
+	s.Equal("xlogin", profiles[0].GetLogin())
+	s.Equal("xlogin@student.42campus.org", profiles[0].GetEmail())
+	s.Equal(fetchedAt, profiles[0].GetFetchedAt())
+}
+
+func (s *ManagerSuite) Test39_InvalidateProfile() {
+	s.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "profiles" WHERE "login" = $1`)).
+		WithArgs("xlogin").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "profiles" WHERE "login" = $1`)).
+		WithArgs("ylogin").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	invalidated, err := s.profileManager.Invalidate(s.db, "xlogin")
+	s.Require().NoError(err)
+	s.True(invalidated)
+
+	invalidated, err = s.profileManager.Invalidate(s.db, "ylogin")
+	s.Require().NoError(err)
+	s.False(invalidated)
+}
+
+func (s *ManagerSuite) Test40_ProfileErrorCases() {
+	s.Error(s.profileManager.Store(s.db, "xlogin", "xlogin@student.42campus.org", time.Now()))
+
+	invalidated, err := s.profileManager.Invalidate(s.db, "xlogin")
+	s.Error(err)
+	s.False(invalidated)
+
+	profiles, err := s.profileManager.Get(s.db)
+	s.Error(err)
+	s.Nil(profiles)
+}
+
+func (s *ManagerSuite) Test41_SetAttendanceChecked() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "scale_teams" SET "attendance_checked" = $1 WHERE (id = $2)`)).
+		WithArgs(true, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "scale_teams" SET "attendance_checked" = $1 WHERE (id = $2)`)).
+		WithArgs(true, 2).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectCommit()
+
+	s.Require().NoError(s.scaleTeamManager.SetAttendanceChecked(s.db, 1))
+	s.Equal(&GoneError{Table: "scale_teams", ID: 2}, s.scaleTeamManager.SetAttendanceChecked(s.db, 2))
+}
+
+func (s *ManagerSuite) Test42_SetReminderSent() {
+	sentAt := time.Now()
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "reminders" SET "sent_at" = $1 WHERE (id = $2)`)).
+		WithArgs(sentAt, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "reminders" SET "sent_at" = $1 WHERE (id = $2)`)).
+		WithArgs(sentAt, 2).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectCommit()
+
+	s.Require().NoError(s.reminderManager.SetSent(s.db, 1, sentAt))
+	s.Equal(&GoneError{Table: "reminders", ID: 2}, s.reminderManager.SetSent(s.db, 2, sentAt))
+}
@@ -0,0 +1,234 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// migrationLockKey is the key of the postgres advisory lock preventing concurrent migrations.
+const migrationLockKey int64 = 4242
+
+const createSchemaMigrations = `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version integer NOT NULL,
+	name text NOT NULL,
+	applied_at timestamp with time zone NOT NULL DEFAULT NOW(),
+	PRIMARY KEY (version)
+)`
+
+// migration is a versioned change of the database schema, along with the way to revert it.
+type migration struct {
+	version int
+	name    string
+	up      string
+	down    string
+}
+
+// MigrationState describes a known migration and when it was applied, if it was.
+type MigrationState struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+}
+
+// queryer is implemented by both *sql.DB and *sql.Conn.
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// LatestSchemaVersion returns the version of the last known migration.
+func LatestSchemaVersion() int {
+	return migrations[len(migrations)-1].version
+}
+
+// CheckSchemaVersion returns a SchemaVersionError if the database schema is not at the latest known version.
+func CheckSchemaVersion(db *sql.DB) error {
+	applied, err := appliedMigrations(context.Background(), db)
+	if err != nil {
+		return err
+	}
+
+	current := 0
+	for version := range applied {
+		if version > current {
+			current = version
+		}
+	}
+	if current != LatestSchemaVersion() {
+		return &SchemaVersionError{current: current, expected: LatestSchemaVersion()}
+	}
+	return nil
+}
+
+// MigrateUp applies every pending migration in order, each one in its own transaction. The tables of a database which
+// was never migrated are completed with autoMigrateColumns first.
+//
+// It returns the number of applied migrations.
+func MigrateUp(db *sql.DB) (int, error) {
+	count := 0
+	err := withMigrationLock(db, func(ctx context.Context, conn *sql.Conn) error {
+		applied, err := appliedMigrations(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		if len(applied) == 0 {
+			logrus.Info("adding the missing columns to the tables created by AutoMigrate")
+			if _, err := conn.ExecContext(ctx, autoMigrateColumns); err != nil {
+				return err
+			}
+		}
+
+		for _, m := range migrations {
+			if _, ok := applied[m.version]; ok {
+				continue
+			}
+			logrus.WithFields(logrus.Fields{"version": m.version, "name": m.name}).Info("applying migration")
+			if err := runMigration(ctx, conn, m.up, `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, m.version, m.name); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// MigrateDown reverts the `steps` last applied migrations, each one in its own transaction.
+//
+// It returns the number of reverted migrations.
+func MigrateDown(db *sql.DB, steps int) (int, error) {
+	count := 0
+	err := withMigrationLock(db, func(ctx context.Context, conn *sql.Conn) error {
+		applied, err := appliedMigrations(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		versions := make([]int, 0, len(applied))
+		for version := range applied {
+			versions = append(versions, version)
+		}
+		sort.Sort(sort.Reverse(sort.IntSlice(versions)))
+
+		for _, version := range versions {
+			if count == steps {
+				break
+			}
+			m, ok := findMigration(version)
+			if !ok {
+				return &UnknownMigrationError{version: version}
+			}
+			logrus.WithFields(logrus.Fields{"version": m.version, "name": m.name}).Info("reverting migration")
+			if err := runMigration(ctx, conn, m.down, `DELETE FROM schema_migrations WHERE version = $1`, m.version); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// MigrationStatus lists every known migration along with when it was applied.
+func MigrationStatus(db *sql.DB) ([]MigrationState, error) {
+	applied, err := appliedMigrations(context.Background(), db)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]MigrationState, len(migrations))
+	for i, m := range migrations {
+		states[i] = MigrationState{Version: m.version, Name: m.name}
+		if appliedAt, ok := applied[m.version]; ok {
+			states[i].AppliedAt = &appliedAt
+		}
+	}
+	return states, nil
+}
+
+// withMigrationLock runs `fn` on a single connection holding the migration advisory lock, once the schema_migrations
+// table exists.
+func withMigrationLock(db *sql.DB, fn func(ctx context.Context, conn *sql.Conn) error) error {
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	logrus.Debug("acquiring the migration lock")
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLockKey); err != nil {
+		return err
+	}
+	defer func() {
+		if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, migrationLockKey); err != nil {
+			logrus.WithError(err).Errorf("could not release the migration lock: %v", err)
+		}
+	}()
+
+	if _, err := conn.ExecContext(ctx, createSchemaMigrations); err != nil {
+		return err
+	}
+	return fn(ctx, conn)
+}
+
+// runMigration executes the migration's `statements` and records it with `record` in a single transaction.
+func runMigration(ctx context.Context, conn *sql.Conn, statements, record string, args ...interface{}) error {
+	tx, err := conn.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, statements); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// appliedMigrations returns the applied migrations' versions and when they were applied.
+func appliedMigrations(ctx context.Context, db queryer) (map[int]time.Time, error) {
+	var exists bool
+	if err := db.QueryRowContext(ctx, `SELECT to_regclass('schema_migrations') IS NOT NULL`).Scan(&exists); err != nil {
+		return nil, err
+	}
+	applied := make(map[int]time.Time)
+	if !exists {
+		return applied, nil
+	}
+
+	rows, err := db.QueryContext(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			version   int
+			appliedAt time.Time
+		)
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+	return applied, rows.Err()
+}
+
+func findMigration(version int) (migration, bool) {
+	for _, m := range migrations {
+		if m.version == version {
+			return m, true
+		}
+	}
+	return migration{}, false
+}
@@ -0,0 +1,261 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestMigrations(t *testing.T) {
+	require.NotEmpty(t, migrations)
+
+	for i, m := range migrations {
+		assert.Equal(t, i+1, m.version, "the migrations' versions must follow each other")
+		assert.NotEmpty(t, m.name)
+		assert.NotEmpty(t, m.up)
+		assert.NotEmpty(t, m.down)
+	}
+	assert.Equal(t, len(migrations), LatestSchemaVersion())
+}
+
+// TestMigrations_FromAutoMigrate verifies that the migrations add every column of the models to the tables which
+// gorm's AutoMigrate created before the migrations existed, as `CREATE TABLE IF NOT EXISTS` skips them.
+func TestMigrations_FromAutoMigrate(t *testing.T) {
+	autoMigrated := map[string][]string{
+		"scale_teams": {"id", "begin_at", "notified"},
+		"users":       {"id", "scale_team_id", "login", "status"},
+	}
+
+	sqlDB, _, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+	gormDB, err := gorm.Open("postgres", sqlDB)
+	require.NoError(t, err)
+
+	var ups strings.Builder
+	ups.WriteString(autoMigrateColumns)
+	for _, m := range migrations {
+		ups.WriteString(m.up)
+	}
+	for _, model := range []interface{}{&scaleTeamModel{}, &userModel{}} {
+		scope := gormDB.NewScope(model)
+		existing := autoMigrated[scope.TableName()]
+		for _, field := range scope.Fields() {
+			if !field.IsNormal || field.IsIgnored {
+				continue
+			}
+			if containsString(existing, field.DBName) {
+				continue
+			}
+			added := regexp.MustCompile(fmt.Sprintf(`ALTER TABLE (IF EXISTS )?%s ADD COLUMN (IF NOT EXISTS )?%s `, scope.TableName(), field.DBName))
+			assert.Regexp(t, added, ups.String(), "the column %s.%s is never added", scope.TableName(), field.DBName)
+		}
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func TestMigrate(t *testing.T) {
+	suite.Run(t, new(MigrateSuite))
+}
+
+type MigrateSuite struct {
+	suite.Suite
+
+	db   *sql.DB
+	mock sqlmock.Sqlmock
+}
+
+func (s *MigrateSuite) SetupTest() {
+	var err error
+	s.db, s.mock, err = sqlmock.New()
+	s.Require().NoError(err)
+}
+
+func (s *MigrateSuite) expectApplied(versions ...int) {
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT to_regclass('schema_migrations') IS NOT NULL`)).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	rows := sqlmock.NewRows([]string{"version", "applied_at"})
+	for _, version := range versions {
+		rows.AddRow(version, time.Now())
+	}
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT version, applied_at FROM schema_migrations`)).WillReturnRows(rows)
+}
+
+func (s *MigrateSuite) expectLock() {
+	s.mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_lock($1)`)).
+		WithArgs(migrationLockKey).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectExec(regexp.QuoteMeta(`CREATE TABLE IF NOT EXISTS schema_migrations`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func (s *MigrateSuite) expectUnlock() {
+	s.mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_unlock($1)`)).
+		WithArgs(migrationLockKey).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func (s *MigrateSuite) Test00_CheckSchemaVersion() {
+	s.expectApplied(1, 2, 3, 4, 5, 6)
+
+	s.NoError(CheckSchemaVersion(s.db))
+}
+
+func (s *MigrateSuite) Test01_CheckSchemaVersion_Outdated() {
+	s.expectApplied(1)
+
+	err := CheckSchemaVersion(s.db)
+	s.IsType(&SchemaVersionError{}, err)
+}
+
+func (s *MigrateSuite) Test02_CheckSchemaVersion_NeverMigrated() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT to_regclass('schema_migrations') IS NOT NULL`)).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+	err := CheckSchemaVersion(s.db)
+	s.Equal(&SchemaVersionError{current: 0, expected: LatestSchemaVersion()}, err)
+}
+
+func (s *MigrateSuite) Test03_MigrateUp() {
+	s.expectLock()
+	s.expectApplied(1, 2)
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta(`CREATE TABLE deliveries`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`)).
+		WithArgs(3, "deliveries").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta(`ALTER TABLE scale_teams ADD COLUMN intra_updated_at`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`)).
+		WithArgs(4, "scale_team_versions").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta(`CREATE TABLE profiles`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`)).
+		WithArgs(5, "profiles").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(`(?s)INSERT INTO reminders .* WHERE notified .*ALTER TABLE scale_teams DROP COLUMN notified`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`)).
+		WithArgs(6, "notified_from_reminders").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+	s.expectUnlock()
+
+	count, err := MigrateUp(s.db)
+	s.NoError(err)
+	s.Equal(4, count)
+}
+
+func (s *MigrateSuite) Test04_MigrateUp_Error() {
+	s.expectLock()
+	s.expectApplied()
+	s.mock.ExpectExec(regexp.QuoteMeta(`ALTER TABLE IF EXISTS scale_teams`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta(`CREATE TABLE IF NOT EXISTS scale_teams`)).
+		WillReturnError(sql.ErrConnDone)
+	s.mock.ExpectRollback()
+	s.expectUnlock()
+
+	count, err := MigrateUp(s.db)
+	s.Equal(sql.ErrConnDone, err)
+	s.Equal(0, count)
+}
+
+func (s *MigrateSuite) Test05_MigrateDown() {
+	s.expectLock()
+	s.expectApplied(1, 2, 3, 4)
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta(`DROP TABLE tombstones`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM schema_migrations WHERE version = $1`)).
+		WithArgs(4).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+	s.expectUnlock()
+
+	count, err := MigrateDown(s.db, 1)
+	s.NoError(err)
+	s.Equal(1, count)
+}
+
+func (s *MigrateSuite) Test06_MigrateDown_UnknownMigration() {
+	s.expectLock()
+	s.expectApplied(1, 2, 3, 4, 1000)
+	s.expectUnlock()
+
+	count, err := MigrateDown(s.db, 1)
+	s.Equal(&UnknownMigrationError{version: 1000}, err)
+	s.Equal(0, count)
+}
+
+func (s *MigrateSuite) Test07_MigrationStatus() {
+	s.expectApplied(1)
+
+	states, err := MigrationStatus(s.db)
+	s.Require().NoError(err)
+	s.Require().Len(states, len(migrations))
+
+	s.Equal(1, states[0].Version)
+	s.Equal("initial_schema", states[0].Name)
+	s.NotNil(states[0].AppliedAt)
+	s.Equal(2, states[1].Version)
+	s.Nil(states[1].AppliedAt)
+}
+
+func (s *MigrateSuite) Test08_MigrateUp_FromAutoMigrate() {
+	s.expectLock()
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT to_regclass('schema_migrations') IS NOT NULL`)).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	s.mock.ExpectExec(`(?s)ALTER TABLE IF EXISTS scale_teams ADD COLUMN IF NOT EXISTS room_provider .*` +
+		`ALTER TABLE IF EXISTS scale_teams ADD COLUMN IF NOT EXISTS room_name .*` +
+		`ALTER TABLE IF EXISTS scale_teams ADD COLUMN IF NOT EXISTS room_url .*` +
+		`ALTER TABLE IF EXISTS scale_teams ADD COLUMN IF NOT EXISTS attendance_checked `).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta(`CREATE TABLE IF NOT EXISTS scale_teams`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`)).
+		WithArgs(1, "initial_schema").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(regexp.QuoteMeta(`CREATE INDEX users_scale_team_id_idx`)).
+		WillReturnError(sql.ErrConnDone)
+	s.mock.ExpectRollback()
+	s.expectUnlock()
+
+	count, err := MigrateUp(s.db)
+	s.Equal(sql.ErrConnDone, err)
+	s.Equal(1, count)
+}
+
+func (s *MigrateSuite) TearDownTest() {
+	s.NoError(s.mock.ExpectationsWereMet())
+	s.db.Close()
+}
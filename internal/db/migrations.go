@@ -0,0 +1,152 @@
+package db
+
+// migrations is the ordered set of changes of the database schema. A migration must never be edited once released,
+// add a new one instead.
+// autoMigrateColumns adds the columns the first migration expects to the scale_teams table created by gorm's
+// AutoMigrate, which only has the id, begin_at and notified ones, as `CREATE TABLE IF NOT EXISTS` skips it. It is run
+// before the migrations of the databases which were never migrated, and does not change the other tables.
+const autoMigrateColumns = `
+ALTER TABLE IF EXISTS scale_teams ADD COLUMN IF NOT EXISTS room_provider varchar(32);
+ALTER TABLE IF EXISTS scale_teams ADD COLUMN IF NOT EXISTS room_name varchar(255);
+ALTER TABLE IF EXISTS scale_teams ADD COLUMN IF NOT EXISTS room_url text;
+ALTER TABLE IF EXISTS scale_teams ADD COLUMN IF NOT EXISTS attendance_checked boolean DEFAULT false;`
+
+var migrations = []migration{
+	{
+		version: 1,
+		name:    "initial_schema",
+		// The tables are created only if they do not exist yet, so that the databases previously created by gorm's
+		// AutoMigrate start from this version.
+		up: `
+CREATE TABLE IF NOT EXISTS scale_teams (
+	id integer NOT NULL,
+	begin_at timestamp with time zone,
+	notified boolean DEFAULT false,
+	room_provider varchar(32),
+	room_name varchar(255),
+	room_url text,
+	attendance_checked boolean DEFAULT false,
+	PRIMARY KEY (id)
+);
+CREATE TABLE IF NOT EXISTS users (
+	id serial,
+	scale_team_id integer,
+	login text,
+	status text,
+	PRIMARY KEY (id),
+	CONSTRAINT users_scale_team_id_scale_teams_id_foreign FOREIGN KEY (scale_team_id)
+		REFERENCES scale_teams(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE TABLE IF NOT EXISTS reminders (
+	id serial,
+	scale_team_id integer,
+	remind_before bigint,
+	remind_at timestamp with time zone,
+	sent_at timestamp with time zone,
+	PRIMARY KEY (id),
+	CONSTRAINT reminders_scale_team_id_scale_teams_id_foreign FOREIGN KEY (scale_team_id)
+		REFERENCES scale_teams(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE TABLE IF NOT EXISTS attendances (
+	id serial,
+	scale_team_id integer,
+	login text,
+	joined_at timestamp with time zone,
+	left_at timestamp with time zone,
+	PRIMARY KEY (id),
+	CONSTRAINT attendances_scale_team_id_scale_teams_id_foreign FOREIGN KEY (scale_team_id)
+		REFERENCES scale_teams(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+-- The no-shows are not bound to the scale teams so that they can still be followed up on once the latter are gone.
+CREATE TABLE IF NOT EXISTS no_shows (
+	id serial,
+	scale_team_id integer,
+	login text,
+	status text,
+	begin_at timestamp with time zone,
+	PRIMARY KEY (id)
+);`,
+		down: `
+DROP TABLE no_shows;
+DROP TABLE attendances;
+DROP TABLE reminders;
+DROP TABLE users;
+DROP TABLE scale_teams;`,
+	},
+	{
+		version: 2,
+		name:    "lookup_indexes",
+		up: `
+CREATE INDEX users_scale_team_id_idx ON users (scale_team_id);
+CREATE INDEX reminders_due_idx ON reminders (remind_at) WHERE sent_at IS NULL;
+CREATE INDEX attendances_scale_team_id_login_idx ON attendances (scale_team_id, login);
+CREATE INDEX scale_teams_room_name_idx ON scale_teams (LOWER(room_name));
+CREATE INDEX no_shows_login_idx ON no_shows (login);`,
+		down: `
+DROP INDEX no_shows_login_idx;
+DROP INDEX scale_teams_room_name_idx;
+DROP INDEX attendances_scale_team_id_login_idx;
+DROP INDEX reminders_due_idx;
+DROP INDEX users_scale_team_id_idx;`,
+	},
+	{
+		version: 3,
+		name:    "deliveries",
+		up: `
+CREATE TABLE deliveries (
+	id varchar(255) NOT NULL,
+	processed_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (id)
+);
+CREATE INDEX deliveries_processed_at_idx ON deliveries (processed_at);`,
+		down: `
+DROP TABLE deliveries;`,
+	},
+	{
+		version: 4,
+		name:    "scale_team_versions",
+		// The scale teams stored before their version are older than any event.
+		up: `
+ALTER TABLE scale_teams ADD COLUMN intra_updated_at timestamp with time zone NOT NULL DEFAULT 'epoch';
+CREATE TABLE tombstones (
+	scale_team_id integer NOT NULL,
+	intra_updated_at timestamp with time zone NOT NULL,
+	destroyed_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (scale_team_id)
+);`,
+		down: `
+DROP TABLE tombstones;
+ALTER TABLE scale_teams DROP COLUMN intra_updated_at;`,
+	},
+	{
+		version: 5,
+		name:    "profiles",
+		up: `
+CREATE TABLE profiles (
+	login varchar(32) NOT NULL,
+	email text NOT NULL,
+	fetched_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (login)
+);`,
+		down: `
+DROP TABLE profiles;`,
+	},
+	{
+		version: 6,
+		name:    "notified_from_reminders",
+		// The scale teams are notified once any of their reminders is sent. Those notified before the reminders were
+		// stored get a sent reminder standing for the notification.
+		up: `
+INSERT INTO reminders (scale_team_id, remind_before, remind_at, sent_at)
+	SELECT id, 0, begin_at, LEAST(begin_at, NOW()) FROM scale_teams
+	WHERE notified AND NOT EXISTS (
+		SELECT 1 FROM reminders WHERE reminders.scale_team_id = scale_teams.id AND reminders.sent_at IS NOT NULL
+	);
+ALTER TABLE scale_teams DROP COLUMN notified;`,
+		down: `
+ALTER TABLE scale_teams ADD COLUMN notified boolean DEFAULT false;
+UPDATE scale_teams SET notified = EXISTS (
+	SELECT 1 FROM reminders WHERE reminders.scale_team_id = scale_teams.id AND reminders.sent_at IS NOT NULL
+);`,
+	},
+}
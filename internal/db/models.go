@@ -7,10 +7,17 @@ import (
 )
 
 type scaleTeamModel struct {
-	ID       int `gorm:"primary_key;auto_increment:false"`
-	BeginAt  time.Time
-	Notified bool        `gorm:"default:false"`
-	Users    []userModel `gorm:"foreignkey:ScaleTeamID"`
+	ID           int `gorm:"primary_key;auto_increment:false"`
+	BeginAt      time.Time
+	RoomProvider string      `gorm:"type:varchar(32)"`
+	RoomName     string      `gorm:"type:varchar(255)"`
+	RoomURL      string      `gorm:"type:text"`
+	Users        []userModel `gorm:"foreignkey:ScaleTeamID"`
+
+	AttendanceChecked bool `gorm:"default:false"`
+
+	// IntraUpdatedAt is the `updated_at` of the latest intra event applied to the scale team, i.e: its version.
+	IntraUpdatedAt time.Time
 
 	userManager      UserManager      `gorm:"-"`
 	scaleTeamManager ScaleTeamManager `gorm:"-"`
@@ -28,8 +35,20 @@ func (scaleTeam *scaleTeamModel) GetBeginAt() time.Time {
 	return scaleTeam.BeginAt
 }
 
-func (scaleTeam *scaleTeamModel) GetNotified() bool {
-	return scaleTeam.Notified
+func (scaleTeam *scaleTeamModel) GetMeeting() Meeting {
+	return Meeting{
+		Provider: scaleTeam.RoomProvider,
+		RoomName: scaleTeam.RoomName,
+		URL:      scaleTeam.RoomURL,
+	}
+}
+
+func (scaleTeam *scaleTeamModel) GetAttendanceChecked() bool {
+	return scaleTeam.AttendanceChecked
+}
+
+func (scaleTeam *scaleTeamModel) GetIntraUpdatedAt() time.Time {
+	return scaleTeam.IntraUpdatedAt
 }
 
 func (scaleTeam *scaleTeamModel) Get(tx *gorm.DB, options ...GetOption) ([]User, error) {
@@ -45,8 +64,18 @@ func (scaleTeam *scaleTeamModel) SetBeginAt(beginAt time.Time) {
 	scaleTeam.BeginAt = beginAt
 }
 
-func (scaleTeam *scaleTeamModel) SetNotified(notified bool) {
-	scaleTeam.Notified = notified
+func (scaleTeam *scaleTeamModel) SetMeeting(meeting Meeting) {
+	scaleTeam.RoomProvider = meeting.Provider
+	scaleTeam.RoomName = meeting.RoomName
+	scaleTeam.RoomURL = meeting.URL
+}
+
+func (scaleTeam *scaleTeamModel) SetAttendanceChecked(checked bool) {
+	scaleTeam.AttendanceChecked = checked
+}
+
+func (scaleTeam *scaleTeamModel) SetIntraUpdatedAt(updatedAt time.Time) {
+	scaleTeam.IntraUpdatedAt = updatedAt
 }
 
 func (scaleTeam *scaleTeamModel) Save(tx *gorm.DB) error {
@@ -106,3 +135,208 @@ func (user *userModel) Save(tx *gorm.DB) error {
 func (user *userModel) Delete(tx *gorm.DB) error {
 	return user.userManager.Delete(tx, user)
 }
+
+type reminderModel struct {
+	ID           int `gorm:"primary_key"`
+	ScaleTeamID  int
+	RemindBefore time.Duration
+	RemindAt     time.Time
+	SentAt       *time.Time
+
+	reminderManager ReminderManager `gorm:"-"`
+}
+
+func (reminderModel) TableName() string {
+	return "reminders"
+}
+
+func (reminder *reminderModel) GetID() int {
+	return reminder.ID
+}
+
+func (reminder *reminderModel) GetScaleTeamID() int {
+	return reminder.ScaleTeamID
+}
+
+func (reminder *reminderModel) GetRemindBefore() time.Duration {
+	return reminder.RemindBefore
+}
+
+func (reminder *reminderModel) GetRemindAt() time.Time {
+	return reminder.RemindAt
+}
+
+func (reminder *reminderModel) GetSentAt() *time.Time {
+	return reminder.SentAt
+}
+
+func (reminder *reminderModel) SetRemindAt(remindAt time.Time) {
+	reminder.RemindAt = remindAt
+}
+
+func (reminder *reminderModel) SetSentAt(sentAt *time.Time) {
+	reminder.SentAt = sentAt
+}
+
+func (reminder *reminderModel) Save(tx *gorm.DB) error {
+	return reminder.reminderManager.Update(tx, reminder)
+}
+
+func (reminder *reminderModel) Delete(tx *gorm.DB) error {
+	return reminder.reminderManager.Delete(tx, reminder)
+}
+
+type attendanceModel struct {
+	ID          int `gorm:"primary_key"`
+	ScaleTeamID int
+	Login       string `gorm:"varchar(32)"`
+	JoinedAt    time.Time
+	LeftAt      *time.Time
+
+	attendanceManager AttendanceManager `gorm:"-"`
+}
+
+func (attendanceModel) TableName() string {
+	return "attendances"
+}
+
+func (attendance *attendanceModel) GetID() int {
+	return attendance.ID
+}
+
+func (attendance *attendanceModel) GetScaleTeamID() int {
+	return attendance.ScaleTeamID
+}
+
+func (attendance *attendanceModel) GetLogin() string {
+	return attendance.Login
+}
+
+func (attendance *attendanceModel) GetJoinedAt() time.Time {
+	return attendance.JoinedAt
+}
+
+func (attendance *attendanceModel) GetLeftAt() *time.Time {
+	return attendance.LeftAt
+}
+
+func (attendance *attendanceModel) SetLeftAt(leftAt *time.Time) {
+	attendance.LeftAt = leftAt
+}
+
+func (attendance *attendanceModel) Save(tx *gorm.DB) error {
+	return attendance.attendanceManager.Update(tx, attendance)
+}
+
+func (attendance *attendanceModel) Delete(tx *gorm.DB) error {
+	return attendance.attendanceManager.Delete(tx, attendance)
+}
+
+type noShowModel struct {
+	ID          int `gorm:"primary_key"`
+	ScaleTeamID int
+	Login       string     `gorm:"varchar(32)"`
+	Status      UserStatus `gorm:"varchar(32)"`
+	BeginAt     time.Time
+
+	noShowManager NoShowManager `gorm:"-"`
+}
+
+func (noShowModel) TableName() string {
+	return "no_shows"
+}
+
+func (noShow *noShowModel) GetID() int {
+	return noShow.ID
+}
+
+func (noShow *noShowModel) GetScaleTeamID() int {
+	return noShow.ScaleTeamID
+}
+
+func (noShow *noShowModel) GetLogin() string {
+	return noShow.Login
+}
+
+func (noShow *noShowModel) GetStatus() UserStatus {
+	return noShow.Status
+}
+
+func (noShow *noShowModel) GetBeginAt() time.Time {
+	return noShow.BeginAt
+}
+
+func (noShow *noShowModel) Save(tx *gorm.DB) error {
+	return noShow.noShowManager.Update(tx, noShow)
+}
+
+func (noShow *noShowModel) Delete(tx *gorm.DB) error {
+	return noShow.noShowManager.Delete(tx, noShow)
+}
+
+type deliveryModel struct {
+	ID          string `gorm:"primary_key;type:varchar(255)"`
+	ProcessedAt time.Time
+}
+
+func (deliveryModel) TableName() string {
+	return "deliveries"
+}
+
+type tombstoneModel struct {
+	ScaleTeamID    int `gorm:"primary_key;auto_increment:false"`
+	IntraUpdatedAt time.Time
+	DestroyedAt    time.Time
+
+	tombstoneManager TombstoneManager `gorm:"-"`
+}
+
+func (tombstoneModel) TableName() string {
+	return "tombstones"
+}
+
+func (tombstone *tombstoneModel) GetScaleTeamID() int {
+	return tombstone.ScaleTeamID
+}
+
+func (tombstone *tombstoneModel) GetIntraUpdatedAt() time.Time {
+	return tombstone.IntraUpdatedAt
+}
+
+func (tombstone *tombstoneModel) GetDestroyedAt() time.Time {
+	return tombstone.DestroyedAt
+}
+
+func (tombstone *tombstoneModel) SetIntraUpdatedAt(updatedAt time.Time) {
+	tombstone.IntraUpdatedAt = updatedAt
+}
+
+func (tombstone *tombstoneModel) Save(tx *gorm.DB) error {
+	return tombstone.tombstoneManager.Update(tx, tombstone)
+}
+
+func (tombstone *tombstoneModel) Delete(tx *gorm.DB) error {
+	return tombstone.tombstoneManager.Delete(tx, tombstone)
+}
+
+type profileModel struct {
+	Login     string `gorm:"primary_key;type:varchar(32)"`
+	Email     string `gorm:"type:text"`
+	FetchedAt time.Time
+}
+
+func (profileModel) TableName() string {
+	return "profiles"
+}
+
+func (profile *profileModel) GetLogin() string {
+	return profile.Login
+}
+
+func (profile *profileModel) GetEmail() string {
+	return profile.Email
+}
+
+func (profile *profileModel) GetFetchedAt() time.Time {
+	return profile.FetchedAt
+}
@@ -21,10 +21,14 @@ func ScaleTeamIDOption(id int) GetOption {
 	}
 }
 
-// ScaleTeamNotifiedOption adds condition if the ScaleTeam is `notified`.
+// ScaleTeamNotifiedOption adds condition if the ScaleTeam is `notified`, i.e: if any of its reminders was sent.
 func ScaleTeamNotifiedOption(notified bool) GetOption {
 	return func(db *gorm.DB) *gorm.DB {
-		return db.Where(map[string]interface{}{"notified": notified})
+		query := "EXISTS (SELECT 1 FROM reminders WHERE reminders.scale_team_id = scale_teams.id AND sent_at IS NOT NULL)"
+		if !notified {
+			query = "NOT " + query
+		}
+		return db.Where(query)
 	}
 }
 
@@ -50,6 +54,50 @@ func ScaleTeamBeginAtInOption(duration time.Duration) GetOption {
 	}
 }
 
+// ScaleTeamMeetingMissingOption adds condition if the ScaleTeam's meeting was never generated.
+func ScaleTeamMeetingMissingOption() GetOption {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("room_name IS NULL OR room_name = ''")
+	}
+}
+
+// ScaleTeamRemindersMissingOption adds condition if the ScaleTeam has no planned reminders.
+func ScaleTeamRemindersMissingOption() GetOption {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("NOT EXISTS (SELECT 1 FROM reminders WHERE reminders.scale_team_id = scale_teams.id)")
+	}
+}
+
+// ScaleTeamRoomNameOption adds condition if the ScaleTeam's meeting room is named `roomName`, ignoring the case.
+func ScaleTeamRoomNameOption(roomName string) GetOption {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("LOWER(room_name) = LOWER(?)", roomName)
+	}
+}
+
+// ScaleTeamAttendanceCheckedOption adds condition if the ScaleTeam's attendance was already `checked`.
+func ScaleTeamAttendanceCheckedOption(checked bool) GetOption {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(map[string]interface{}{"attendance_checked": checked})
+	}
+}
+
+// ScaleTeamAttendanceTrackedOption adds condition if the ScaleTeam began after the first attendance ever recorded.
+//
+// The attendance of the scale teams which began before the jitsi events were received can not be known.
+func ScaleTeamAttendanceTrackedOption() GetOption {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("begin_at >= (SELECT MIN(joined_at) FROM attendances)")
+	}
+}
+
+// ScaleTeamForUpdateOption locks the selected ScaleTeams until the end of the transaction.
+func ScaleTeamForUpdateOption() GetOption {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Set("gorm:query_option", "FOR UPDATE")
+	}
+}
+
 /*
  * User Get Options
  */
@@ -81,3 +129,127 @@ func UserScaleTeamOption(scaleTeamId int) GetOption {
 		return db.Where("scale_team_id = ?", scaleTeamId)
 	}
 }
+
+/*
+ * Reminder Get Options
+ */
+
+// ReminderScaleTeamOption adds condition if Reminder's ScaleTeam id is `scaleTeamID`.
+func ReminderScaleTeamOption(scaleTeamID int) GetOption {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("scale_team_id = ?", scaleTeamID)
+	}
+}
+
+// ReminderSentOption adds condition if the Reminder was already `sent`.
+func ReminderSentOption(sent bool) GetOption {
+	return func(db *gorm.DB) *gorm.DB {
+		if sent {
+			return db.Where("sent_at IS NOT NULL")
+		}
+		return db.Where("sent_at IS NULL")
+	}
+}
+
+// ReminderScaleTeamBeginAtAfterOption adds condition if the Reminder's ScaleTeam begins after `beginAt`.
+func ReminderScaleTeamBeginAtAfterOption(beginAt time.Time) GetOption {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(
+			"EXISTS (SELECT 1 FROM scale_teams WHERE scale_teams.id = reminders.scale_team_id AND scale_teams.begin_at > ?)",
+			beginAt.Format(time.RFC3339),
+		)
+	}
+}
+
+// ReminderDueOption adds condition if the Reminder is due at or before `now`.
+func ReminderDueOption(now time.Time) GetOption {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("remind_at <= ?", now.Format(time.RFC3339))
+	}
+}
+
+/*
+ * Attendance Get Options
+ */
+
+// AttendanceScaleTeamOption adds condition if Attendance's ScaleTeam id is `scaleTeamID`.
+func AttendanceScaleTeamOption(scaleTeamID int) GetOption {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("scale_team_id = ?", scaleTeamID)
+	}
+}
+
+// AttendanceLoginOption adds condition if Attendance's login is `login`.
+func AttendanceLoginOption(login string) GetOption {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("login = ?", login)
+	}
+}
+
+// AttendanceOngoingOption adds condition if the Attendance's user did not leave the meeting yet.
+func AttendanceOngoingOption() GetOption {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("left_at IS NULL")
+	}
+}
+
+/*
+ * Tombstone Get Options
+ */
+
+// TombstoneScaleTeamOption adds condition if Tombstone's ScaleTeam id is `scaleTeamID`.
+func TombstoneScaleTeamOption(scaleTeamID int) GetOption {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("scale_team_id = ?", scaleTeamID)
+	}
+}
+
+/*
+ * NoShow Get Options
+ */
+
+// NoShowScaleTeamOption adds condition if NoShow's ScaleTeam id is `scaleTeamID`.
+func NoShowScaleTeamOption(scaleTeamID int) GetOption {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("scale_team_id = ?", scaleTeamID)
+	}
+}
+
+// NoShowLoginOption adds condition if NoShow's login is `login`.
+func NoShowLoginOption(login string) GetOption {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("login = ?", login)
+	}
+}
+
+// NoShowLatestFirstOption orders the NoShows from the latest scale team to the oldest.
+func NoShowLatestFirstOption() GetOption {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Order("begin_at DESC")
+	}
+}
+
+/*
+ * Profile Get Options
+ */
+
+// ProfileLoginOption adds condition if Profile's login is `login`.
+func ProfileLoginOption(login string) GetOption {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("login = ?", login)
+	}
+}
+
+// ProfileLoginsOption adds condition if Profile's login is one of `logins`.
+func ProfileLoginsOption(logins []string) GetOption {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("login IN (?)", logins)
+	}
+}
+
+// ProfileFetchedAfterOption adds condition if the Profile was fetched after `after`, i.e: it did not expire.
+func ProfileFetchedAfterOption(after time.Time) GetOption {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("fetched_at > ?", after.Format(time.RFC3339))
+	}
+}
@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/gustavobelfort/42-jitsi/internal/db"
+	"github.com/gustavobelfort/42-jitsi/internal/intra"
+	"github.com/gustavobelfort/42-jitsi/internal/logging"
+	"github.com/jinzhu/gorm"
+	"github.com/sirupsen/logrus"
+)
+
+// Action is what handling a scale team's payload does to its records.
+type Action string
+
+// Action constant values.
+const (
+	// CreateAction creates the records of a scale team which is not stored yet.
+	CreateAction Action = "create"
+	// RescheduleAction moves the stored scale team to its new beginning.
+	RescheduleAction Action = "reschedule"
+	// UnchangedAction leaves the stored scale team as is, apart from its version.
+	UnchangedAction Action = "unchanged"
+	// StaleAction ignores the payload as the stored scale team is at least as recent, apart from its members.
+	StaleAction Action = "stale"
+	// DestroyedAction ignores the payload as the scale team was destroyed after it.
+	DestroyedAction Action = "destroyed"
+)
+
+// Member is a participant of a scale team.
+type Member struct {
+	Login  string
+	Status db.UserStatus
+}
+
+// Change describes how a scale team's payload changes its records.
+type Change struct {
+	ScaleTeamID int
+	Action      Action
+	// From is the stored beginning of the scale team. It is zero if the scale team is not stored.
+	From time.Time
+	// To is the beginning of the scale team in the payload.
+	To time.Time
+	// Joined and Left are the members added to and removed from the stored scale team by the payload. The members are
+	// those of the team when the payload is handled, thus they are not versioned and even stale payloads update them.
+	Joined []Member
+	Left   []Member
+}
+
+// MembersChanged reports whether the payload changes the members of the stored scale team.
+func (change Change) MembersChanged() bool {
+	return len(change.Joined) != 0 || len(change.Left) != 0
+}
+
+// upsertPlan is the change of a scale team's payload along with the stored records it applies to.
+type upsertPlan struct {
+	Change
+	record db.ScaleTeam
+	left   []db.User
+}
+
+// planUpsert decides how the scale team's payload changes its records.
+func (handler *scaleTeamHandler) planUpsert(tx *gorm.DB, st *scaleTeam, options ...db.GetOption) (*upsertPlan, error) {
+	plan := &upsertPlan{Change: Change{ScaleTeamID: st.ID, To: st.BeginAt.Time}}
+
+	stRecords, err := handler.scaleTeamManager.Get(tx, append([]db.GetOption{db.ScaleTeamIDOption(st.ID)}, options...)...)
+	if err != nil {
+		return nil, err
+	}
+	if len(stRecords) != 0 {
+		plan.record = stRecords[0]
+		plan.From = plan.record.GetBeginAt()
+		switch {
+		case !st.UpdatedAt.IsZero() && !st.UpdatedAt.After(plan.record.GetIntraUpdatedAt()):
+			plan.Action = StaleAction
+		case st.BeginAt.Equal(plan.From):
+			plan.Action = UnchangedAction
+		default:
+			plan.Action = RescheduleAction
+		}
+
+		users, err := handler.userManager.Get(tx, db.UserScaleTeamOption(st.ID))
+		if err != nil {
+			return nil, err
+		}
+		plan.Joined, plan.left = diffMembers(st, users)
+		for _, user := range plan.left {
+			plan.Left = append(plan.Left, Member{Login: user.GetLogin(), Status: user.GetStatus()})
+		}
+		return plan, nil
+	}
+
+	tombstones, err := handler.tombstoneManager.Get(tx, db.TombstoneScaleTeamOption(st.ID))
+	if err != nil {
+		return nil, err
+	}
+	if len(tombstones) != 0 && !st.UpdatedAt.After(tombstones[0].GetIntraUpdatedAt()) {
+		plan.Action = DestroyedAction
+	} else {
+		plan.Action = CreateAction
+	}
+	return plan, nil
+}
+
+// diffMembers returns the members of the payload which are not stored, and the stored users who are not in the payload.
+func diffMembers(st *scaleTeam, users []db.User) ([]Member, []db.User) {
+	members := []Member{{Login: st.Corrector, Status: db.Corrector}}
+	for _, login := range st.Correcteds {
+		members = append(members, Member{Login: login, Status: db.Corrected})
+	}
+	expected := make(map[Member]bool, len(members))
+	for _, member := range members {
+		expected[member] = true
+	}
+
+	var left []db.User
+	for _, user := range users {
+		member := Member{Login: user.GetLogin(), Status: user.GetStatus()}
+		if !expected[member] {
+			left = append(left, user)
+			continue
+		}
+		delete(expected, member)
+	}
+
+	var joined []Member
+	for _, member := range members {
+		if expected[member] {
+			joined = append(joined, member)
+			delete(expected, member)
+		}
+	}
+	return joined, left
+}
+
+// Preview tells how handling the scale team of intra's API would change its records, without changing them.
+func (handler *scaleTeamHandler) Preview(ctx context.Context, intraST *intra.ScaleTeam) (Change, error) {
+	logger := logging.ContextLog(ctx, logrus.StandardLogger())
+
+	st, err := handler.interpretScaleTeam(ctx, intraST, logger)
+	if err != nil {
+		return Change{}, err
+	}
+
+	plan, err := handler.planUpsert(handler.db, st)
+	if err != nil {
+		return Change{}, err
+	}
+	return plan.Change, nil
+}
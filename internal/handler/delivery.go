@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/sirupsen/logrus"
+)
+
+// This create a private key-space in the Context, meaning that only this package can get or set "contextKey" types
+type contextKey int
+
+const deliveryKey contextKey = iota
+
+// ContextWithDelivery sets the id of the webhook's delivery being handled, i.e: its `X-Delivery` header.
+func ContextWithDelivery(ctx context.Context, deliveryID string) context.Context {
+	return context.WithValue(ctx, deliveryKey, deliveryID)
+}
+
+// ContextGetDelivery gets the id of the webhook's delivery being handled. It is empty if unknown.
+func ContextGetDelivery(ctx context.Context) string {
+	deliveryID := ""
+	if ctx != nil {
+		deliveryID, _ = ctx.Value(deliveryKey).(string)
+	}
+	return deliveryID
+}
+
+// alreadyProcessed records the delivery of the context within the transaction and reports whether it was already
+// processed. Deliveries without id are never considered processed.
+func (handler *scaleTeamHandler) alreadyProcessed(ctx context.Context, tx *gorm.DB, logger *logrus.Entry) (bool, error) {
+	deliveryID := ContextGetDelivery(ctx)
+	if deliveryID == "" {
+		return false, nil
+	}
+
+	recorded, err := handler.deliveryManager.Record(tx, deliveryID, time.Now())
+	if err != nil {
+		return false, err
+	}
+	if !recorded {
+		logger.Info("delivery already processed: ignoring it")
+	}
+	return !recorded, nil
+}
@@ -27,3 +27,13 @@ type MissingFieldsError struct {
 func (err *MissingFieldsError) Error() string {
 	return fmt.Sprintf("missing required fields: %s", strings.Join(err.missing, ","))
 }
+
+// UnknownEventError will be returned when the jitsi event is not one of the handled events.
+type UnknownEventError struct {
+	event string
+}
+
+// Error formats the UnknownEventError with the unknown event.
+func (err *UnknownEventError) Error() string {
+	return fmt.Sprintf("unknown jitsi event: '%s'", err.event)
+}
@@ -10,3 +10,8 @@ func TestMissingFieldsError(t *testing.T) {
 	error := &MissingFieldsError{missing: []string{"field1", "field2"}}
 	assert.Equal(t, "missing required fields: field1,field2", error.Error())
 }
+
+func TestUnknownEventError(t *testing.T) {
+	error := &UnknownEventError{event: "muc-room-pre-create"}
+	assert.Equal(t, "unknown jitsi event: 'muc-room-pre-create'", error.Error())
+}
@@ -1,10 +1,24 @@
 package handler
 
-import "context"
+import (
+	"context"
+
+	"github.com/gustavobelfort/42-jitsi/internal/intra"
+)
 
 // ScaleTeamHandler inputs the webhook payload of a scale_team and inserts it into the database.
 type ScaleTeamHandler interface {
 	HandleCreate(ctx context.Context, data []byte) error
 	HandleUpdate(ctx context.Context, data []byte) error
 	HandleDestroy(ctx context.Context, data []byte) error
+
+	// HandleScaleTeam handles a scale team of intra's API as an update, the same way as the webhooks.
+	HandleScaleTeam(ctx context.Context, st *intra.ScaleTeam) error
+	// Preview tells how the scale team of intra's API would change the database if it was handled.
+	Preview(ctx context.Context, st *intra.ScaleTeam) (Change, error)
+}
+
+// JitsiEventHandler inputs the events of the jitsi deployment and records the attendance of the scale teams' meetings.
+type JitsiEventHandler interface {
+	HandleEvent(ctx context.Context, data []byte) error
 }
@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/gustavobelfort/42-jitsi/internal/db"
+	"github.com/gustavobelfort/42-jitsi/internal/logging"
+	"github.com/gustavobelfort/42-jitsi/internal/utils"
+	"github.com/jinzhu/gorm"
+	"github.com/sirupsen/logrus"
+)
+
+type jitsiEventHandler struct {
+	db *gorm.DB
+
+	scaleTeamManager  db.ScaleTeamManager
+	attendanceManager db.AttendanceManager
+}
+
+// NewJitsiEventHandler returns a new handler that will record the jitsi events with the given db managers.
+func NewJitsiEventHandler(dbInstance *gorm.DB) JitsiEventHandler {
+	return &jitsiEventHandler{
+		db: dbInstance,
+
+		scaleTeamManager:  db.NewScaleTeamManager(dbInstance),
+		attendanceManager: db.NewAttendanceManager(dbInstance),
+	}
+}
+
+func (handler *jitsiEventHandler) HandleEvent(ctx context.Context, data []byte) error {
+	logger := logging.ContextLog(ctx, logrus.StandardLogger())
+
+	event := &jitsiEvent{}
+	logger.Info("parsing jitsi event's payload")
+	err := utils.WrapContext(ctx, func() error {
+		return json.Unmarshal(data, event)
+	})
+	if err != nil {
+		return logging.WithLog(err, logrus.WarnLevel, nil)
+	}
+
+	logger = logger.WithFields(logrus.Fields{"jitsi_event": event.Name, "room_name": event.RoomName})
+	return handler.recordEvent(handler.db.BeginTx(ctx, &sql.TxOptions{}), event, logger)
+}
+
+func (handler *jitsiEventHandler) recordEvent(tx *gorm.DB, event *jitsiEvent, logger *logrus.Entry) error {
+	defer tx.RollbackUnlessCommitted()
+
+	logger.Info("getting the scale team of the room")
+	stRecords, err := handler.scaleTeamManager.Get(tx, db.ScaleTeamRoomNameOption(event.RoomName))
+	if err != nil {
+		return err
+	}
+	if len(stRecords) == 0 {
+		logger.Info("the room does not belong to any scale team: ignoring event")
+		return nil
+	}
+	scaleTeamID := stRecords[0].GetID()
+	logger = logger.WithField("scale_team_id", scaleTeamID)
+
+	switch event.Name {
+	case roomCreatedEvent:
+		logger.Info("the scale team's room was created")
+		return nil
+	case occupantJoinedEvent:
+		err = handler.recordJoin(tx, scaleTeamID, event.Occupant, logger)
+	case occupantLeftEvent:
+		err = handler.recordLeave(tx, scaleTeamID, event.Occupant, logger)
+	case roomDestroyedEvent:
+		err = handler.recordDestroy(tx, scaleTeamID, orNow(event.DestroyedAt), logger)
+	}
+	if err != nil {
+		return err
+	}
+	return tx.Commit().Error
+}
+
+func (handler *jitsiEventHandler) recordJoin(tx *gorm.DB, scaleTeamID int, occupant *jitsiOccupant, logger *logrus.Entry) error {
+	if occupant.ID == "" {
+		logger.WithField("name", occupant.Name).Warn("the occupant is not authenticated: ignoring event")
+		return nil
+	}
+
+	logger.WithField("login", occupant.ID).Info("creating the occupant's attendance record")
+	_, err := handler.attendanceManager.Create(tx, scaleTeamID, occupant.ID, orNow(occupant.JoinedAt))
+	return err
+}
+
+func (handler *jitsiEventHandler) recordLeave(tx *gorm.DB, scaleTeamID int, occupant *jitsiOccupant, logger *logrus.Entry) error {
+	if occupant.ID == "" {
+		logger.WithField("name", occupant.Name).Warn("the occupant is not authenticated: ignoring event")
+		return nil
+	}
+	logger = logger.WithField("login", occupant.ID)
+	leftAt := orNow(occupant.LeftAt)
+
+	logger.Info("getting the occupant's ongoing attendance records")
+	attendances, err := handler.attendanceManager.Get(tx,
+		db.AttendanceScaleTeamOption(scaleTeamID),
+		db.AttendanceLoginOption(occupant.ID),
+		db.AttendanceOngoingOption(),
+	)
+	if err != nil {
+		return err
+	}
+
+	if len(attendances) == 0 {
+		logger.Warn("the occupant's join was not recorded: creating the attendance record")
+		attendance, err := handler.attendanceManager.Create(tx, scaleTeamID, occupant.ID, orNow(occupant.JoinedAt))
+		if err != nil {
+			return err
+		}
+		attendances = []db.Attendance{attendance}
+	}
+
+	logger.Info("updating the occupant's attendance records")
+	for _, attendance := range attendances {
+		attendance.SetLeftAt(&leftAt)
+		if err := attendance.Save(tx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (handler *jitsiEventHandler) recordDestroy(tx *gorm.DB, scaleTeamID int, destroyedAt time.Time, logger *logrus.Entry) error {
+	logger.Info("getting the room's ongoing attendance records")
+	attendances, err := handler.attendanceManager.Get(tx,
+		db.AttendanceScaleTeamOption(scaleTeamID),
+		db.AttendanceOngoingOption(),
+	)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("closing the room's ongoing attendance records")
+	for _, attendance := range attendances {
+		attendance.SetLeftAt(&destroyedAt)
+		if err := attendance.Save(tx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// orNow returns the time of the event, or the current time if it was not sent.
+func orNow(t epochTime) time.Time {
+	if t.IsZero() {
+		return time.Now()
+	}
+	return t.Time
+}
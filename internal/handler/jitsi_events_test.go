@@ -0,0 +1,204 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gustavobelfort/42-jitsi/internal/db"
+	"github.com/gustavobelfort/42-jitsi/internal/logging"
+	"github.com/jinzhu/gorm"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestJitsiEventHandler(t *testing.T) {
+	t.Run("NewJitsiEventHandler", func(t *testing.T) {
+		db := &gorm.DB{}
+
+		handler := NewJitsiEventHandler(db)
+		require.IsType(t, &jitsiEventHandler{}, handler)
+
+		eHandler := handler.(*jitsiEventHandler)
+
+		assert.Equal(t, db, eHandler.db)
+		assert.Equal(t, db, eHandler.scaleTeamManager.DB())
+		assert.Equal(t, db, eHandler.attendanceManager.DB())
+	})
+
+	suite.Run(t, new(JitsiEventHandlerSuite))
+}
+
+type JitsiEventHandlerSuite struct {
+	suite.Suite
+
+	handler *jitsiEventHandler
+
+	stMock *ScaleTeamManagerMock
+	aMock  *AttendanceManagerMock
+
+	db     *gorm.DB
+	dbMock sqlmock.Sqlmock
+}
+
+func (s *JitsiEventHandlerSuite) SetupSuite() {
+	var (
+		db  *sql.DB
+		err error
+	)
+
+	db, s.dbMock, err = sqlmock.New()
+	s.Require().NoError(err)
+
+	s.db, err = gorm.Open("postgres", db)
+	s.Require().NoError(err)
+}
+
+func (s *JitsiEventHandlerSuite) SetupTest() {
+	s.stMock = &ScaleTeamManagerMock{}
+	s.aMock = &AttendanceManagerMock{}
+
+	s.handler = &jitsiEventHandler{
+		db:                s.db,
+		scaleTeamManager:  s.stMock,
+		attendanceManager: s.aMock,
+	}
+}
+
+// expectScaleTeam sets the expectation to find the scale team with the id 21 from its room name.
+func (s *JitsiEventHandlerSuite) expectScaleTeam() {
+	recordMock := &ScaleTeamMock{}
+	recordMock.On("GetID").Return(21).Once()
+	s.stMock.On("Get", mock.Anything, mock.Anything).Return([]db.ScaleTeam{recordMock}, nil).Once()
+}
+
+func (s *JitsiEventHandlerSuite) Test00_HandleEvent_RoomCreated() {
+	s.dbMock.ExpectBegin()
+	s.dbMock.ExpectRollback()
+	s.expectScaleTeam()
+
+	payload := []byte(`{"event_name": "muc-room-created", "room_name": "room", "created_at": 1594846800}`)
+	s.NoError(s.handler.HandleEvent(context.Background(), payload))
+}
+
+func (s *JitsiEventHandlerSuite) Test01_HandleEvent_OccupantJoined() {
+	s.dbMock.ExpectBegin()
+	s.dbMock.ExpectCommit()
+	s.expectScaleTeam()
+
+	s.aMock.On("Create", mock.Anything, 21, "xlogin", time.Unix(1594846800, 0)).Return(&AttendanceMock{}, nil).Once()
+
+	payload := []byte(`{"event_name": "muc-occupant-joined", "room_name": "room", "occupant": {"id": "xlogin", "joined_at": 1594846800}}`)
+	s.NoError(s.handler.HandleEvent(context.Background(), payload))
+}
+
+func (s *JitsiEventHandlerSuite) Test02_HandleEvent_OccupantJoined_Anonymous() {
+	s.dbMock.ExpectBegin()
+	s.dbMock.ExpectCommit()
+	s.expectScaleTeam()
+
+	payload := []byte(`{"event_name": "muc-occupant-joined", "room_name": "room", "occupant": {"name": "Guest", "joined_at": 1594846800}}`)
+	s.NoError(s.handler.HandleEvent(context.Background(), payload))
+}
+
+func (s *JitsiEventHandlerSuite) Test03_HandleEvent_OccupantLeft() {
+	s.dbMock.ExpectBegin()
+	s.dbMock.ExpectCommit()
+	s.expectScaleTeam()
+
+	expectedLeftAt := time.Unix(1594850400, 0)
+	attendance := &AttendanceMock{}
+	defer attendance.AssertExpectations(s.T())
+	s.aMock.On("Get", mock.Anything, mock.Anything).Return([]db.Attendance{attendance}, nil).Once()
+	attendance.On("SetLeftAt", &expectedLeftAt).Return().Once()
+	attendance.On("Save", mock.Anything).Return(nil).Once()
+
+	payload := []byte(`{"event_name": "muc-occupant-left", "room_name": "room", "occupant": {"id": "xlogin", "joined_at": 1594846800, "left_at": 1594850400}}`)
+	s.NoError(s.handler.HandleEvent(context.Background(), payload))
+}
+
+func (s *JitsiEventHandlerSuite) Test04_HandleEvent_OccupantLeft_JoinMissed() {
+	s.dbMock.ExpectBegin()
+	s.dbMock.ExpectCommit()
+	s.expectScaleTeam()
+
+	expectedLeftAt := time.Unix(1594850400, 0)
+	attendance := &AttendanceMock{}
+	defer attendance.AssertExpectations(s.T())
+	s.aMock.On("Get", mock.Anything, mock.Anything).Return([]db.Attendance{}, nil).Once()
+	s.aMock.On("Create", mock.Anything, 21, "xlogin", time.Unix(1594846800, 0)).Return(attendance, nil).Once()
+	attendance.On("SetLeftAt", &expectedLeftAt).Return().Once()
+	attendance.On("Save", mock.Anything).Return(nil).Once()
+
+	payload := []byte(`{"event_name": "muc-occupant-left", "room_name": "room", "occupant": {"id": "xlogin", "joined_at": 1594846800, "left_at": 1594850400}}`)
+	s.NoError(s.handler.HandleEvent(context.Background(), payload))
+}
+
+func (s *JitsiEventHandlerSuite) Test05_HandleEvent_RoomDestroyed() {
+	s.dbMock.ExpectBegin()
+	s.dbMock.ExpectCommit()
+	s.expectScaleTeam()
+
+	expectedDestroyedAt := time.Unix(1594850400, 0)
+	attendance := &AttendanceMock{}
+	defer attendance.AssertExpectations(s.T())
+	s.aMock.On("Get", mock.Anything, mock.Anything).Return([]db.Attendance{attendance}, nil).Once()
+	attendance.On("SetLeftAt", &expectedDestroyedAt).Return().Once()
+	attendance.On("Save", mock.Anything).Return(nil).Once()
+
+	payload := []byte(`{"event_name": "muc-room-destroyed", "room_name": "room", "destroyed_at": 1594850400}`)
+	s.NoError(s.handler.HandleEvent(context.Background(), payload))
+}
+
+func (s *JitsiEventHandlerSuite) Test06_HandleEvent_UnknownRoom() {
+	s.dbMock.ExpectBegin()
+	s.dbMock.ExpectRollback()
+	s.stMock.On("Get", mock.Anything, mock.Anything).Return([]db.ScaleTeam{}, nil).Once()
+
+	payload := []byte(`{"event_name": "muc-occupant-joined", "room_name": "someone-elses-room", "occupant": {"id": "xlogin"}}`)
+	s.NoError(s.handler.HandleEvent(context.Background(), payload))
+}
+
+func (s *JitsiEventHandlerSuite) Test07_HandleEvent_PayloadError() {
+	err := s.handler.HandleEvent(context.Background(), []byte(`{"event_name": "muc-occupant-joined"}`))
+	s.Error(err)
+
+	logError := &logging.WithLogError{}
+	s.Require().True(errors.As(err, &logError))
+	s.Equal(logrus.WarnLevel, logError.LogLevel)
+}
+
+func (s *JitsiEventHandlerSuite) Test08_HandleEvent_GetError() {
+	s.dbMock.ExpectBegin()
+	s.dbMock.ExpectRollback()
+
+	expectedError := errors.New("testing")
+	s.stMock.On("Get", mock.Anything, mock.Anything).Return([]db.ScaleTeam{}, expectedError).Once()
+
+	payload := []byte(`{"event_name": "muc-room-created", "room_name": "room"}`)
+	s.Equal(expectedError, s.handler.HandleEvent(context.Background(), payload))
+}
+
+func (s *JitsiEventHandlerSuite) Test09_HandleEvent_CreateError() {
+	s.dbMock.ExpectBegin()
+	s.dbMock.ExpectRollback()
+	s.expectScaleTeam()
+
+	expectedError := errors.New("testing")
+	s.aMock.On("Create", mock.Anything, 21, "xlogin", mock.Anything).Return(&AttendanceMock{}, expectedError).Once()
+
+	payload := []byte(`{"event_name": "muc-occupant-joined", "room_name": "room", "occupant": {"id": "xlogin"}}`)
+	s.Equal(expectedError, s.handler.HandleEvent(context.Background(), payload))
+}
+
+func (s *JitsiEventHandlerSuite) TearDownTest() {
+	s.stMock.AssertExpectations(s.T())
+	s.aMock.AssertExpectations(s.T())
+	s.NoError(s.dbMock.ExpectationsWereMet())
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/gustavobelfort/42-jitsi/internal/config"
+	"github.com/gustavobelfort/42-jitsi/internal/intra"
 )
 
 // customTime will allow us to support unmarshalling time that is not RFC 3339
@@ -28,11 +29,13 @@ func (ct *customTime) UnmarshalJSON(d []byte) error {
 }
 
 type scaleTeam struct {
-	ID         int        `json:"id"`
-	BeginAt    customTime `json:"begin_at"`
-	Corrector  string
-	Correcteds []string
-	TeamID     int
+	ID      int        `json:"id"`
+	BeginAt customTime `json:"begin_at"`
+	// UpdatedAt is the version of the evaluation. It is zero if the payload does not have any.
+	UpdatedAt  customTime `json:"updated_at"`
+	Corrector  string     `json:"-"`
+	Correcteds []string   `json:"-"`
+	TeamID     int        `json:"-"`
 }
 
 func (st *scaleTeam) validate() error {
@@ -81,3 +84,110 @@ func (st *scaleTeam) UnmarshalJSON(d []byte) error {
 
 	return st.validate()
 }
+
+// newScaleTeam converts a scale team of intra's API into the scaleTeam structure.
+func newScaleTeam(intraST *intra.ScaleTeam) (*scaleTeam, error) {
+	st := &scaleTeam{
+		ID:        intraST.ID,
+		BeginAt:   customTime{intraST.BeginAt},
+		UpdatedAt: customTime{intraST.UpdatedAt},
+		Corrector: intraST.Corrector.Login,
+		TeamID:    intraST.Team.ID,
+	}
+	if err := st.validate(); err != nil {
+		return nil, err
+	}
+	return st, nil
+}
+
+// destroyedScaleTeam is the payload of a destroyed evaluation.
+type destroyedScaleTeam struct {
+	ID        int        `json:"id"`
+	UpdatedAt customTime `json:"updated_at"`
+}
+
+// UnmarshalJSON will unmarshal the destroyed evaluation's payload into the destroyedScaleTeam structure.
+func (st *destroyedScaleTeam) UnmarshalJSON(d []byte) error {
+	type destroyedScaleTeamTwin destroyedScaleTeam
+	if err := json.Unmarshal(d, (*destroyedScaleTeamTwin)(st)); err != nil {
+		return err
+	}
+	if st.ID == 0 {
+		return &MissingFieldsError{missing: []string{"id"}}
+	}
+	return nil
+}
+
+// Jitsi events, as sent by prosody's event_sync module.
+const (
+	roomCreatedEvent    = "muc-room-created"
+	roomDestroyedEvent  = "muc-room-destroyed"
+	occupantJoinedEvent = "muc-occupant-joined"
+	occupantLeftEvent   = "muc-occupant-left"
+)
+
+// epochTime will allow us to support unmarshalling the unix timestamps of the jitsi events.
+type epochTime struct {
+	time.Time
+}
+
+func (et *epochTime) UnmarshalJSON(d []byte) error {
+	if string(d) == "null" {
+		return nil
+	}
+	var seconds float64
+	if err := json.Unmarshal(d, &seconds); err != nil {
+		return err
+	}
+	et.Time = time.Unix(0, int64(seconds*float64(time.Second)))
+	return nil
+}
+
+type jitsiOccupant struct {
+	// ID is the `context.user.id` claim of the occupant's token, thus their login.
+	ID       string    `json:"id"`
+	Name     string    `json:"name"`
+	JoinedAt epochTime `json:"joined_at"`
+	LeftAt   epochTime `json:"left_at"`
+}
+
+type jitsiEvent struct {
+	Name        string         `json:"event_name"`
+	RoomName    string         `json:"room_name"`
+	Occupant    *jitsiOccupant `json:"occupant"`
+	CreatedAt   epochTime      `json:"created_at"`
+	DestroyedAt epochTime      `json:"destroyed_at"`
+}
+
+func (event *jitsiEvent) validate() error {
+	missing := make([]string, 0)
+	if event.Name == "" {
+		missing = append(missing, "event_name")
+	}
+	if event.RoomName == "" {
+		missing = append(missing, "room_name")
+	}
+	if (event.Name == occupantJoinedEvent || event.Name == occupantLeftEvent) && event.Occupant == nil {
+		missing = append(missing, "occupant")
+	}
+
+	if len(missing) != 0 {
+		return &MissingFieldsError{missing: missing}
+	}
+
+	switch event.Name {
+	case roomCreatedEvent, roomDestroyedEvent, occupantJoinedEvent, occupantLeftEvent:
+		return nil
+	default:
+		return &UnknownEventError{event: event.Name}
+	}
+}
+
+// UnmarshalJSON will unmarshal the jitsi event's payload into the jitsiEvent structure.
+func (event *jitsiEvent) UnmarshalJSON(d []byte) error {
+	type jitsiEventTwin jitsiEvent
+	if err := json.Unmarshal(d, (*jitsiEventTwin)(event)); err != nil {
+		return err
+	}
+	return event.validate()
+}
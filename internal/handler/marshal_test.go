@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/gustavobelfort/42-jitsi/internal/intra"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -79,4 +80,123 @@ func TestScaleTeamMarshal(t *testing.T) {
 		assert.Error(t, err)
 		assert.Equal(t, NoCorrectorError, err)
 	})
+
+	t.Run("WithVersion", func(t *testing.T) {
+		st := &scaleTeam{}
+		assert.NoError(t, json.Unmarshal([]byte(`{
+	"id": 21,
+	"begin_at": "2020-07-15T21:00:00.000Z",
+	"updated_at": "2020-07-14T10:30:00.000Z",
+	"user": {"login": "xlogin"},
+	"team": {"id": 42}
+}`), &st))
+		assert.Equal(t, time.Date(2020, 7, 14, 10, 30, 0, 0, time.UTC), st.UpdatedAt.UTC())
+	})
+
+}
+
+func TestNewScaleTeam(t *testing.T) {
+	t.Run("ValidScaleTeam", func(t *testing.T) {
+		st, err := newScaleTeam(&intra.ScaleTeam{
+			ID:        21,
+			BeginAt:   time.Date(2020, 7, 15, 21, 0, 0, 0, time.UTC),
+			UpdatedAt: time.Date(2020, 7, 14, 10, 30, 0, 0, time.UTC),
+			Corrector: intra.Corrector{ID: 1, Login: "xlogin"},
+			Team:      intra.Team{ID: 42},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, &scaleTeam{
+			ID:        21,
+			BeginAt:   customTime{time.Date(2020, 7, 15, 21, 0, 0, 0, time.UTC)},
+			UpdatedAt: customTime{time.Date(2020, 7, 14, 10, 30, 0, 0, time.UTC)},
+			Corrector: "xlogin",
+			TeamID:    42,
+		}, st)
+	})
+
+	t.Run("InvisibleCorrector", func(t *testing.T) {
+		_, err := newScaleTeam(&intra.ScaleTeam{
+			ID:      21,
+			BeginAt: time.Date(2020, 7, 15, 21, 0, 0, 0, time.UTC),
+			Team:    intra.Team{ID: 42},
+		})
+		assert.Equal(t, NoCorrectorError, err)
+	})
+}
+
+func TestDestroyedScaleTeamMarshal(t *testing.T) {
+	t.Run("ValidPayload", func(t *testing.T) {
+		st := &destroyedScaleTeam{}
+		assert.NoError(t, json.Unmarshal([]byte(`{"id": 21, "updated_at": "2020-07-14T10:30:00.000Z"}`), st))
+		assert.Equal(t, 21, st.ID)
+		assert.Equal(t, time.Date(2020, 7, 14, 10, 30, 0, 0, time.UTC), st.UpdatedAt.UTC())
+	})
+
+	t.Run("WithoutVersion", func(t *testing.T) {
+		st := &destroyedScaleTeam{}
+		assert.NoError(t, json.Unmarshal([]byte(`{"id": 21}`), st))
+		assert.True(t, st.UpdatedAt.IsZero())
+	})
+
+	t.Run("IncompletePayload", func(t *testing.T) {
+		err := json.Unmarshal([]byte(`{"updated_at": "2020-07-14T10:30:00.000Z"}`), &destroyedScaleTeam{})
+		assert.Error(t, err)
+		assert.Equal(t, "missing required fields: id", err.Error())
+	})
+}
+
+func TestJitsiEventMarshal(t *testing.T) {
+	t.Run("OccupantJoined", func(t *testing.T) {
+		payload := []byte(`{
+	"event_name": "muc-occupant-joined",
+	"room_name": "evaluation-49793d8cb8a852d55b533608895e8683",
+	"room_jid": "evaluation-49793d8cb8a852d55b533608895e8683@conference.meet.jitsi",
+	"occupant": {"id": "xlogin", "name": "Xavier Login", "occupant_jid": "xlogin@meet.jitsi", "joined_at": 1594846800.5}
+}`)
+
+		event := &jitsiEvent{}
+		assert.NoError(t, json.Unmarshal(payload, event))
+		assert.Equal(t, occupantJoinedEvent, event.Name)
+		assert.Equal(t, "evaluation-49793d8cb8a852d55b533608895e8683", event.RoomName)
+		if assert.NotNil(t, event.Occupant) {
+			assert.Equal(t, "xlogin", event.Occupant.ID)
+			assert.True(t, time.Unix(1594846800, 5e8).Equal(event.Occupant.JoinedAt.Time))
+			assert.True(t, event.Occupant.LeftAt.IsZero())
+		}
+	})
+
+	t.Run("RoomDestroyed", func(t *testing.T) {
+		payload := []byte(`{"event_name": "muc-room-destroyed", "room_name": "room", "destroyed_at": 1594850400, "all_occupants": []}`)
+
+		event := &jitsiEvent{}
+		assert.NoError(t, json.Unmarshal(payload, event))
+		assert.True(t, time.Unix(1594850400, 0).Equal(event.DestroyedAt.Time))
+	})
+
+	t.Run("MissingOccupant", func(t *testing.T) {
+		event := &jitsiEvent{}
+		err := json.Unmarshal([]byte(`{"event_name": "muc-occupant-left", "room_name": "room"}`), event)
+		assert.Error(t, err)
+		assert.Equal(t, "missing required fields: occupant", err.Error())
+	})
+
+	t.Run("IncompletePayload", func(t *testing.T) {
+		event := &jitsiEvent{}
+		err := json.Unmarshal([]byte(`{}`), event)
+		assert.Error(t, err)
+		assert.Equal(t, "missing required fields: event_name,room_name", err.Error())
+	})
+
+	t.Run("UnknownEvent", func(t *testing.T) {
+		event := &jitsiEvent{}
+		err := json.Unmarshal([]byte(`{"event_name": "muc-room-pre-create", "room_name": "room"}`), event)
+		assert.Error(t, err)
+		assert.IsType(t, &UnknownEventError{}, err)
+	})
+
+	t.Run("InvalidTimestamp", func(t *testing.T) {
+		event := &jitsiEvent{}
+		err := json.Unmarshal([]byte(`{"event_name": "muc-room-created", "room_name": "room", "created_at": "yesterday"}`), event)
+		assert.Error(t, err)
+	})
 }
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/gustavobelfort/42-jitsi/internal/db"
+	"github.com/gustavobelfort/42-jitsi/internal/notify"
 	"github.com/jinzhu/gorm"
 	"github.com/stretchr/testify/mock"
 )
@@ -18,18 +19,12 @@ func (m *TxManagerMock) BeginTx(ctx context.Context, options *sql.TxOptions) *go
 	return m.Called(ctx, options).Get(0).(*gorm.DB)
 }
 
-type ClientMock struct {
+type NotifierMock struct {
 	mock.Mock
 }
 
-func (m *ClientMock) GetUserEmail(ctx context.Context, login string) (string, error) {
-	toReturn := m.Called(ctx, login)
-	return toReturn.String(0), toReturn.Error(1)
-}
-
-func (m *ClientMock) GetTeamMembers(ctx context.Context, teamID int) ([]string, error) {
-	toReturn := m.Called(ctx, teamID)
-	return toReturn.Get(0).([]string), toReturn.Error(1)
+func (m *NotifierMock) Notify(message notify.Message) error {
+	return m.Called(message).Error(0)
 }
 
 type ScaleTeamManagerMock struct {
@@ -40,8 +35,8 @@ func (m *ScaleTeamManagerMock) DB() *gorm.DB {
 	return m.Called().Get(0).(*gorm.DB)
 }
 
-func (m *ScaleTeamManagerMock) Create(tx *gorm.DB, id int, beginAt time.Time, notified bool) (db.ScaleTeam, error) {
-	toReturn := m.Called(tx, id, beginAt, notified)
+func (m *ScaleTeamManagerMock) Create(tx *gorm.DB, id int, beginAt, intraUpdatedAt time.Time, meeting db.Meeting) (db.ScaleTeam, error) {
+	toReturn := m.Called(tx, id, beginAt, intraUpdatedAt, meeting)
 	return toReturn.Get(0).(db.ScaleTeam), toReturn.Error(1)
 }
 
@@ -49,6 +44,10 @@ func (m *ScaleTeamManagerMock) Update(tx *gorm.DB, scaleTeam db.ScaleTeam) error
 	return m.Called(tx, scaleTeam).Error(0)
 }
 
+func (m *ScaleTeamManagerMock) SetAttendanceChecked(tx *gorm.DB, id int) error {
+	return m.Called(tx, id).Error(0)
+}
+
 func (m *ScaleTeamManagerMock) Delete(tx *gorm.DB, scaleTeam db.ScaleTeam) error {
 	return m.Called(tx, scaleTeam).Error(0)
 }
@@ -84,6 +83,134 @@ func (m *UserManagerMock) Get(tx *gorm.DB, options ...db.GetOption) ([]db.User,
 	return toReturn.Get(0).([]db.User), toReturn.Error(1)
 }
 
+type ReminderManagerMock struct {
+	mock.Mock
+}
+
+func (m *ReminderManagerMock) DB() *gorm.DB {
+	return m.Called().Get(0).(*gorm.DB)
+}
+
+func (m *ReminderManagerMock) Create(tx *gorm.DB, scaleTeamID int, remindBefore time.Duration, remindAt time.Time) (db.Reminder, error) {
+	toReturn := m.Called(tx, scaleTeamID, remindBefore, remindAt)
+	return toReturn.Get(0).(db.Reminder), toReturn.Error(1)
+}
+
+func (m *ReminderManagerMock) Update(tx *gorm.DB, reminder db.Reminder) error {
+	return m.Called(tx, reminder).Error(0)
+}
+
+func (m *ReminderManagerMock) SetSent(tx *gorm.DB, id int, sentAt time.Time) error {
+	return m.Called(tx, id, sentAt).Error(0)
+}
+
+func (m *ReminderManagerMock) Delete(tx *gorm.DB, reminder db.Reminder) error {
+	return m.Called(tx, reminder).Error(0)
+}
+
+func (m *ReminderManagerMock) Get(tx *gorm.DB, options ...db.GetOption) ([]db.Reminder, error) {
+	toReturn := m.Called(tx, options)
+	return toReturn.Get(0).([]db.Reminder), toReturn.Error(1)
+}
+
+type DeliveryManagerMock struct {
+	mock.Mock
+}
+
+func (m *DeliveryManagerMock) DB() *gorm.DB {
+	return m.Called().Get(0).(*gorm.DB)
+}
+
+func (m *DeliveryManagerMock) Record(tx *gorm.DB, id string, processedAt time.Time) (bool, error) {
+	toReturn := m.Called(tx, id, processedAt)
+	return toReturn.Bool(0), toReturn.Error(1)
+}
+
+func (m *DeliveryManagerMock) Purge(tx *gorm.DB, before time.Time) (int64, error) {
+	toReturn := m.Called(tx, before)
+	return toReturn.Get(0).(int64), toReturn.Error(1)
+}
+
+type TombstoneManagerMock struct {
+	mock.Mock
+}
+
+func (m *TombstoneManagerMock) DB() *gorm.DB {
+	return m.Called().Get(0).(*gorm.DB)
+}
+
+func (m *TombstoneManagerMock) Create(tx *gorm.DB, scaleTeamID int, intraUpdatedAt, destroyedAt time.Time) (db.Tombstone, error) {
+	toReturn := m.Called(tx, scaleTeamID, intraUpdatedAt, destroyedAt)
+	return toReturn.Get(0).(db.Tombstone), toReturn.Error(1)
+}
+
+func (m *TombstoneManagerMock) Update(tx *gorm.DB, tombstone db.Tombstone) error {
+	return m.Called(tx, tombstone).Error(0)
+}
+
+func (m *TombstoneManagerMock) Delete(tx *gorm.DB, tombstone db.Tombstone) error {
+	return m.Called(tx, tombstone).Error(0)
+}
+
+func (m *TombstoneManagerMock) Get(tx *gorm.DB, options ...db.GetOption) ([]db.Tombstone, error) {
+	toReturn := m.Called(tx, options)
+	return toReturn.Get(0).([]db.Tombstone), toReturn.Error(1)
+}
+
+type TombstoneMock struct {
+	mock.Mock
+}
+
+func (m *TombstoneMock) GetScaleTeamID() int {
+	return m.Called().Int(0)
+}
+
+func (m *TombstoneMock) GetIntraUpdatedAt() time.Time {
+	return m.Called().Get(0).(time.Time)
+}
+
+func (m *TombstoneMock) GetDestroyedAt() time.Time {
+	return m.Called().Get(0).(time.Time)
+}
+
+func (m *TombstoneMock) SetIntraUpdatedAt(updatedAt time.Time) {
+	m.Called(updatedAt)
+}
+
+func (m *TombstoneMock) Save(tx *gorm.DB) error {
+	return m.Called(tx).Error(0)
+}
+
+func (m *TombstoneMock) Delete(tx *gorm.DB) error {
+	return m.Called(tx).Error(0)
+}
+
+type AttendanceManagerMock struct {
+	mock.Mock
+}
+
+func (m *AttendanceManagerMock) DB() *gorm.DB {
+	return m.Called().Get(0).(*gorm.DB)
+}
+
+func (m *AttendanceManagerMock) Create(tx *gorm.DB, scaleTeamID int, login string, joinedAt time.Time) (db.Attendance, error) {
+	toReturn := m.Called(tx, scaleTeamID, login, joinedAt)
+	return toReturn.Get(0).(db.Attendance), toReturn.Error(1)
+}
+
+func (m *AttendanceManagerMock) Update(tx *gorm.DB, attendance db.Attendance) error {
+	return m.Called(tx, attendance).Error(0)
+}
+
+func (m *AttendanceManagerMock) Delete(tx *gorm.DB, attendance db.Attendance) error {
+	return m.Called(tx, attendance).Error(0)
+}
+
+func (m *AttendanceManagerMock) Get(tx *gorm.DB, options ...db.GetOption) ([]db.Attendance, error) {
+	toReturn := m.Called(tx, options)
+	return toReturn.Get(0).([]db.Attendance), toReturn.Error(1)
+}
+
 type ScaleTeamMock struct {
 	mock.Mock
 }
@@ -96,10 +223,18 @@ func (m *ScaleTeamMock) GetBeginAt() time.Time {
 	return m.Called().Get(0).(time.Time)
 }
 
-func (m *ScaleTeamMock) GetNotified() bool {
+func (m *ScaleTeamMock) GetMeeting() db.Meeting {
+	return m.Called().Get(0).(db.Meeting)
+}
+
+func (m *ScaleTeamMock) GetAttendanceChecked() bool {
 	return m.Called().Bool(0)
 }
 
+func (m *ScaleTeamMock) GetIntraUpdatedAt() time.Time {
+	return m.Called().Get(0).(time.Time)
+}
+
 func (m *ScaleTeamMock) Get(tx *gorm.DB, options ...db.GetOption) ([]db.User, error) {
 	toReturn := m.Called(tx, options)
 	return toReturn.Get(0).([]db.User), toReturn.Error(1)
@@ -113,8 +248,16 @@ func (m *ScaleTeamMock) SetBeginAt(beginAt time.Time) {
 	m.Called(beginAt)
 }
 
-func (m *ScaleTeamMock) SetNotified(notified bool) {
-	m.Called(notified)
+func (m *ScaleTeamMock) SetMeeting(meeting db.Meeting) {
+	m.Called(meeting)
+}
+
+func (m *ScaleTeamMock) SetAttendanceChecked(checked bool) {
+	m.Called(checked)
+}
+
+func (m *ScaleTeamMock) SetIntraUpdatedAt(updatedAt time.Time) {
+	m.Called(updatedAt)
 }
 
 func (m *ScaleTeamMock) Save(tx *gorm.DB) error {
@@ -164,3 +307,79 @@ func (m *UserMock) Save(tx *gorm.DB) error {
 func (m *UserMock) Delete(tx *gorm.DB) error {
 	return m.Called(tx).Error(0)
 }
+
+type ReminderMock struct {
+	mock.Mock
+}
+
+func (m *ReminderMock) GetID() int {
+	return m.Called().Int(0)
+}
+
+func (m *ReminderMock) GetScaleTeamID() int {
+	return m.Called().Int(0)
+}
+
+func (m *ReminderMock) GetRemindBefore() time.Duration {
+	return m.Called().Get(0).(time.Duration)
+}
+
+func (m *ReminderMock) GetRemindAt() time.Time {
+	return m.Called().Get(0).(time.Time)
+}
+
+func (m *ReminderMock) GetSentAt() *time.Time {
+	return m.Called().Get(0).(*time.Time)
+}
+
+func (m *ReminderMock) SetRemindAt(remindAt time.Time) {
+	m.Called(remindAt)
+}
+
+func (m *ReminderMock) SetSentAt(sentAt *time.Time) {
+	m.Called(sentAt)
+}
+
+func (m *ReminderMock) Save(tx *gorm.DB) error {
+	return m.Called(tx).Error(0)
+}
+
+func (m *ReminderMock) Delete(tx *gorm.DB) error {
+	return m.Called(tx).Error(0)
+}
+
+type AttendanceMock struct {
+	mock.Mock
+}
+
+func (m *AttendanceMock) GetID() int {
+	return m.Called().Int(0)
+}
+
+func (m *AttendanceMock) GetScaleTeamID() int {
+	return m.Called().Int(0)
+}
+
+func (m *AttendanceMock) GetLogin() string {
+	return m.Called().String(0)
+}
+
+func (m *AttendanceMock) GetJoinedAt() time.Time {
+	return m.Called().Get(0).(time.Time)
+}
+
+func (m *AttendanceMock) GetLeftAt() *time.Time {
+	return m.Called().Get(0).(*time.Time)
+}
+
+func (m *AttendanceMock) SetLeftAt(leftAt *time.Time) {
+	m.Called(leftAt)
+}
+
+func (m *AttendanceMock) Save(tx *gorm.DB) error {
+	return m.Called(tx).Error(0)
+}
+
+func (m *AttendanceMock) Delete(tx *gorm.DB) error {
+	return m.Called(tx).Error(0)
+}
@@ -4,10 +4,15 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"time"
 
+	"github.com/gustavobelfort/42-jitsi/internal/config"
 	"github.com/gustavobelfort/42-jitsi/internal/db"
 	"github.com/gustavobelfort/42-jitsi/internal/intra"
 	"github.com/gustavobelfort/42-jitsi/internal/logging"
+	"github.com/gustavobelfort/42-jitsi/internal/notify"
+	"github.com/gustavobelfort/42-jitsi/internal/reminder"
+	"github.com/gustavobelfort/42-jitsi/internal/room"
 	"github.com/gustavobelfort/42-jitsi/internal/utils"
 	"github.com/jinzhu/gorm"
 	"github.com/sirupsen/logrus"
@@ -18,18 +23,26 @@ type scaleTeamHandler struct {
 
 	scaleTeamManager db.ScaleTeamManager
 	userManager      db.UserManager
+	reminderManager  db.ReminderManager
+	deliveryManager  db.DeliveryManager
+	tombstoneManager db.TombstoneManager
 
-	client intra.Client
+	client   intra.Client
+	notifier notify.Notifier
 }
 
-// NewScaleTeamHandler returns a new handler that will handle scale teams payloads with the given client and db managers.
-func NewScaleTeamHandler(client intra.Client, dbInstance *gorm.DB) ScaleTeamHandler {
+// NewScaleTeamHandler returns a new handler that will handle scale teams payloads with the given clients and db managers.
+func NewScaleTeamHandler(client intra.Client, notifier notify.Notifier, dbInstance *gorm.DB) ScaleTeamHandler {
 	return &scaleTeamHandler{
 		db: dbInstance,
 
 		scaleTeamManager: db.NewScaleTeamManager(dbInstance),
 		userManager:      db.NewUserManager(dbInstance),
+		reminderManager:  db.NewReminderManager(dbInstance),
+		deliveryManager:  db.NewDeliveryManager(dbInstance),
+		tombstoneManager: db.NewTombstoneManager(dbInstance),
 		client:           client,
+		notifier:         notifier,
 	}
 }
 
@@ -43,20 +56,34 @@ func (handler *scaleTeamHandler) interpretData(ctx context.Context, data []byte,
 	if err != nil {
 		return nil, err
 	}
+	return st, handler.getCorrecteds(ctx, st, logger)
+}
 
-	logger.Info("getting scale team's corrected members")
-	if st.Correcteds, err = handler.client.GetTeamMembers(ctx, st.TeamID); err != nil {
+// interpretScaleTeam converts the scale team of intra's API, then gets its corrected members.
+func (handler *scaleTeamHandler) interpretScaleTeam(ctx context.Context, intraST *intra.ScaleTeam, logger *logrus.Entry) (*scaleTeam, error) {
+	st, err := newScaleTeam(intraST)
+	if err != nil {
 		return nil, err
 	}
+	return st, handler.getCorrecteds(ctx, st, logger)
+}
 
-	return st, nil
+func (handler *scaleTeamHandler) getCorrecteds(ctx context.Context, st *scaleTeam, logger *logrus.Entry) error {
+	logger.Info("getting scale team's corrected members")
+	var err error
+	st.Correcteds, err = handler.client.GetTeamMembers(ctx, st.TeamID)
+	return err
 }
 
 func (handler *scaleTeamHandler) insertInDB(tx *gorm.DB, st *scaleTeam, logger *logrus.Entry) error {
-	defer tx.RollbackUnlessCommitted()
+	logger.Info("generating scale team's meeting")
+	meeting, err := room.NewMeeting(st.ID)
+	if err != nil {
+		return err
+	}
 
 	logger.Info("creating scale team's record")
-	stRecord, err := handler.scaleTeamManager.Create(tx, st.ID, st.BeginAt.Time, false)
+	stRecord, err := handler.scaleTeamManager.Create(tx, st.ID, st.BeginAt.Time, st.UpdatedAt.Time, meeting)
 	if err != nil {
 		return err
 	}
@@ -70,48 +97,156 @@ func (handler *scaleTeamHandler) insertInDB(tx *gorm.DB, st *scaleTeam, logger *
 			return err
 		}
 	}
+	logger.Info("planning scale team's reminders")
+	if err := reminder.Plan(tx, handler.reminderManager, st.ID, st.BeginAt.Time); err != nil {
+		return err
+	}
 	return tx.Commit().Error
 }
 
-func (handler *scaleTeamHandler) HandleCreate(ctx context.Context, data []byte) error {
-	logger := logging.ContextLog(ctx, logrus.StandardLogger())
+// upsertInDB creates or updates the scale team's records, unless the event is older than what is stored.
+//
+// Events newer than the stored scale team update it, while the events older than its destruction are ignored, thus
+// the events can be handled out of order. When two events create the same scale team concurrently, the latest one to
+// be handled updates it instead.
+func (handler *scaleTeamHandler) upsertInDB(tx *gorm.DB, st *scaleTeam, logger *logrus.Entry) error {
+	defer tx.RollbackUnlessCommitted()
 
-	st, err := handler.interpretData(ctx, data, logger)
+	logger.Info("getting corresponding scale team's record")
+	plan, err := handler.planUpsert(tx, st, db.ScaleTeamForUpdateOption())
 	if err != nil {
 		return err
 	}
 
-	return handler.insertInDB(handler.db.BeginTx(ctx, &sql.TxOptions{}), st, logger.WithField("scale_team_id", st.ID))
+	if plan.Action == CreateAction {
+		err := handler.insertInDB(tx, st, logger)
+		if _, exists := err.(*db.ScaleTeamExistsError); !exists {
+			return err
+		}
+		logger.Info("scale team was created by a concurrent event: updating it instead")
+		if plan, err = handler.planUpsert(tx, st, db.ScaleTeamForUpdateOption()); err != nil {
+			return err
+		}
+	}
+
+	switch plan.Action {
+	case CreateAction:
+		// The scale team created by the concurrent event is gone again: the event is to be retried.
+		return &db.ScaleTeamExistsError{ID: st.ID}
+	case StaleAction:
+		logger.Info("scale team's record is as recent as this event: only updating its members")
+		if err := handler.updateMembers(tx, st.ID, plan, logger); err != nil {
+			return err
+		}
+		return tx.Commit().Error
+	case DestroyedAction:
+		logger.Info("scale team was destroyed after this event: ignoring it")
+		return tx.Commit().Error
+	default:
+		return handler.updateInDB(tx, st, plan, logger)
+	}
 }
 
-func (handler *scaleTeamHandler) updateInDB(tx *gorm.DB, st *scaleTeam, logger *logrus.Entry) error {
-	defer tx.RollbackUnlessCommitted()
+func (handler *scaleTeamHandler) HandleCreate(ctx context.Context, data []byte) error {
+	logger := logging.ContextLog(ctx, logrus.StandardLogger())
 
-	logger.Info("getting corresponding scale team's record")
-	stRecords, err := handler.scaleTeamManager.Get(tx, db.ScaleTeamIDOption(st.ID))
+	st, err := handler.interpretData(ctx, data, logger)
 	if err != nil {
 		return err
 	}
+	return handler.handleUpsert(ctx, st, logger)
+}
 
-	if len(stRecords) == 0 {
-		logger.WithField("error", NotInDBError).Warnf("trying to update scale team: %v", NotInDBError)
-		return handler.insertInDB(tx, st, logger)
+// handleUpsert creates or updates the scale team's records within a transaction, unless the delivery was already
+// processed.
+func (handler *scaleTeamHandler) handleUpsert(ctx context.Context, st *scaleTeam, logger *logrus.Entry) error {
+	logger = logger.WithField("scale_team_id", st.ID)
+	tx := handler.db.BeginTx(ctx, &sql.TxOptions{})
+	if processed, err := handler.alreadyProcessed(ctx, tx, logger); err != nil || processed {
+		tx.Rollback()
+		return err
+	}
+	return handler.upsertInDB(tx, st, logger)
+}
+
+// updateInDB updates the scale team's record and members with the event. Events without version are always applied.
+func (handler *scaleTeamHandler) updateInDB(tx *gorm.DB, st *scaleTeam, plan *upsertPlan, logger *logrus.Entry) error {
+	stRecord := plan.record
+	if !st.UpdatedAt.IsZero() {
+		logger.Debugf("setting intra_updated_at to: %v", st.UpdatedAt)
+		stRecord.SetIntraUpdatedAt(st.UpdatedAt.Time)
+	}
+	if err := handler.updateMembers(tx, st.ID, plan, logger); err != nil {
+		return err
 	}
 
-	if st.BeginAt.Equal(stRecords[0].GetBeginAt()) {
+	previousBeginAt := plan.From
+	if plan.Action == UnchangedAction {
 		logger.Info("scale team's begin_at did not change")
-		return nil
+		if !st.UpdatedAt.IsZero() {
+			if err := stRecord.Save(tx); err != nil {
+				return err
+			}
+		}
+		return tx.Commit().Error
+	}
+
+	var participants []notify.Participant
+	notified, err := reminder.Notified(tx, handler.reminderManager, st.ID)
+	if err != nil {
+		return err
+	}
+	if notified {
+		logger.Info("getting scale team's participants to notify of the reschedule")
+		if participants, err = handler.getParticipants(tx, st.ID); err != nil {
+			return err
+		}
 	}
 
 	logger.Info("updating scale team's record")
 	logger.Debugf("setting begin_at to: %v", st.BeginAt)
-	stRecords[0].SetBeginAt(st.BeginAt.Time)
-	logger.Debugf("setting notified to: %v", false)
-	stRecords[0].SetNotified(false)
-	if err := stRecords[0].Save(tx); err != nil {
+	stRecord.SetBeginAt(st.BeginAt.Time)
+	if err := stRecord.Save(tx); err != nil {
 		return err
 	}
-	return tx.Commit().Error
+	logger.Info("resetting scale team's reminders, which makes it not notified")
+	if err := reminder.Reset(tx, handler.reminderManager, st.ID, st.BeginAt.Time); err != nil {
+		return err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	if participants != nil {
+		logger.Info("notifying scale team's participants of the reschedule")
+		if err := handler.notifier.Notify(notify.Message{
+			Kind:            notify.Reschedule,
+			ScaleTeamID:     st.ID,
+			BeginAt:         st.BeginAt.Time,
+			PreviousBeginAt: previousBeginAt,
+			Participants:    participants,
+		}); err != nil {
+			logging.LogError(logger, err, "sending reschedule notice to the scale team")
+		}
+	}
+	return nil
+}
+
+// updateMembers deletes the users who left the scale team and creates the members who joined it.
+func (handler *scaleTeamHandler) updateMembers(tx *gorm.DB, scaleTeamID int, plan *upsertPlan, logger *logrus.Entry) error {
+	for _, user := range plan.left {
+		logger.WithField("login", user.GetLogin()).Infof("deleting scale team's %s record", user.GetStatus())
+		if err := user.Delete(tx); err != nil {
+			return err
+		}
+	}
+	for _, member := range plan.Joined {
+		logger.WithField("login", member.Login).Infof("creating scale team's %s record", member.Status)
+		if _, err := handler.userManager.Create(tx, scaleTeamID, member.Login, member.Status); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (handler *scaleTeamHandler) HandleUpdate(ctx context.Context, data []byte) error {
@@ -121,21 +256,59 @@ func (handler *scaleTeamHandler) HandleUpdate(ctx context.Context, data []byte)
 	if err != nil {
 		return err
 	}
+	return handler.handleUpsert(ctx, st, logger)
+}
+
+func (handler *scaleTeamHandler) HandleScaleTeam(ctx context.Context, intraST *intra.ScaleTeam) error {
+	logger := logging.ContextLog(ctx, logrus.StandardLogger())
 
-	return handler.updateInDB(handler.db.BeginTx(ctx, &sql.TxOptions{}), st, logger)
+	st, err := handler.interpretScaleTeam(ctx, intraST, logger)
+	if err != nil {
+		return err
+	}
+	return handler.handleUpsert(ctx, st, logger)
+}
+
+// cancellation holds what is needed to notify the participants of a destroyed scale team once its records are deleted.
+type cancellation struct {
+	scaleTeamID  int
+	beginAt      time.Time
+	participants []notify.Participant
 }
 
-func (handler *scaleTeamHandler) deleteFromDB(tx *gorm.DB, id int, logger *logrus.Entry) error {
+func (handler *scaleTeamHandler) deleteFromDB(tx *gorm.DB, st *destroyedScaleTeam, logger *logrus.Entry) error {
 	defer tx.RollbackUnlessCommitted()
 
 	logger.Info("getting corresponding scale team's records")
-	stRecords, err := handler.scaleTeamManager.Get(tx, db.ScaleTeamIDOption(id))
+	stRecords, err := handler.scaleTeamManager.Get(tx, db.ScaleTeamIDOption(st.ID), db.ScaleTeamForUpdateOption())
 	if err != nil {
 		return err
 	}
 
 	if len(stRecords) == 0 {
-		return logging.WithLog(NotInDBError, logrus.WarnLevel, logrus.Fields{"scale_team_id": id})
+		// The scale team is still buried, so that it is not created by an event delivered after this one.
+		logger.WithField("error", NotInDBError).Warnf("destroying scale team: %v", NotInDBError)
+	}
+
+	var cancellations []cancellation
+	for _, record := range stRecords {
+		notified, err := reminder.Notified(tx, handler.reminderManager, record.GetID())
+		if err != nil {
+			return err
+		}
+		if !cancellationNotifiable(record, notified, time.Now()) {
+			continue
+		}
+		logger.Info("getting scale team's participants to notify of the cancellation")
+		participants, err := handler.getParticipants(tx, record.GetID())
+		if err != nil {
+			return err
+		}
+		cancellations = append(cancellations, cancellation{
+			scaleTeamID:  record.GetID(),
+			beginAt:      record.GetBeginAt(),
+			participants: participants,
+		})
 	}
 
 	logger.Info("deleting scale team's records")
@@ -145,26 +318,100 @@ func (handler *scaleTeamHandler) deleteFromDB(tx *gorm.DB, id int, logger *logru
 		}
 	}
 
-	return tx.Commit().Error
+	logger.Info("burying scale team")
+	if err := handler.bury(tx, st); err != nil {
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	for _, c := range cancellations {
+		logger.Info("notifying scale team's participants of the cancellation")
+		if err := handler.notifier.Notify(notify.Message{
+			Kind:         notify.Cancel,
+			ScaleTeamID:  c.scaleTeamID,
+			BeginAt:      c.beginAt,
+			Participants: c.participants,
+		}); err != nil {
+			logging.LogError(logger, err, "sending cancellation notice to the scale team")
+		}
+	}
+	return nil
+}
+
+// bury keeps a tombstone of the destroyed scale team, versioned with the destruction event. The current time is used as
+// the version of the events without any.
+func (handler *scaleTeamHandler) bury(tx *gorm.DB, st *destroyedScaleTeam) error {
+	now := time.Now()
+	version := st.UpdatedAt.Time
+	if version.IsZero() {
+		version = now
+	}
+
+	tombstones, err := handler.tombstoneManager.Get(tx, db.TombstoneScaleTeamOption(st.ID))
+	if err != nil {
+		return err
+	}
+	if len(tombstones) == 0 {
+		_, err := handler.tombstoneManager.Create(tx, st.ID, version, now)
+		return err
+	}
+
+	tombstone := tombstones[0]
+	if !version.After(tombstone.GetIntraUpdatedAt()) {
+		return nil
+	}
+	tombstone.SetIntraUpdatedAt(version)
+	return tombstone.Save(tx)
+}
+
+func (handler *scaleTeamHandler) getParticipants(tx *gorm.DB, scaleTeamID int) ([]notify.Participant, error) {
+	users, err := handler.userManager.Get(tx, db.UserScaleTeamOption(scaleTeamID))
+	if err != nil {
+		return nil, err
+	}
+
+	participants := make([]notify.Participant, len(users))
+	for i, user := range users {
+		participants[i] = notify.Participant{
+			Login: user.GetLogin(),
+			Role:  notify.Role(user.GetStatus()),
+		}
+	}
+	return participants, nil
+}
+
+// cancellationNotifiable reports whether the participants of a destroyed scale team are to be notified of it.
+//
+// They are if they already received the link or if the evaluation begins within the configured window. Evaluations
+// which began longer than the window ago are considered over.
+func cancellationNotifiable(scaleTeam db.ScaleTeam, notified bool, now time.Time) bool {
+	window := config.Conf.CancelWindow
+	beginAt := scaleTeam.GetBeginAt()
+	if beginAt.Before(now.Add(-window)) {
+		return false
+	}
+	return beginAt.Before(now.Add(window)) || notified
 }
 
 func (handler *scaleTeamHandler) HandleDestroy(ctx context.Context, data []byte) error {
 	logger := logging.ContextLog(ctx, logrus.StandardLogger())
-	st := make(map[string]interface{})
+	st := &destroyedScaleTeam{}
 	logger.Info("parsing webhook's payload")
 	err := utils.WrapContext(ctx, func() error {
-		if err := json.Unmarshal(data, &st); err != nil {
-			return err
-		}
-		logger.Debug("checking if id is present")
-		if _, ok := st["id"]; !ok {
-			return &MissingFieldsError{missing: []string{"id"}}
-		}
-		return nil
+		return json.Unmarshal(data, st)
 	})
 	if err != nil {
 		return err
 	}
 
-	return handler.deleteFromDB(handler.db.BeginTx(ctx, &sql.TxOptions{}), int(st["id"].(float64)), logger.WithField("scale_team_id", int(st["id"].(float64))))
+	logger = logger.WithField("scale_team_id", st.ID)
+	tx := handler.db.BeginTx(ctx, &sql.TxOptions{})
+	if processed, err := handler.alreadyProcessed(ctx, tx, logger); err != nil || processed {
+		tx.Rollback()
+		return err
+	}
+	return handler.deleteFromDB(tx, st, logger)
 }
@@ -5,11 +5,17 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gustavobelfort/42-jitsi/internal/config"
 	"github.com/gustavobelfort/42-jitsi/internal/db"
+	"github.com/gustavobelfort/42-jitsi/internal/intra"
+	"github.com/gustavobelfort/42-jitsi/internal/intra/intratest"
+	"github.com/gustavobelfort/42-jitsi/internal/notify"
+	"github.com/gustavobelfort/42-jitsi/internal/room"
 	"github.com/jinzhu/gorm"
 	"github.com/magiconair/properties/assert"
 	"github.com/stretchr/testify/mock"
@@ -19,10 +25,12 @@ import (
 
 func TestScaleTeamHandler(t *testing.T) {
 	t.Run("NewScaleTeamHander", func(t *testing.T) {
-		client := &ClientMock{}
+		client, err := intra.NewClient("id", "secret", nil)
+		require.NoError(t, err)
+		notifier := &NotifierMock{}
 		db := &gorm.DB{}
 
-		handler := NewScaleTeamHandler(client, db)
+		handler := NewScaleTeamHandler(client, notifier, db)
 		require.IsType(t, &scaleTeamHandler{}, handler)
 
 		stHandler := handler.(*scaleTeamHandler)
@@ -30,12 +38,24 @@ func TestScaleTeamHandler(t *testing.T) {
 		assert.Equal(t, db, stHandler.db)
 		assert.Equal(t, db, stHandler.scaleTeamManager.DB())
 		assert.Equal(t, db, stHandler.userManager.DB())
+		assert.Equal(t, db, stHandler.reminderManager.DB())
+		assert.Equal(t, db, stHandler.deliveryManager.DB())
+		assert.Equal(t, db, stHandler.tombstoneManager.DB())
 		assert.Equal(t, client, stHandler.client)
+		assert.Equal(t, notifier, stHandler.notifier)
 	})
 
 	suite.Run(t, new(ScaleTeamHandlerSuite))
 }
 
+var expectedMeeting = db.Meeting{
+	Provider: "jitsi",
+	RoomName: "49793d8cb8a852d55b533608895e8683",
+	URL:      "https://meet.jit.si/49793d8cb8a852d55b533608895e8683",
+}
+
+var expectedRemindAt = time.Date(2020, 7, 15, 20, 45, 0, 0, time.UTC)
+
 type ScaleTeamHandlerSuite struct {
 	suite.Suite
 
@@ -43,7 +63,13 @@ type ScaleTeamHandlerSuite struct {
 
 	stMock *ScaleTeamManagerMock
 	uMock  *UserManagerMock
-	cMock  *ClientMock
+	rMock  *ReminderManagerMock
+	dMock  *DeliveryManagerMock
+	tMock  *TombstoneManagerMock
+	nMock  *NotifierMock
+
+	intraServer *httptest.Server
+	intraAPI    *intratest.API
 
 	db     *gorm.DB
 	dbMock sqlmock.Sqlmock
@@ -60,20 +86,64 @@ func (s *ScaleTeamHandlerSuite) SetupSuite() {
 
 	s.db, err = gorm.Open("postgres", db)
 	s.Require().NoError(err)
+
+	config.Conf.Jitsi.URL = "https://meet.jit.si"
+	config.Conf.Jitsi.RoomTemplate = "{{.Hash}}"
+	config.Conf.Jitsi.RoomSecret = "secret"
+	config.Conf.Reminders = []time.Duration{time.Minute * 15}
+	config.Conf.CancelWindow = time.Hour
 }
 
 func (s *ScaleTeamHandlerSuite) SetupTest() {
 	s.stMock = &ScaleTeamManagerMock{}
 	s.uMock = &UserManagerMock{}
-	s.cMock = &ClientMock{}
+	s.rMock = &ReminderManagerMock{}
+	s.dMock = &DeliveryManagerMock{}
+	s.tMock = &TombstoneManagerMock{}
+	s.nMock = &NotifierMock{}
+
+	s.intraServer, s.intraAPI = intratest.NewServer(&intratest.Fixtures{Users: []intra.User{
+		{ID: 1, Login: "wlogin"},
+		{ID: 2, Login: "xlogin"},
+		{ID: 3, Login: "ylogin"},
+		{ID: 4, Login: "zlogin"},
+	}})
+	config.Conf.Intra.URL = s.intraServer.URL
+	client, err := intra.NewClient("id", "secret", s.intraServer.Client())
+	s.Require().NoError(err)
 
 	s.handler = &scaleTeamHandler{
 		db:               s.db,
 		scaleTeamManager: s.stMock,
 		userManager:      s.uMock,
+		reminderManager:  s.rMock,
+		deliveryManager:  s.dMock,
+		tombstoneManager: s.tMock,
+
+		client:   client,
+		notifier: s.nMock,
+	}
+}
+
+// setTeam makes intra serve the team with the given members.
+func (s *ScaleTeamHandlerSuite) setTeam(id int, logins ...string) {
+	s.intraAPI.SetTeam(intratest.Team{ID: id, Logins: logins})
+}
 
-		client: s.cMock,
+// expectStoredMembers expects the members of the scale team to be fetched and returns the given logins as stored.
+func (s *ScaleTeamHandlerSuite) expectStoredMembers(corrector string, correcteds ...string) {
+	users := []db.User{s.userMock(corrector, db.Corrector)}
+	for _, login := range correcteds {
+		users = append(users, s.userMock(login, db.Corrected))
 	}
+	s.uMock.On("Get", mock.Anything, mock.Anything).Return(users, nil).Once()
+}
+
+func (s *ScaleTeamHandlerSuite) userMock(login string, status db.UserStatus) *UserMock {
+	userMock := &UserMock{}
+	userMock.On("GetLogin").Return(login)
+	userMock.On("GetStatus").Return(string(status))
+	return userMock
 }
 
 func (s *ScaleTeamHandlerSuite) Test00_HandleCreate() {
@@ -91,17 +161,21 @@ func (s *ScaleTeamHandlerSuite) Test00_HandleCreate() {
 	expectedLogins := []string{"ylogin"}
 
 	expectedContext := context.Background()
-	s.cMock.On("GetTeamMembers", expectedContext, expectedTeam).Return(expectedLogins, nil).Once()
+	s.setTeam(expectedTeam, expectedLogins...)
 
 	s.dbMock.ExpectBegin()
 	s.dbMock.ExpectCommit()
 
+	s.stMock.On("Get", mock.Anything, mock.Anything).Return([]db.ScaleTeam{}, nil).Once()
+	s.tMock.On("Get", mock.Anything, mock.Anything).Return([]db.Tombstone{}, nil).Once()
+
 	recordMock := &ScaleTeamMock{}
 	defer recordMock.AssertExpectations(s.T())
-	s.stMock.On("Create", mock.Anything, expectedID, mock.Anything, false).Return(recordMock, nil).Once()
+	s.stMock.On("Create", mock.Anything, expectedID, mock.Anything, mock.Anything, expectedMeeting).Return(recordMock, nil).Once()
 
 	s.uMock.On("Create", mock.Anything, expectedID, expectedCorrector, db.Corrector).Return(&UserMock{}, nil).Once()
 	s.uMock.On("Create", mock.Anything, expectedID, expectedLogins[0], db.Corrected).Return(&UserMock{}, nil).Once()
+	s.rMock.On("Create", mock.Anything, expectedID, time.Minute*15, expectedRemindAt).Return(&ReminderMock{}, nil).Once()
 
 	recordMock.On("GetID").Return(expectedID).Twice()
 
@@ -124,14 +198,12 @@ func (s *ScaleTeamHandlerSuite) Test02_HandleCreate_ClientError() {
 		expectedTeam,
 	))
 
-	expectedError := errors.New("testing")
-
+	// The team is unknown to intra.
 	expectedContext := context.Background()
-	s.cMock.On("GetTeamMembers", expectedContext, expectedTeam).Return([]string{}, expectedError).Once()
 
 	err := s.handler.HandleCreate(expectedContext, payload)
 	s.Error(err)
-	s.Equal(expectedError, err)
+	s.True(intra.IsNotFound(err))
 }
 
 func (s *ScaleTeamHandlerSuite) Test03_HandleCreate_CreateScaleTeamError() {
@@ -149,14 +221,17 @@ func (s *ScaleTeamHandlerSuite) Test03_HandleCreate_CreateScaleTeamError() {
 	expectedLogins := []string{"ylogin"}
 
 	expectedContext := context.Background()
-	s.cMock.On("GetTeamMembers", expectedContext, expectedTeam).Return(expectedLogins, nil).Once()
+	s.setTeam(expectedTeam, expectedLogins...)
 
 	s.dbMock.ExpectBegin()
 	s.dbMock.ExpectRollback()
 
+	s.stMock.On("Get", mock.Anything, mock.Anything).Return([]db.ScaleTeam{}, nil).Once()
+	s.tMock.On("Get", mock.Anything, mock.Anything).Return([]db.Tombstone{}, nil).Once()
+
 	expectedError := errors.New("testing")
 
-	s.stMock.On("Create", mock.Anything, expectedID, mock.Anything, false).Return(&ScaleTeamMock{}, expectedError).Once()
+	s.stMock.On("Create", mock.Anything, expectedID, mock.Anything, mock.Anything, expectedMeeting).Return(&ScaleTeamMock{}, expectedError).Once()
 
 	err := s.handler.HandleCreate(expectedContext, payload)
 	s.Error(err)
@@ -178,14 +253,17 @@ func (s *ScaleTeamHandlerSuite) Test04_HandleCreate_CreateCorrectorError() {
 	expectedLogins := []string{"ylogin"}
 
 	expectedContext := context.Background()
-	s.cMock.On("GetTeamMembers", expectedContext, expectedTeam).Return(expectedLogins, nil).Once()
+	s.setTeam(expectedTeam, expectedLogins...)
 
 	s.dbMock.ExpectBegin()
 	s.dbMock.ExpectRollback()
 
+	s.stMock.On("Get", mock.Anything, mock.Anything).Return([]db.ScaleTeam{}, nil).Once()
+	s.tMock.On("Get", mock.Anything, mock.Anything).Return([]db.Tombstone{}, nil).Once()
+
 	recordMock := &ScaleTeamMock{}
 	defer recordMock.AssertExpectations(s.T())
-	s.stMock.On("Create", mock.Anything, expectedID, mock.Anything, false).Return(recordMock, nil).Once()
+	s.stMock.On("Create", mock.Anything, expectedID, mock.Anything, mock.Anything, expectedMeeting).Return(recordMock, nil).Once()
 
 	expectedError := errors.New("testing")
 
@@ -213,14 +291,17 @@ func (s *ScaleTeamHandlerSuite) Test05_HandleCreate_CreateCorrectedError() {
 	expectedLogins := []string{"ylogin"}
 
 	expectedContext := context.Background()
-	s.cMock.On("GetTeamMembers", expectedContext, expectedTeam).Return(expectedLogins, nil).Once()
+	s.setTeam(expectedTeam, expectedLogins...)
 
 	s.dbMock.ExpectBegin()
 	s.dbMock.ExpectRollback()
 
+	s.stMock.On("Get", mock.Anything, mock.Anything).Return([]db.ScaleTeam{}, nil).Once()
+	s.tMock.On("Get", mock.Anything, mock.Anything).Return([]db.Tombstone{}, nil).Once()
+
 	recordMock := &ScaleTeamMock{}
 	defer recordMock.AssertExpectations(s.T())
-	s.stMock.On("Create", mock.Anything, expectedID, mock.Anything, false).Return(recordMock, nil).Once()
+	s.stMock.On("Create", mock.Anything, expectedID, mock.Anything, mock.Anything, expectedMeeting).Return(recordMock, nil).Once()
 
 	expectedError := errors.New("testing")
 
@@ -243,7 +324,7 @@ func (s *ScaleTeamHandlerSuite) Test06_HandleUpdate() {
 	))
 
 	expectedContext := context.Background()
-	s.cMock.On("GetTeamMembers", expectedContext, expectedTeam).Return([]string{}, nil).Once()
+	s.setTeam(expectedTeam)
 
 	s.dbMock.ExpectBegin()
 	s.dbMock.ExpectCommit()
@@ -251,13 +332,20 @@ func (s *ScaleTeamHandlerSuite) Test06_HandleUpdate() {
 	recordMock := &ScaleTeamMock{}
 	defer recordMock.AssertExpectations(s.T())
 	s.stMock.On("Get", mock.Anything, mock.Anything).Return([]db.ScaleTeam{recordMock}, nil).Once()
+	s.expectStoredMembers("xlogin")
 
 	recordMock.On("GetBeginAt").Return(time.Now()).Once()
+	s.rMock.On("Get", mock.Anything, mock.Anything).Return([]db.Reminder{}, nil).Once()
 	recordMock.On("SetBeginAt", mock.Anything).Return().Once()
-	recordMock.On("SetNotified", false).Return().Once()
 
 	recordMock.On("Save", mock.Anything).Return(nil).Once()
 
+	sentMock := &ReminderMock{}
+	defer sentMock.AssertExpectations(s.T())
+	s.rMock.On("Get", mock.Anything, mock.Anything).Return([]db.Reminder{sentMock}, nil).Once()
+	sentMock.On("Delete", mock.Anything).Return(nil).Once()
+	s.rMock.On("Create", mock.Anything, 21, time.Minute*15, expectedRemindAt).Return(&ReminderMock{}, nil).Once()
+
 	err := s.handler.HandleUpdate(expectedContext, payload)
 	s.NoError(err)
 }
@@ -275,14 +363,15 @@ func (s *ScaleTeamHandlerSuite) Test07_HandleUpdate_NoUpdate() {
 	))
 
 	expectedContext := context.Background()
-	s.cMock.On("GetTeamMembers", expectedContext, expectedTeam).Return([]string{}, nil).Once()
+	s.setTeam(expectedTeam)
 
 	s.dbMock.ExpectBegin()
-	s.dbMock.ExpectRollback()
+	s.dbMock.ExpectCommit()
 
 	recordMock := &ScaleTeamMock{}
 	defer recordMock.AssertExpectations(s.T())
 	s.stMock.On("Get", mock.Anything, mock.Anything).Return([]db.ScaleTeam{recordMock}, nil).Once()
+	s.expectStoredMembers("xlogin")
 
 	recordMock.On("GetBeginAt").Return(expectedTime).Once()
 
@@ -305,19 +394,21 @@ func (s *ScaleTeamHandlerSuite) Test08_HandleUpdate_NotInDB() {
 	expectedLogins := []string{"ylogin"}
 
 	expectedContext := context.Background()
-	s.cMock.On("GetTeamMembers", expectedContext, expectedTeam).Return(expectedLogins, nil).Once()
+	s.setTeam(expectedTeam, expectedLogins...)
 
 	s.dbMock.ExpectBegin()
 	s.dbMock.ExpectCommit()
 
 	s.stMock.On("Get", mock.Anything, mock.Anything).Return([]db.ScaleTeam{}, nil)
+	s.tMock.On("Get", mock.Anything, mock.Anything).Return([]db.Tombstone{}, nil).Once()
 
 	recordMock := &ScaleTeamMock{}
 	defer recordMock.AssertExpectations(s.T())
-	s.stMock.On("Create", mock.Anything, expectedID, mock.Anything, false).Return(recordMock, nil).Once()
+	s.stMock.On("Create", mock.Anything, expectedID, mock.Anything, mock.Anything, expectedMeeting).Return(recordMock, nil).Once()
 
 	s.uMock.On("Create", mock.Anything, expectedID, expectedCorrector, db.Corrector).Return(&UserMock{}, nil).Once()
 	s.uMock.On("Create", mock.Anything, expectedID, expectedLogins[0], db.Corrected).Return(&UserMock{}, nil).Once()
+	s.rMock.On("Create", mock.Anything, expectedID, time.Minute*15, expectedRemindAt).Return(&ReminderMock{}, nil).Once()
 
 	recordMock.On("GetID").Return(expectedID).Twice()
 
@@ -341,7 +432,7 @@ func (s *ScaleTeamHandlerSuite) Test10_HandleUpdate_GetError() {
 	))
 
 	expectedContext := context.Background()
-	s.cMock.On("GetTeamMembers", expectedContext, expectedTeam).Return([]string{}, nil).Once()
+	s.setTeam(expectedTeam)
 
 	s.dbMock.ExpectBegin()
 	s.dbMock.ExpectRollback()
@@ -363,7 +454,7 @@ func (s *ScaleTeamHandlerSuite) Test11_HandleUpdate_SaveError() {
 	))
 
 	expectedContext := context.Background()
-	s.cMock.On("GetTeamMembers", expectedContext, expectedTeam).Return([]string{}, nil).Once()
+	s.setTeam(expectedTeam)
 
 	s.dbMock.ExpectBegin()
 	s.dbMock.ExpectRollback()
@@ -371,10 +462,11 @@ func (s *ScaleTeamHandlerSuite) Test11_HandleUpdate_SaveError() {
 	recordMock := &ScaleTeamMock{}
 	defer recordMock.AssertExpectations(s.T())
 	s.stMock.On("Get", mock.Anything, mock.Anything).Return([]db.ScaleTeam{recordMock}, nil).Once()
+	s.expectStoredMembers("xlogin")
 
 	recordMock.On("GetBeginAt").Return(time.Now()).Once()
+	s.rMock.On("Get", mock.Anything, mock.Anything).Return([]db.Reminder{}, nil).Once()
 	recordMock.On("SetBeginAt", mock.Anything).Return().Once()
-	recordMock.On("SetNotified", false).Return().Once()
 
 	expectedError := errors.New("testing")
 	recordMock.On("Save", mock.Anything).Return(expectedError).Once()
@@ -399,8 +491,14 @@ func (s *ScaleTeamHandlerSuite) Test12_HandleDestroy() {
 	defer recordMock.AssertExpectations(s.T())
 	s.stMock.On("Get", mock.Anything, mock.Anything).Return([]db.ScaleTeam{recordMock}, nil).Once()
 
+	recordMock.On("GetID").Return(expectedID).Once()
+	recordMock.On("GetBeginAt").Return(time.Now().Add(-time.Hour * 2)).Once()
+	s.rMock.On("Get", mock.Anything, mock.Anything).Return([]db.Reminder{}, nil).Once()
 	recordMock.On("Delete", mock.Anything).Return(nil).Once()
 
+	s.tMock.On("Get", mock.Anything, mock.Anything).Return([]db.Tombstone{}, nil).Once()
+	s.tMock.On("Create", mock.Anything, 21, mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).Return(&TombstoneMock{}, nil).Once()
+
 	err := s.handler.HandleDestroy(context.Background(), payload)
 	s.NoError(err)
 }
@@ -414,13 +512,15 @@ func (s *ScaleTeamHandlerSuite) Test13_HandleDestroy_NotInDB() {
 	))
 
 	s.dbMock.ExpectBegin()
-	s.dbMock.ExpectRollback()
+	s.dbMock.ExpectCommit()
 
 	s.stMock.On("Get", mock.Anything, mock.Anything).Return([]db.ScaleTeam{}, nil).Once()
 
+	s.tMock.On("Get", mock.Anything, mock.Anything).Return([]db.Tombstone{}, nil).Once()
+	s.tMock.On("Create", mock.Anything, 21, mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).Return(&TombstoneMock{}, nil).Once()
+
 	err := s.handler.HandleDestroy(context.Background(), payload)
-	s.Error(err)
-	s.True(errors.Is(err, NotInDBError))
+	s.NoError(err)
 }
 
 func (s *ScaleTeamHandlerSuite) Test14_HandleDestroy_PayloadError() {
@@ -481,6 +581,10 @@ func (s *ScaleTeamHandlerSuite) Test17_HandleDestroy_DeleteError() {
 	defer recordMock.AssertExpectations(s.T())
 	s.stMock.On("Get", mock.Anything, mock.Anything).Return([]db.ScaleTeam{recordMock}, nil).Once()
 
+	recordMock.On("GetID").Return(expectedID).Once()
+	recordMock.On("GetBeginAt").Return(time.Now().Add(time.Hour * 2)).Once()
+	s.rMock.On("Get", mock.Anything, mock.Anything).Return([]db.Reminder{}, nil).Once()
+
 	expectedError := errors.New("testing")
 	recordMock.On("Delete", mock.Anything).Return(expectedError).Once()
 
@@ -489,9 +593,705 @@ func (s *ScaleTeamHandlerSuite) Test17_HandleDestroy_DeleteError() {
 	s.Equal(expectedError, err)
 }
 
+func (s *ScaleTeamHandlerSuite) Test18_HandleCreate_MeetingError() {
+	expectedTeam := 42
+
+	payload := []byte(fmt.Sprintf(
+		`{"id": 21, "user": {"login": "xlogin"}, "team": {"id": %d}, "begin_at": "2020-07-15T21:00:00.000Z"}`,
+		expectedTeam,
+	))
+
+	expectedContext := context.Background()
+	s.setTeam(expectedTeam, "ylogin")
+
+	s.dbMock.ExpectBegin()
+	s.dbMock.ExpectRollback()
+
+	s.stMock.On("Get", mock.Anything, mock.Anything).Return([]db.ScaleTeam{}, nil).Once()
+	s.tMock.On("Get", mock.Anything, mock.Anything).Return([]db.Tombstone{}, nil).Once()
+
+	config.Conf.Jitsi.RoomSecret = ""
+	defer func() { config.Conf.Jitsi.RoomSecret = "secret" }()
+
+	err := s.handler.HandleCreate(expectedContext, payload)
+	s.Error(err)
+	s.Equal(room.MissingSecretError, err)
+}
+
+func (s *ScaleTeamHandlerSuite) Test19_HandleCreate_ReminderError() {
+	expectedID := 21
+	expectedTeam := 42
+
+	payload := []byte(fmt.Sprintf(
+		`{"id": %d, "user": {"login": "xlogin"}, "team": {"id": %d}, "begin_at": "2020-07-15T21:00:00.000Z"}`,
+		expectedID,
+		expectedTeam,
+	))
+
+	expectedContext := context.Background()
+	s.setTeam(expectedTeam)
+
+	s.dbMock.ExpectBegin()
+	s.dbMock.ExpectRollback()
+
+	s.stMock.On("Get", mock.Anything, mock.Anything).Return([]db.ScaleTeam{}, nil).Once()
+	s.tMock.On("Get", mock.Anything, mock.Anything).Return([]db.Tombstone{}, nil).Once()
+
+	recordMock := &ScaleTeamMock{}
+	defer recordMock.AssertExpectations(s.T())
+	s.stMock.On("Create", mock.Anything, expectedID, mock.Anything, mock.Anything, expectedMeeting).Return(recordMock, nil).Once()
+	s.uMock.On("Create", mock.Anything, expectedID, "xlogin", db.Corrector).Return(&UserMock{}, nil).Once()
+	recordMock.On("GetID").Return(expectedID).Once()
+
+	expectedError := errors.New("testing")
+	s.rMock.On("Create", mock.Anything, expectedID, time.Minute*15, expectedRemindAt).Return(&ReminderMock{}, expectedError).Once()
+
+	err := s.handler.HandleCreate(expectedContext, payload)
+	s.Error(err)
+	s.Equal(expectedError, err)
+}
+
+func (s *ScaleTeamHandlerSuite) Test20_HandleUpdate_ReminderError() {
+	expectedTeam := 42
+
+	payload := []byte(fmt.Sprintf(
+		`{"id": 21, "user": {"login": "xlogin"}, "team": {"id": %d}, "begin_at": "2020-07-15T21:00:00.000Z"}`,
+		expectedTeam,
+	))
+
+	expectedContext := context.Background()
+	s.setTeam(expectedTeam)
+
+	s.dbMock.ExpectBegin()
+	s.dbMock.ExpectRollback()
+
+	recordMock := &ScaleTeamMock{}
+	defer recordMock.AssertExpectations(s.T())
+	s.stMock.On("Get", mock.Anything, mock.Anything).Return([]db.ScaleTeam{recordMock}, nil).Once()
+	s.expectStoredMembers("xlogin")
+
+	recordMock.On("GetBeginAt").Return(time.Now()).Once()
+	s.rMock.On("Get", mock.Anything, mock.Anything).Return([]db.Reminder{}, nil).Once()
+	recordMock.On("SetBeginAt", mock.Anything).Return().Once()
+	recordMock.On("Save", mock.Anything).Return(nil).Once()
+
+	expectedError := errors.New("testing")
+	s.rMock.On("Get", mock.Anything, mock.Anything).Return([]db.Reminder{}, expectedError).Once()
+
+	err := s.handler.HandleUpdate(expectedContext, payload)
+	s.Error(err)
+	s.Equal(expectedError, err)
+}
+
+func (s *ScaleTeamHandlerSuite) Test21_HandleDestroy_Notified() {
+	expectedID := 21
+	expectedBeginAt := time.Now().Add(time.Hour * 2)
+
+	payload := []byte(fmt.Sprintf(
+		`{"id": %d}`,
+		expectedID,
+	))
+
+	s.dbMock.ExpectBegin()
+	s.dbMock.ExpectCommit()
+
+	recordMock := &ScaleTeamMock{}
+	defer recordMock.AssertExpectations(s.T())
+	s.stMock.On("Get", mock.Anything, mock.Anything).Return([]db.ScaleTeam{recordMock}, nil).Once()
+
+	recordMock.On("GetBeginAt").Return(expectedBeginAt)
+	s.rMock.On("Get", mock.Anything, mock.Anything).Return([]db.Reminder{&ReminderMock{}}, nil).Once()
+	recordMock.On("GetID").Return(expectedID)
+
+	corrector := &UserMock{}
+	corrector.On("GetLogin").Return("xlogin").Once()
+	corrector.On("GetStatus").Return(string(db.Corrector)).Once()
+	s.uMock.On("Get", mock.Anything, mock.Anything).Return([]db.User{corrector}, nil).Once()
+
+	recordMock.On("Delete", mock.Anything).Return(nil).Once()
+
+	s.tMock.On("Get", mock.Anything, mock.Anything).Return([]db.Tombstone{}, nil).Once()
+	s.tMock.On("Create", mock.Anything, 21, mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).Return(&TombstoneMock{}, nil).Once()
+
+	s.nMock.On("Notify", notify.Message{
+		Kind:         notify.Cancel,
+		ScaleTeamID:  expectedID,
+		BeginAt:      expectedBeginAt,
+		Participants: []notify.Participant{{Login: "xlogin", Role: notify.Corrector}},
+	}).Return(nil).Once()
+
+	err := s.handler.HandleDestroy(context.Background(), payload)
+	s.NoError(err)
+}
+
+func (s *ScaleTeamHandlerSuite) Test22_HandleDestroy_WithinWindow() {
+	expectedID := 21
+	expectedBeginAt := time.Now().Add(time.Minute * 30)
+
+	payload := []byte(fmt.Sprintf(
+		`{"id": %d}`,
+		expectedID,
+	))
+
+	s.dbMock.ExpectBegin()
+	s.dbMock.ExpectCommit()
+
+	recordMock := &ScaleTeamMock{}
+	defer recordMock.AssertExpectations(s.T())
+	s.stMock.On("Get", mock.Anything, mock.Anything).Return([]db.ScaleTeam{recordMock}, nil).Once()
+
+	recordMock.On("GetBeginAt").Return(expectedBeginAt)
+	recordMock.On("GetID").Return(expectedID)
+	s.rMock.On("Get", mock.Anything, mock.Anything).Return([]db.Reminder{}, nil).Once()
+
+	corrected := &UserMock{}
+	corrected.On("GetLogin").Return("ylogin").Once()
+	corrected.On("GetStatus").Return(string(db.Corrected)).Once()
+	s.uMock.On("Get", mock.Anything, mock.Anything).Return([]db.User{corrected}, nil).Once()
+
+	recordMock.On("Delete", mock.Anything).Return(nil).Once()
+
+	s.tMock.On("Get", mock.Anything, mock.Anything).Return([]db.Tombstone{}, nil).Once()
+	s.tMock.On("Create", mock.Anything, 21, mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).Return(&TombstoneMock{}, nil).Once()
+
+	// The records are deleted even though the participants could not be notified.
+	s.nMock.On("Notify", notify.Message{
+		Kind:         notify.Cancel,
+		ScaleTeamID:  expectedID,
+		BeginAt:      expectedBeginAt,
+		Participants: []notify.Participant{{Login: "ylogin", Role: notify.Corrected}},
+	}).Return(errors.New("testing")).Once()
+
+	err := s.handler.HandleDestroy(context.Background(), payload)
+	s.NoError(err)
+}
+
+func (s *ScaleTeamHandlerSuite) Test23_HandleDestroy_ParticipantsError() {
+	expectedID := 21
+
+	payload := []byte(fmt.Sprintf(
+		`{"id": %d}`,
+		expectedID,
+	))
+
+	s.dbMock.ExpectBegin()
+	s.dbMock.ExpectRollback()
+
+	recordMock := &ScaleTeamMock{}
+	defer recordMock.AssertExpectations(s.T())
+	s.stMock.On("Get", mock.Anything, mock.Anything).Return([]db.ScaleTeam{recordMock}, nil).Once()
+
+	recordMock.On("GetBeginAt").Return(time.Now().Add(time.Minute * 30)).Once()
+	recordMock.On("GetID").Return(expectedID).Twice()
+	s.rMock.On("Get", mock.Anything, mock.Anything).Return([]db.Reminder{}, nil).Once()
+
+	expectedError := errors.New("testing")
+	s.uMock.On("Get", mock.Anything, mock.Anything).Return([]db.User{}, expectedError).Once()
+
+	err := s.handler.HandleDestroy(context.Background(), payload)
+	s.Error(err)
+	s.Equal(expectedError, err)
+}
+
+func (s *ScaleTeamHandlerSuite) Test24_HandleUpdate_Notified() {
+	expectedID := 21
+	expectedTeam := 42
+	previousBeginAt := time.Date(2020, 7, 15, 20, 0, 0, 0, time.UTC)
+	expectedBeginAt := time.Date(2020, 7, 15, 21, 0, 0, 0, time.UTC)
+
+	payload := []byte(fmt.Sprintf(
+		`{"id": %d, "user": {"login": "xlogin"}, "team": {"id": %d}, "begin_at": "2020-07-15T21:00:00.000Z"}`,
+		expectedID,
+		expectedTeam,
+	))
+
+	expectedContext := context.Background()
+	s.setTeam(expectedTeam)
+
+	s.dbMock.ExpectBegin()
+	s.dbMock.ExpectCommit()
+
+	recordMock := &ScaleTeamMock{}
+	defer recordMock.AssertExpectations(s.T())
+	s.stMock.On("Get", mock.Anything, mock.Anything).Return([]db.ScaleTeam{recordMock}, nil).Once()
+	s.expectStoredMembers("xlogin")
+
+	recordMock.On("GetBeginAt").Return(previousBeginAt).Once()
+	s.rMock.On("Get", mock.Anything, mock.Anything).Return([]db.Reminder{&ReminderMock{}}, nil).Once()
+
+	corrector := &UserMock{}
+	corrector.On("GetLogin").Return("xlogin").Once()
+	corrector.On("GetStatus").Return(string(db.Corrector)).Once()
+	s.uMock.On("Get", mock.Anything, mock.Anything).Return([]db.User{corrector}, nil).Once()
+
+	recordMock.On("SetBeginAt", expectedBeginAt).Return().Once()
+	recordMock.On("Save", mock.Anything).Return(nil).Once()
+
+	s.rMock.On("Get", mock.Anything, mock.Anything).Return([]db.Reminder{}, nil).Once()
+	s.rMock.On("Create", mock.Anything, expectedID, time.Minute*15, expectedRemindAt).Return(&ReminderMock{}, nil).Once()
+
+	s.nMock.On("Notify", notify.Message{
+		Kind:            notify.Reschedule,
+		ScaleTeamID:     expectedID,
+		BeginAt:         expectedBeginAt,
+		PreviousBeginAt: previousBeginAt,
+		Participants:    []notify.Participant{{Login: "xlogin", Role: notify.Corrector}},
+	}).Return(nil).Once()
+
+	err := s.handler.HandleUpdate(expectedContext, payload)
+	s.NoError(err)
+}
+
+func (s *ScaleTeamHandlerSuite) Test25_HandleDestroy_NewDelivery() {
+	expectedID := 21
+	payload := []byte(fmt.Sprintf(`{"id": %d}`, expectedID))
+	expectedContext := ContextWithDelivery(context.Background(), "delivery")
+
+	s.dbMock.ExpectBegin()
+	s.dbMock.ExpectCommit()
+
+	s.dMock.On("Record", mock.Anything, "delivery", mock.AnythingOfType("time.Time")).Return(true, nil).Once()
+
+	recordMock := &ScaleTeamMock{}
+	defer recordMock.AssertExpectations(s.T())
+	s.stMock.On("Get", mock.Anything, mock.Anything).Return([]db.ScaleTeam{recordMock}, nil).Once()
+
+	recordMock.On("GetID").Return(expectedID).Once()
+	recordMock.On("GetBeginAt").Return(time.Now().Add(-time.Hour * 2)).Once()
+	s.rMock.On("Get", mock.Anything, mock.Anything).Return([]db.Reminder{}, nil).Once()
+	recordMock.On("Delete", mock.Anything).Return(nil).Once()
+
+	s.tMock.On("Get", mock.Anything, mock.Anything).Return([]db.Tombstone{}, nil).Once()
+	s.tMock.On("Create", mock.Anything, 21, mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).Return(&TombstoneMock{}, nil).Once()
+
+	err := s.handler.HandleDestroy(expectedContext, payload)
+	s.NoError(err)
+}
+
+func (s *ScaleTeamHandlerSuite) Test26_HandleCreate_AlreadyProcessed() {
+	expectedTeam := 42
+	payload := []byte(fmt.Sprintf(
+		`{"id": 21, "user": {"login": "xlogin"}, "team": {"id": %d}, "begin_at": "2020-07-15T21:00:00.000Z"}`,
+		expectedTeam,
+	))
+	expectedContext := ContextWithDelivery(context.Background(), "delivery")
+
+	s.setTeam(expectedTeam, "ylogin")
+
+	s.dbMock.ExpectBegin()
+	s.dbMock.ExpectRollback()
+
+	s.dMock.On("Record", mock.Anything, "delivery", mock.AnythingOfType("time.Time")).Return(false, nil).Once()
+
+	err := s.handler.HandleCreate(expectedContext, payload)
+	s.NoError(err)
+}
+
+func (s *ScaleTeamHandlerSuite) Test27_HandleUpdate_AlreadyProcessed() {
+	expectedTeam := 42
+	payload := []byte(fmt.Sprintf(
+		`{"id": 21, "user": {"login": "xlogin"}, "team": {"id": %d}, "begin_at": "2020-07-15T21:00:00.000Z"}`,
+		expectedTeam,
+	))
+	expectedContext := ContextWithDelivery(context.Background(), "delivery")
+
+	s.setTeam(expectedTeam, "ylogin")
+
+	s.dbMock.ExpectBegin()
+	s.dbMock.ExpectRollback()
+
+	s.dMock.On("Record", mock.Anything, "delivery", mock.AnythingOfType("time.Time")).Return(false, nil).Once()
+
+	err := s.handler.HandleUpdate(expectedContext, payload)
+	s.NoError(err)
+}
+
+func (s *ScaleTeamHandlerSuite) Test28_HandleDestroy_AlreadyProcessed() {
+	payload := []byte(`{"id": 21}`)
+	expectedContext := ContextWithDelivery(context.Background(), "delivery")
+
+	s.dbMock.ExpectBegin()
+	s.dbMock.ExpectRollback()
+
+	s.dMock.On("Record", mock.Anything, "delivery", mock.AnythingOfType("time.Time")).Return(false, nil).Once()
+
+	err := s.handler.HandleDestroy(expectedContext, payload)
+	s.NoError(err)
+}
+
+func (s *ScaleTeamHandlerSuite) Test29_HandleDestroy_RecordDeliveryError() {
+	payload := []byte(`{"id": 21}`)
+	expectedContext := ContextWithDelivery(context.Background(), "delivery")
+	expectedError := errors.New("testing")
+
+	s.dbMock.ExpectBegin()
+	s.dbMock.ExpectRollback()
+
+	s.dMock.On("Record", mock.Anything, "delivery", mock.AnythingOfType("time.Time")).Return(false, expectedError).Once()
+
+	err := s.handler.HandleDestroy(expectedContext, payload)
+	s.Equal(expectedError, err)
+}
+
+func (s *ScaleTeamHandlerSuite) Test30_HandleCreate_AlreadyInDB() {
+	expectedTeam := 42
+	expectedTime := time.Date(2020, 7, 15, 21, 0, 0, 0, time.UTC)
+	expectedVersion := time.Date(2020, 7, 14, 10, 30, 0, 0, time.UTC)
+
+	payload := []byte(fmt.Sprintf(
+		`{"id": 21, "user": {"login": "xlogin"}, "team": {"id": %d}, "begin_at": "2020-07-15T21:00:00.000Z", "updated_at": "2020-07-14T10:30:00.000Z"}`,
+		expectedTeam,
+	))
+
+	expectedContext := context.Background()
+	s.setTeam(expectedTeam)
+
+	s.dbMock.ExpectBegin()
+	s.dbMock.ExpectCommit()
+
+	recordMock := &ScaleTeamMock{}
+	defer recordMock.AssertExpectations(s.T())
+	s.stMock.On("Get", mock.Anything, mock.Anything).Return([]db.ScaleTeam{recordMock}, nil).Once()
+	s.expectStoredMembers("xlogin")
+
+	recordMock.On("GetIntraUpdatedAt").Return(expectedVersion.Add(-time.Hour)).Once()
+	recordMock.On("SetIntraUpdatedAt", expectedVersion).Return().Once()
+	recordMock.On("GetBeginAt").Return(expectedTime).Once()
+	recordMock.On("Save", mock.Anything).Return(nil).Once()
+
+	err := s.handler.HandleCreate(expectedContext, payload)
+	s.NoError(err)
+}
+
+func (s *ScaleTeamHandlerSuite) Test31_HandleUpdate_Stale() {
+	expectedTeam := 42
+	expectedVersion := time.Date(2020, 7, 14, 10, 30, 0, 0, time.UTC)
+
+	payload := []byte(fmt.Sprintf(
+		`{"id": 21, "user": {"login": "xlogin"}, "team": {"id": %d}, "begin_at": "2020-07-15T21:00:00.000Z", "updated_at": "2020-07-14T10:30:00.000Z"}`,
+		expectedTeam,
+	))
+
+	expectedContext := context.Background()
+	s.setTeam(expectedTeam)
+
+	s.dbMock.ExpectBegin()
+	s.dbMock.ExpectCommit()
+
+	recordMock := &ScaleTeamMock{}
+	defer recordMock.AssertExpectations(s.T())
+	s.stMock.On("Get", mock.Anything, mock.Anything).Return([]db.ScaleTeam{recordMock}, nil).Once()
+
+	recordMock.On("GetBeginAt").Return(time.Date(2020, 7, 15, 20, 0, 0, 0, time.UTC)).Once()
+	recordMock.On("GetIntraUpdatedAt").Return(expectedVersion).Once()
+	s.expectStoredMembers("xlogin")
+
+	err := s.handler.HandleUpdate(expectedContext, payload)
+	s.NoError(err)
+}
+
+func (s *ScaleTeamHandlerSuite) Test32_HandleUpdate_Destroyed() {
+	expectedTeam := 42
+	expectedVersion := time.Date(2020, 7, 14, 10, 30, 0, 0, time.UTC)
+
+	payload := []byte(fmt.Sprintf(
+		`{"id": 21, "user": {"login": "xlogin"}, "team": {"id": %d}, "begin_at": "2020-07-15T21:00:00.000Z", "updated_at": "2020-07-14T10:30:00.000Z"}`,
+		expectedTeam,
+	))
+
+	expectedContext := context.Background()
+	s.setTeam(expectedTeam)
+
+	s.dbMock.ExpectBegin()
+	s.dbMock.ExpectCommit()
+
+	s.stMock.On("Get", mock.Anything, mock.Anything).Return([]db.ScaleTeam{}, nil).Once()
+
+	tombstoneMock := &TombstoneMock{}
+	defer tombstoneMock.AssertExpectations(s.T())
+	s.tMock.On("Get", mock.Anything, mock.Anything).Return([]db.Tombstone{tombstoneMock}, nil).Once()
+	tombstoneMock.On("GetIntraUpdatedAt").Return(expectedVersion.Add(time.Minute)).Once()
+
+	err := s.handler.HandleUpdate(expectedContext, payload)
+	s.NoError(err)
+}
+
+func (s *ScaleTeamHandlerSuite) Test33_HandleUpdate_TombstoneError() {
+	expectedTeam := 42
+
+	payload := []byte(fmt.Sprintf(
+		`{"id": 21, "user": {"login": "xlogin"}, "team": {"id": %d}, "begin_at": "2020-07-15T21:00:00.000Z"}`,
+		expectedTeam,
+	))
+
+	expectedContext := context.Background()
+	s.setTeam(expectedTeam)
+
+	s.dbMock.ExpectBegin()
+	s.dbMock.ExpectRollback()
+
+	s.stMock.On("Get", mock.Anything, mock.Anything).Return([]db.ScaleTeam{}, nil).Once()
+
+	expectedError := errors.New("testing")
+	s.tMock.On("Get", mock.Anything, mock.Anything).Return([]db.Tombstone{}, expectedError).Once()
+
+	err := s.handler.HandleUpdate(expectedContext, payload)
+	s.Equal(expectedError, err)
+}
+
+func (s *ScaleTeamHandlerSuite) Test34_HandleDestroy_AlreadyBuried() {
+	expectedVersion := time.Date(2020, 7, 14, 10, 30, 0, 0, time.UTC)
+	payload := []byte(`{"id": 21, "updated_at": "2020-07-14T10:30:00.000Z"}`)
+
+	s.dbMock.ExpectBegin()
+	s.dbMock.ExpectCommit()
+
+	s.stMock.On("Get", mock.Anything, mock.Anything).Return([]db.ScaleTeam{}, nil).Once()
+
+	tombstoneMock := &TombstoneMock{}
+	defer tombstoneMock.AssertExpectations(s.T())
+	s.tMock.On("Get", mock.Anything, mock.Anything).Return([]db.Tombstone{tombstoneMock}, nil).Once()
+	tombstoneMock.On("GetIntraUpdatedAt").Return(expectedVersion.Add(-time.Minute)).Once()
+	tombstoneMock.On("SetIntraUpdatedAt", expectedVersion).Return().Once()
+	tombstoneMock.On("Save", mock.Anything).Return(nil).Once()
+
+	err := s.handler.HandleDestroy(context.Background(), payload)
+	s.NoError(err)
+}
+
+func (s *ScaleTeamHandlerSuite) Test35_HandleDestroy_BuryError() {
+	payload := []byte(`{"id": 21}`)
+
+	s.dbMock.ExpectBegin()
+	s.dbMock.ExpectRollback()
+
+	recordMock := &ScaleTeamMock{}
+	defer recordMock.AssertExpectations(s.T())
+	s.stMock.On("Get", mock.Anything, mock.Anything).Return([]db.ScaleTeam{recordMock}, nil).Once()
+
+	recordMock.On("GetID").Return(21).Once()
+	recordMock.On("GetBeginAt").Return(time.Now().Add(-time.Hour * 2)).Once()
+	s.rMock.On("Get", mock.Anything, mock.Anything).Return([]db.Reminder{}, nil).Once()
+	recordMock.On("Delete", mock.Anything).Return(nil).Once()
+
+	expectedError := errors.New("testing")
+	s.tMock.On("Get", mock.Anything, mock.Anything).Return([]db.Tombstone{}, nil).Once()
+	s.tMock.On("Create", mock.Anything, 21, mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).Return(&TombstoneMock{}, expectedError).Once()
+
+	err := s.handler.HandleDestroy(context.Background(), payload)
+	s.Equal(expectedError, err)
+}
+
+func (s *ScaleTeamHandlerSuite) Test36_Preview_Create() {
+	scaleTeam := intraScaleTeam("xlogin", time.Date(2020, 7, 14, 10, 30, 0, 0, time.UTC))
+
+	expectedContext := context.Background()
+	s.setTeam(42)
+
+	s.stMock.On("Get", mock.Anything, mock.Anything).Return([]db.ScaleTeam{}, nil).Once()
+	s.tMock.On("Get", mock.Anything, mock.Anything).Return([]db.Tombstone{}, nil).Once()
+
+	change, err := s.handler.Preview(expectedContext, scaleTeam)
+	s.Require().NoError(err)
+	s.Equal(Change{
+		ScaleTeamID: 21,
+		Action:      CreateAction,
+		To:          time.Date(2020, 7, 15, 21, 0, 0, 0, time.UTC),
+	}, change)
+}
+
+func (s *ScaleTeamHandlerSuite) Test37_Preview_Reschedule() {
+	expectedFrom := time.Date(2020, 7, 15, 20, 0, 0, 0, time.UTC)
+	scaleTeam := intraScaleTeam("xlogin", time.Date(2020, 7, 14, 10, 30, 0, 0, time.UTC))
+
+	expectedContext := context.Background()
+	s.setTeam(42)
+
+	recordMock := &ScaleTeamMock{}
+	defer recordMock.AssertExpectations(s.T())
+	s.stMock.On("Get", mock.Anything, mock.Anything).Return([]db.ScaleTeam{recordMock}, nil).Once()
+	s.expectStoredMembers("xlogin")
+	recordMock.On("GetBeginAt").Return(expectedFrom).Once()
+	recordMock.On("GetIntraUpdatedAt").Return(time.Date(2020, 7, 13, 0, 0, 0, 0, time.UTC)).Once()
+
+	change, err := s.handler.Preview(expectedContext, scaleTeam)
+	s.Require().NoError(err)
+	s.Equal(Change{
+		ScaleTeamID: 21,
+		Action:      RescheduleAction,
+		From:        expectedFrom,
+		To:          time.Date(2020, 7, 15, 21, 0, 0, 0, time.UTC),
+	}, change)
+}
+
+func (s *ScaleTeamHandlerSuite) Test38_Preview_Destroyed() {
+	scaleTeam := intraScaleTeam("xlogin", time.Date(2020, 7, 14, 10, 30, 0, 0, time.UTC))
+
+	expectedContext := context.Background()
+	s.setTeam(42)
+
+	s.stMock.On("Get", mock.Anything, mock.Anything).Return([]db.ScaleTeam{}, nil).Once()
+	tombstoneMock := &TombstoneMock{}
+	defer tombstoneMock.AssertExpectations(s.T())
+	s.tMock.On("Get", mock.Anything, mock.Anything).Return([]db.Tombstone{tombstoneMock}, nil).Once()
+	tombstoneMock.On("GetIntraUpdatedAt").Return(time.Date(2020, 7, 14, 11, 0, 0, 0, time.UTC)).Once()
+
+	change, err := s.handler.Preview(expectedContext, scaleTeam)
+	s.Require().NoError(err)
+	s.Equal(DestroyedAction, change.Action)
+}
+
+func (s *ScaleTeamHandlerSuite) Test39_HandleUpdate_MembersChanged() {
+	expectedTime := time.Date(2020, 7, 15, 21, 0, 0, 0, time.UTC)
+	payload := []byte(`{"id": 21, "user": {"login": "xlogin"}, "team": {"id": 42}, "begin_at": "2020-07-15T21:00:00.000Z"}`)
+
+	expectedContext := context.Background()
+	s.setTeam(42, "zlogin")
+
+	s.dbMock.ExpectBegin()
+	s.dbMock.ExpectCommit()
+
+	recordMock := &ScaleTeamMock{}
+	defer recordMock.AssertExpectations(s.T())
+	s.stMock.On("Get", mock.Anything, mock.Anything).Return([]db.ScaleTeam{recordMock}, nil).Once()
+	recordMock.On("GetBeginAt").Return(expectedTime).Once()
+
+	leftMock := s.userMock("ylogin", db.Corrected)
+	defer leftMock.AssertExpectations(s.T())
+	s.uMock.On("Get", mock.Anything, mock.Anything).Return([]db.User{s.userMock("xlogin", db.Corrector), leftMock}, nil).Once()
+	leftMock.On("Delete", mock.Anything).Return(nil).Once()
+	s.uMock.On("Create", mock.Anything, 21, "zlogin", db.Corrected).Return(&UserMock{}, nil).Once()
+
+	err := s.handler.HandleUpdate(expectedContext, payload)
+	s.NoError(err)
+}
+
+func (s *ScaleTeamHandlerSuite) Test40_Preview_MembersChanged() {
+	expectedTime := time.Date(2020, 7, 15, 21, 0, 0, 0, time.UTC)
+	scaleTeam := intraScaleTeam("wlogin", time.Time{})
+
+	expectedContext := context.Background()
+	s.setTeam(42, "xlogin")
+
+	recordMock := &ScaleTeamMock{}
+	defer recordMock.AssertExpectations(s.T())
+	s.stMock.On("Get", mock.Anything, mock.Anything).Return([]db.ScaleTeam{recordMock}, nil).Once()
+	recordMock.On("GetBeginAt").Return(expectedTime).Once()
+	s.expectStoredMembers("xlogin")
+
+	change, err := s.handler.Preview(expectedContext, scaleTeam)
+	s.Require().NoError(err)
+	s.Equal(UnchangedAction, change.Action)
+	s.True(change.MembersChanged())
+	s.Equal([]Member{{Login: "wlogin", Status: db.Corrector}, {Login: "xlogin", Status: db.Corrected}}, change.Joined)
+	s.Equal([]Member{{Login: "xlogin", Status: db.Corrector}}, change.Left)
+}
+
+func (s *ScaleTeamHandlerSuite) Test41_HandleUpdate_Stale_MembersChanged() {
+	expectedVersion := time.Date(2020, 7, 14, 10, 30, 0, 0, time.UTC)
+	payload := []byte(`{"id": 21, "user": {"login": "xlogin"}, "team": {"id": 42}, "begin_at": "2020-07-15T21:00:00.000Z", "updated_at": "2020-07-14T10:30:00.000Z"}`)
+
+	expectedContext := context.Background()
+	s.setTeam(42, "ylogin")
+
+	s.dbMock.ExpectBegin()
+	s.dbMock.ExpectCommit()
+
+	recordMock := &ScaleTeamMock{}
+	defer recordMock.AssertExpectations(s.T())
+	s.stMock.On("Get", mock.Anything, mock.Anything).Return([]db.ScaleTeam{recordMock}, nil).Once()
+	recordMock.On("GetBeginAt").Return(time.Date(2020, 7, 15, 20, 0, 0, 0, time.UTC)).Once()
+	recordMock.On("GetIntraUpdatedAt").Return(expectedVersion).Once()
+
+	s.expectStoredMembers("xlogin")
+	s.uMock.On("Create", mock.Anything, 21, "ylogin", db.Corrected).Return(&UserMock{}, nil).Once()
+
+	err := s.handler.HandleUpdate(expectedContext, payload)
+	s.NoError(err)
+}
+
+func (s *ScaleTeamHandlerSuite) Test42_HandleCreate_CreatedConcurrently() {
+	expectedVersion := time.Date(2020, 7, 14, 10, 30, 0, 0, time.UTC)
+	payload := []byte(`{"id": 21, "user": {"login": "xlogin"}, "team": {"id": 42}, "begin_at": "2020-07-15T21:00:00.000Z", "updated_at": "2020-07-14T10:30:00.000Z"}`)
+
+	expectedContext := context.Background()
+	s.setTeam(42, "ylogin")
+
+	s.dbMock.ExpectBegin()
+	s.dbMock.ExpectCommit()
+
+	s.stMock.On("Get", mock.Anything, mock.Anything).Return([]db.ScaleTeam{}, nil).Once()
+	s.tMock.On("Get", mock.Anything, mock.Anything).Return([]db.Tombstone{}, nil).Once()
+	s.stMock.On("Create", mock.Anything, 21, mock.Anything, expectedVersion, expectedMeeting).Return(&ScaleTeamMock{}, &db.ScaleTeamExistsError{ID: 21}).Once()
+
+	// The same event was handled concurrently: it is handled again as an update.
+	recordMock := &ScaleTeamMock{}
+	defer recordMock.AssertExpectations(s.T())
+	s.stMock.On("Get", mock.Anything, mock.Anything).Return([]db.ScaleTeam{recordMock}, nil).Once()
+	recordMock.On("GetBeginAt").Return(time.Date(2020, 7, 15, 21, 0, 0, 0, time.UTC)).Once()
+	recordMock.On("GetIntraUpdatedAt").Return(expectedVersion).Once()
+	s.expectStoredMembers("xlogin", "ylogin")
+
+	err := s.handler.HandleCreate(expectedContext, payload)
+	s.NoError(err)
+}
+
+func (s *ScaleTeamHandlerSuite) Test43_HandleScaleTeam_MembersChanged() {
+	expectedTime := time.Date(2020, 7, 15, 21, 0, 0, 0, time.UTC)
+	expectedVersion := time.Date(2020, 7, 14, 10, 30, 0, 0, time.UTC)
+	scaleTeam := intraScaleTeam("xlogin", expectedVersion)
+
+	expectedContext := context.Background()
+	s.setTeam(42, "zlogin")
+
+	s.dbMock.ExpectBegin()
+	s.dbMock.ExpectCommit()
+
+	recordMock := &ScaleTeamMock{}
+	defer recordMock.AssertExpectations(s.T())
+	s.stMock.On("Get", mock.Anything, mock.Anything).Return([]db.ScaleTeam{recordMock}, nil).Once()
+	recordMock.On("GetBeginAt").Return(expectedTime).Once()
+	recordMock.On("GetIntraUpdatedAt").Return(time.Date(2020, 7, 13, 0, 0, 0, 0, time.UTC)).Once()
+	recordMock.On("SetIntraUpdatedAt", expectedVersion).Once()
+	recordMock.On("Save", mock.Anything).Return(nil).Once()
+
+	leftMock := s.userMock("ylogin", db.Corrected)
+	defer leftMock.AssertExpectations(s.T())
+	s.uMock.On("Get", mock.Anything, mock.Anything).Return([]db.User{s.userMock("xlogin", db.Corrector), leftMock}, nil).Once()
+	leftMock.On("Delete", mock.Anything).Return(nil).Once()
+	s.uMock.On("Create", mock.Anything, 21, "zlogin", db.Corrected).Return(&UserMock{}, nil).Once()
+
+	err := s.handler.HandleScaleTeam(expectedContext, scaleTeam)
+	s.NoError(err)
+}
+
+func (s *ScaleTeamHandlerSuite) Test44_HandleScaleTeam_InvisibleCorrector() {
+	err := s.handler.HandleScaleTeam(context.Background(), intraScaleTeam("", time.Date(2020, 7, 14, 10, 30, 0, 0, time.UTC)))
+	s.Equal(NoCorrectorError, err)
+}
+
+func (s *ScaleTeamHandlerSuite) TearDownSuite() {
+	config.Conf.Intra.URL = ""
+}
+
 func (s *ScaleTeamHandlerSuite) TearDownTest() {
 	s.stMock.AssertExpectations(s.T())
 	s.uMock.AssertExpectations(s.T())
-	s.cMock.AssertExpectations(s.T())
+	s.rMock.AssertExpectations(s.T())
+	s.dMock.AssertExpectations(s.T())
+	s.tMock.AssertExpectations(s.T())
+	s.nMock.AssertExpectations(s.T())
 	s.NoError(s.dbMock.ExpectationsWereMet())
+	s.intraServer.Close()
+}
+
+// intraScaleTeam returns the scale team 21 of the team 42 as intra's API lists it.
+func intraScaleTeam(corrector string, updatedAt time.Time) *intra.ScaleTeam {
+	return &intra.ScaleTeam{
+		ID:        21,
+		BeginAt:   time.Date(2020, 7, 15, 21, 0, 0, 0, time.UTC),
+		UpdatedAt: updatedAt,
+		Corrector: intra.Corrector{Login: corrector},
+		Team:      intra.Team{ID: 42},
+	}
 }
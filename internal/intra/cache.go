@@ -0,0 +1,91 @@
+package intra
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gustavobelfort/42-jitsi/internal/db"
+	"github.com/gustavobelfort/42-jitsi/internal/logging"
+	"github.com/jinzhu/gorm"
+	"github.com/sirupsen/logrus"
+)
+
+// cachedClient is a Client looking the users' profiles up in the database before requesting them to the API.
+type cachedClient struct {
+	Client
+
+	db       *gorm.DB
+	profiles db.ProfileManager
+	ttl      time.Duration
+}
+
+// NewCachedClient returns a Client caching the users' profiles fetched by `client` for `ttl` in the database, so
+// that every process shares them.
+//
+// The cache never fails a lookup: if the database can not be read or written, the API is requested.
+func NewCachedClient(client Client, dbInstance *gorm.DB, ttl time.Duration) Client {
+	return &cachedClient{
+		Client: client,
+
+		db:       dbInstance,
+		profiles: db.NewProfileManager(dbInstance),
+		ttl:      ttl,
+	}
+}
+
+// GetUserEmail returns the cached email of a user, or requests it with the API and caches it if it expired.
+func (c *cachedClient) GetUserEmail(ctx context.Context, login string) (string, error) {
+	logger := logging.ContextLog(ctx, logrus.StandardLogger()).WithField("login", login)
+
+	now := time.Now()
+	profiles, err := c.profiles.Get(c.db, db.ProfileLoginOption(login), db.ProfileFetchedAfterOption(now.Add(-c.ttl)))
+	if err != nil {
+		logger.WithError(err).Warnf("could not read the cached profile: %v", err)
+	} else if len(profiles) > 0 {
+		logger.Debug("user email found in the cache")
+		return profiles[0].GetEmail(), nil
+	}
+
+	email, err := c.Client.GetUserEmail(ctx, login)
+	if err != nil {
+		return "", err
+	}
+	if err := c.profiles.Store(c.db, login, email, now); err != nil {
+		logger.WithError(err).Warnf("could not cache the profile: %v", err)
+	}
+	return email, nil
+}
+
+// GetUserEmails returns the cached emails of the users, and requests the expired ones with the API to cache them.
+func (c *cachedClient) GetUserEmails(ctx context.Context, logins []string) (map[string]string, error) {
+	logger := logging.ContextLog(ctx, logrus.StandardLogger())
+
+	now := time.Now()
+	emails := make(map[string]string, len(logins))
+	profiles, err := c.profiles.Get(c.db, db.ProfileLoginsOption(logins), db.ProfileFetchedAfterOption(now.Add(-c.ttl)))
+	if err != nil {
+		logger.WithError(err).Warnf("could not read the cached profiles: %v", err)
+	}
+	for _, profile := range profiles {
+		emails[profile.GetLogin()] = profile.GetEmail()
+	}
+	expired := missingLogins(uniqueLogins(logins), emails)
+	if len(expired) == 0 {
+		logger.Debug("users emails found in the cache")
+		return emails, nil
+	}
+
+	fetched, err := c.Client.GetUserEmails(ctx, expired)
+	var notFoundErr *UsersNotFoundError
+	if err != nil && !errors.As(err, &notFoundErr) {
+		return nil, err
+	}
+	for login, email := range fetched {
+		emails[login] = email
+		if err := c.profiles.Store(c.db, login, email, now); err != nil {
+			logger.WithField("login", login).WithError(err).Warnf("could not cache the profile: %v", err)
+		}
+	}
+	return emails, err
+}
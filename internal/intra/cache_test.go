@@ -0,0 +1,131 @@
+package intra
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gustavobelfort/42-jitsi/internal/db"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestCachedClient(t *testing.T) {
+	suite.Run(t, new(CachedClientSuite))
+}
+
+type CachedClientSuite struct {
+	suite.Suite
+
+	client *cachedClient
+	cMock  *ClientMock
+	pMock  *ProfileManagerMock
+	db     *gorm.DB
+}
+
+func (s *CachedClientSuite) SetupTest() {
+	s.cMock = &ClientMock{}
+	s.pMock = &ProfileManagerMock{}
+	s.db = &gorm.DB{}
+
+	s.client = &cachedClient{
+		Client:   s.cMock,
+		db:       s.db,
+		profiles: s.pMock,
+		ttl:      time.Hour,
+	}
+}
+
+func (s *CachedClientSuite) Test00_NewCachedClient() {
+	client := NewCachedClient(s.cMock, s.db, time.Hour)
+	s.Require().IsType(&cachedClient{}, client)
+
+	cached := client.(*cachedClient)
+	s.Equal(s.cMock, cached.Client)
+	s.Equal(s.db, cached.profiles.DB())
+	s.Equal(time.Hour, cached.ttl)
+}
+
+func (s *CachedClientSuite) Test01_GetUserEmail_Cached() {
+	profile := &ProfileMock{}
+	profile.On("GetEmail").Return("xlogin@student.42campus.org").Once()
+	s.pMock.On("Get", s.db, mock.Anything).Return([]db.Profile{profile}, nil).Once()
+
+	email, err := s.client.GetUserEmail(context.Background(), "xlogin")
+	s.Require().NoError(err)
+	s.Equal("xlogin@student.42campus.org", email)
+}
+
+func (s *CachedClientSuite) Test02_GetUserEmail_Expired() {
+	s.pMock.On("Get", s.db, mock.Anything).Return([]db.Profile{}, nil).Once()
+	s.cMock.On("GetUserEmail", mock.Anything, "xlogin").Return("xlogin@student.42campus.org", nil).Once()
+	s.pMock.On("Store", s.db, "xlogin", "xlogin@student.42campus.org", mock.AnythingOfType("time.Time")).Return(nil).Once()
+
+	email, err := s.client.GetUserEmail(context.Background(), "xlogin")
+	s.Require().NoError(err)
+	s.Equal("xlogin@student.42campus.org", email)
+}
+
+func (s *CachedClientSuite) Test03_GetUserEmail_DatabaseError() {
+	s.pMock.On("Get", s.db, mock.Anything).Return([]db.Profile{}, errors.New("testing")).Once()
+	s.cMock.On("GetUserEmail", mock.Anything, "xlogin").Return("xlogin@student.42campus.org", nil).Once()
+	s.pMock.On("Store", s.db, "xlogin", "xlogin@student.42campus.org", mock.AnythingOfType("time.Time")).
+		Return(errors.New("testing")).Once()
+
+	email, err := s.client.GetUserEmail(context.Background(), "xlogin")
+	s.Require().NoError(err, "the cache must not fail the lookup")
+	s.Equal("xlogin@student.42campus.org", email)
+}
+
+func (s *CachedClientSuite) Test04_GetUserEmail_APIError() {
+	s.pMock.On("Get", s.db, mock.Anything).Return([]db.Profile{}, nil).Once()
+	s.cMock.On("GetUserEmail", mock.Anything, "xlogin").Return("", errors.New("testing")).Once()
+
+	_, err := s.client.GetUserEmail(context.Background(), "xlogin")
+	s.Error(err)
+}
+
+func (s *CachedClientSuite) Test05_GetUserEmails_PartlyCached() {
+	profile := &ProfileMock{}
+	profile.On("GetLogin").Return("xlogin").Once()
+	profile.On("GetEmail").Return("xlogin@student.42campus.org").Once()
+	s.pMock.On("Get", s.db, mock.Anything).Return([]db.Profile{profile}, nil).Once()
+	s.cMock.On("GetUserEmails", mock.Anything, []string{"ylogin"}).
+		Return(map[string]string{"ylogin": "ylogin@student.42campus.org"}, nil).Once()
+	s.pMock.On("Store", s.db, "ylogin", "ylogin@student.42campus.org", mock.AnythingOfType("time.Time")).Return(nil).Once()
+
+	emails, err := s.client.GetUserEmails(context.Background(), []string{"xlogin", "ylogin"})
+	s.Require().NoError(err)
+	s.Equal(map[string]string{
+		"xlogin": "xlogin@student.42campus.org",
+		"ylogin": "ylogin@student.42campus.org",
+	}, emails)
+}
+
+func (s *CachedClientSuite) Test06_GetUserEmails_NotFound() {
+	notFoundErr := &UsersNotFoundError{Logins: []string{"ylogin"}}
+	s.pMock.On("Get", s.db, mock.Anything).Return([]db.Profile{}, nil).Once()
+	s.cMock.On("GetUserEmails", mock.Anything, []string{"xlogin", "ylogin"}).
+		Return(map[string]string{"xlogin": "xlogin@student.42campus.org"}, notFoundErr).Once()
+	s.pMock.On("Store", s.db, "xlogin", "xlogin@student.42campus.org", mock.AnythingOfType("time.Time")).Return(nil).Once()
+
+	emails, err := s.client.GetUserEmails(context.Background(), []string{"xlogin", "ylogin"})
+	s.Equal(notFoundErr, err)
+	s.Equal(map[string]string{"xlogin": "xlogin@student.42campus.org"}, emails)
+}
+
+func (s *CachedClientSuite) Test07_GetUserEmails_APIError() {
+	s.pMock.On("Get", s.db, mock.Anything).Return([]db.Profile{}, nil).Once()
+	s.cMock.On("GetUserEmails", mock.Anything, []string{"xlogin"}).Return(nil, errors.New("testing")).Once()
+
+	emails, err := s.client.GetUserEmails(context.Background(), []string{"xlogin"})
+	s.Error(err)
+	s.Nil(emails)
+}
+
+func (s *CachedClientSuite) TearDownTest() {
+	s.cMock.AssertExpectations(s.T())
+	s.pMock.AssertExpectations(s.T())
+}
@@ -0,0 +1,309 @@
+package intra_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gustavobelfort/42-jitsi/internal/config"
+	"github.com/gustavobelfort/42-jitsi/internal/intra"
+	"github.com/gustavobelfort/42-jitsi/internal/intra/intratest"
+	"github.com/gustavobelfort/42-jitsi/internal/resilient"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestClient(t *testing.T) {
+	suite.Run(t, new(ClientSuite))
+}
+
+// ClientSuite tests the client against the fake API of intratest.
+type ClientSuite struct {
+	suite.Suite
+
+	server *httptest.Server
+	api    *intratest.API
+	client intra.Client
+}
+
+// scaleTeamPayload returns the payload of a scale team of the team 42 as the API returns it.
+func scaleTeamPayload(id int, beginAt string) json.RawMessage {
+	payload, _ := json.Marshal(map[string]interface{}{
+		"id":         id,
+		"scale_id":   4,
+		"comment":    nil,
+		"created_at": "2020-07-14T10:00:00.000Z",
+		"updated_at": "2020-07-14T10:00:00.000Z",
+		"feedback":   nil,
+		"final_mark": nil,
+		"flag":       map[string]interface{}{"id": 1, "name": "Ok", "positive": true},
+		"begin_at":   beginAt,
+		"correcteds": []map[string]interface{}{{"id": 2, "login": "ylogin"}},
+		"corrector":  map[string]interface{}{"id": 1, "login": "xlogin"},
+		"truant":     map[string]interface{}{},
+		"filled_at":  nil,
+		"team":       map[string]interface{}{"id": 42, "name": "ylogin's group", "project_id": 1},
+		"feedbacks":  []interface{}{},
+	})
+	return payload
+}
+
+// bodyTrackingTransport counts the response bodies it returns which are left open.
+type bodyTrackingTransport struct {
+	transport http.RoundTripper
+	open      int32
+}
+
+func (t *bodyTrackingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	atomic.AddInt32(&t.open, 1)
+	resp.Body = &trackedBody{ReadCloser: resp.Body, open: &t.open}
+	return resp, nil
+}
+
+type trackedBody struct {
+	io.ReadCloser
+	open   *int32
+	closed sync.Once
+}
+
+func (b *trackedBody) Close() error {
+	b.closed.Do(func() { atomic.AddInt32(b.open, -1) })
+	return b.ReadCloser.Close()
+}
+
+// trackingClient returns a client of the API whose response bodies are tracked by the transport.
+func (s *ClientSuite) trackingClient() (intra.Client, *bodyTrackingTransport) {
+	transport := &bodyTrackingTransport{transport: s.server.Client().Transport}
+	client, err := intra.NewClient("id", "secret", &http.Client{Transport: transport})
+	s.Require().NoError(err)
+	return client, transport
+}
+
+func (s *ClientSuite) SetupTest() {
+	s.server, s.api = intratest.NewServer(&intratest.Fixtures{
+		Users: []intra.User{
+			{ID: 1, Login: "xlogin", Email: "xlogin@student.42campus.org"},
+			{ID: 2, Login: "ylogin", Email: "ylogin@student.42campus.org"},
+			{ID: 3, Login: "zlogin", Email: "zlogin@student.42campus.org"},
+			// The email of the users is null when the application is not allowed to see it.
+			{ID: 4, Login: "wlogin"},
+		},
+		Teams: []intratest.Team{{ID: 42, Logins: []string{"ylogin", "zlogin", "wlogin"}}},
+		ScaleTeams: []intratest.ScaleTeam{
+			{CampusID: 1, Payload: scaleTeamPayload(21, "2020-07-15T21:00:00.000Z")},
+			{CampusID: 7, Payload: scaleTeamPayload(22, "2020-07-15T22:00:00.000Z")},
+			{CampusID: 1, Payload: scaleTeamPayload(23, "2020-07-17T21:00:00.000Z")},
+		},
+	}, intratest.MaxPageSizeOption(2))
+
+	config.Conf.Intra.URL = s.server.URL
+	config.Conf.Intra.MaxRetries = 1
+	client, err := intra.NewClient("id", "secret", s.server.Client())
+	s.Require().NoError(err)
+	s.client = client
+}
+
+func (s *ClientSuite) Test00_GetUserEmail() {
+	email, err := s.client.GetUserEmail(context.Background(), "xlogin")
+	s.Require().NoError(err)
+	s.Equal("xlogin@student.42campus.org", email)
+}
+
+func (s *ClientSuite) Test01_GetUserEmail_NotFound() {
+	email, err := s.client.GetUserEmail(context.Background(), "unknown")
+	s.True(intra.IsNotFound(err))
+	s.Zero(email)
+}
+
+func (s *ClientSuite) Test02_GetUserEmail_NullEmail() {
+	_, err := s.client.GetUserEmail(context.Background(), "wlogin")
+	s.Equal(&intra.InvalidModelError{Model: "user", ID: "wlogin", Field: "email"}, err)
+}
+
+func (s *ClientSuite) Test03_GetUserEmail_RateLimited() {
+	s.api.Throttle(1)
+
+	email, err := s.client.GetUserEmail(context.Background(), "xlogin")
+	s.Require().NoError(err)
+	s.Equal("xlogin@student.42campus.org", email)
+	s.Equal(2, s.api.Requests("/v2/users/:login"))
+}
+
+func (s *ClientSuite) Test03_GetUserEmail_BodiesClosed() {
+	client, transport := s.trackingClient()
+
+	_, err := client.GetUserEmail(context.Background(), "unknown")
+	s.True(intra.IsNotFound(err))
+	s.Zero(atomic.LoadInt32(&transport.open), "the body of an error response must be closed")
+
+	s.api.Throttle(2)
+	_, err = client.GetUserEmail(context.Background(), "xlogin")
+	var httpErr *intra.HTTPError
+	s.Require().True(errors.As(err, &httpErr))
+	s.Equal(http.StatusTooManyRequests, httpErr.Response.StatusCode)
+	s.Zero(atomic.LoadInt32(&transport.open), "the body of the last rate limited response must be closed")
+}
+
+func (s *ClientSuite) Test04_GetUserEmail_ContextCanceled() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	email, err := s.client.GetUserEmail(ctx, "xlogin")
+	s.True(errors.Is(err, context.Canceled))
+	s.Zero(email)
+}
+
+func (s *ClientSuite) Test05_GetTeamMembers() {
+	logins, err := s.client.GetTeamMembers(context.Background(), 42)
+	s.Require().NoError(err)
+	s.Equal([]string{"ylogin", "zlogin", "wlogin"}, logins)
+	s.Equal(2, s.api.Requests("/v2/teams/:id/users"), "every page of the members must be requested")
+}
+
+func (s *ClientSuite) Test05_GetTeamMembers_BasePath() {
+	server := httptest.NewServer(http.StripPrefix("/intra", s.api))
+	defer server.Close()
+	config.Conf.Intra.URL = server.URL + "/intra"
+	client, err := intra.NewClient("id", "secret", server.Client())
+	s.Require().NoError(err)
+
+	logins, err := client.GetTeamMembers(context.Background(), 42)
+	s.Require().NoError(err)
+	s.Equal([]string{"ylogin", "zlogin", "wlogin"}, logins)
+	s.Equal(2, s.api.Requests("/v2/teams/:id/users"), "the links must be followed under the base path")
+}
+
+func (s *ClientSuite) Test06_GetTeamMembers_NotFound() {
+	logins, err := s.client.GetTeamMembers(context.Background(), 4242)
+	s.True(intra.IsNotFound(err))
+	s.Nil(logins)
+}
+
+func (s *ClientSuite) Test07_GetUserEmails() {
+	emails, err := s.client.GetUserEmails(context.Background(), []string{"xlogin", "ylogin", "zlogin", "xlogin"})
+	s.Require().NoError(err)
+	s.Equal(map[string]string{
+		"xlogin": "xlogin@student.42campus.org",
+		"ylogin": "ylogin@student.42campus.org",
+		"zlogin": "zlogin@student.42campus.org",
+	}, emails)
+}
+
+func (s *ClientSuite) Test08_GetUserEmails_NotFound() {
+	emails, err := s.client.GetUserEmails(context.Background(), []string{"wlogin", "ylogin", "unknown"})
+	s.Require().Error(err)
+	s.Equal(&intra.UsersNotFoundError{
+		Logins: []string{"wlogin", "unknown"},
+		Errors: map[string]error{"wlogin": &intra.InvalidModelError{Model: "user", ID: "wlogin", Field: "email"}},
+	}, err)
+	s.Equal(map[string]string{"ylogin": "ylogin@student.42campus.org"}, emails)
+}
+
+func (s *ClientSuite) Test09_ListScaleTeams() {
+	from := time.Date(2020, 7, 15, 0, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour * 24)
+
+	scaleTeams, err := s.client.ListScaleTeams(context.Background(), 0, from, to)
+	s.Require().NoError(err)
+	s.Require().Len(scaleTeams, 2)
+	s.Equal(21, scaleTeams[0].ID)
+	s.Equal(time.Date(2020, 7, 15, 21, 0, 0, 0, time.UTC), scaleTeams[0].BeginAt)
+	s.Equal("xlogin", scaleTeams[0].Corrector.Login)
+	s.Equal(42, scaleTeams[0].Team.ID)
+	s.Equal(22, scaleTeams[1].ID)
+}
+
+func (s *ClientSuite) Test10_ListScaleTeams_Campus() {
+	from := time.Date(2020, 7, 15, 0, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour * 24 * 7)
+
+	scaleTeams, err := s.client.ListScaleTeams(context.Background(), 1, from, to)
+	s.Require().NoError(err)
+	s.Require().Len(scaleTeams, 2)
+	s.Equal(21, scaleTeams[0].ID)
+	s.Equal(23, scaleTeams[1].ID)
+}
+
+func (s *ClientSuite) Test11_ListScaleTeams_MissingField() {
+	var payload map[string]interface{}
+	s.Require().NoError(json.Unmarshal(scaleTeamPayload(22, "2020-07-15T22:00:00.000Z"), &payload))
+	delete(payload, "updated_at")
+	raw, err := json.Marshal(payload)
+	s.Require().NoError(err)
+	s.Require().NoError(s.api.AddScaleTeam(7, raw))
+
+	// Only the invalid scale team is left out.
+	from := time.Date(2020, 7, 15, 0, 0, 0, 0, time.UTC)
+	scaleTeams, err := s.client.ListScaleTeams(context.Background(), 0, from, from.Add(time.Hour*24))
+	s.Require().NoError(err)
+	s.Require().Len(scaleTeams, 1)
+	s.Equal(21, scaleTeams[0].ID)
+}
+
+func (s *ClientSuite) Test12_GetScaleTeam() {
+	scaleTeam, err := s.client.GetScaleTeam(context.Background(), 22)
+	s.Require().NoError(err)
+	s.Equal(22, scaleTeam.ID)
+	s.Equal(time.Date(2020, 7, 14, 10, 0, 0, 0, time.UTC), scaleTeam.UpdatedAt)
+}
+
+func (s *ClientSuite) Test13_GetScaleTeam_NotFound() {
+	_, err := s.client.GetScaleTeam(context.Background(), 24)
+	s.True(intra.IsNotFound(err))
+}
+
+func (s *ClientSuite) Test14_GetScaleTeam_UnknownField() {
+	var payload map[string]interface{}
+	s.Require().NoError(json.Unmarshal(scaleTeamPayload(22, "2020-07-15T22:00:00.000Z"), &payload))
+	payload["renamed_begin_at"] = payload["begin_at"]
+	raw, err := json.Marshal(payload)
+	s.Require().NoError(err)
+	s.Require().NoError(s.api.AddScaleTeam(7, raw))
+
+	// The field added to the API's schema is only logged.
+	scaleTeam, err := s.client.GetScaleTeam(context.Background(), 22)
+	s.Require().NoError(err)
+	s.Equal(22, scaleTeam.ID)
+
+	from := time.Date(2020, 7, 15, 0, 0, 0, 0, time.UTC)
+	scaleTeams, err := s.client.ListScaleTeams(context.Background(), 0, from, from.Add(time.Hour*24))
+	s.Require().NoError(err)
+	s.Len(scaleTeams, 2)
+}
+
+func (s *ClientSuite) Test15_GetScaleTeam_ServerError() {
+	s.api.Fail(1+resilient.DefaultMaxRetries, http.StatusInternalServerError)
+
+	_, err := s.client.GetScaleTeam(context.Background(), 22)
+	s.Error(err)
+	s.False(intra.IsNotFound(err))
+	s.Equal(1+resilient.DefaultMaxRetries, s.api.Requests("/v2/scale_teams/:id"))
+}
+
+func (s *ClientSuite) Test16_ListScaleTeams_ServerError() {
+	s.api.Fail(1+resilient.DefaultMaxRetries, http.StatusServiceUnavailable)
+
+	from := time.Date(2020, 7, 15, 0, 0, 0, 0, time.UTC)
+	scaleTeams, err := s.client.ListScaleTeams(context.Background(), 1, from, from.Add(time.Hour*24))
+	s.Error(err)
+	s.Nil(scaleTeams)
+}
+
+func (s *ClientSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *ClientSuite) TearDownSuite() {
+	config.Conf.Intra.URL = ""
+	config.Conf.Intra.MaxRetries = 0
+}
@@ -1,8 +1,15 @@
 package intra
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
+)
+
+var (
+	// RateLimitDeadlineError is returned when the rate limit would not allow a request before its context's deadline.
+	RateLimitDeadlineError = errors.New("the rate limit does not allow the request before the deadline")
 )
 
 func validateResponse(resp *http.Response) error {
@@ -26,3 +33,71 @@ func (err *HTTPError) Error() string {
 		err.Response.StatusCode,
 		err.Response.Status)
 }
+
+// UsersNotFoundError is returned when the API did not return some of the requested users, or returned them invalid.
+type UsersNotFoundError struct {
+	Logins []string
+	// Errors holds the error of the users which were returned invalid, e.g: without any email, by login.
+	Errors map[string]error
+}
+
+// Error formats the UsersNotFoundError with each login which was not found, or the error of its user.
+func (err *UsersNotFoundError) Error() string {
+	notFound := make([]string, len(err.Logins))
+	for i, login := range err.Logins {
+		if userErr, ok := err.Errors[login]; ok {
+			notFound[i] = userErr.Error()
+			continue
+		}
+		notFound[i] = fmt.Sprintf("no intra user with the login '%s'", login)
+	}
+	return strings.Join(notFound, "; ")
+}
+
+// DecodeError is returned when a payload of the API can not be decoded, e.g: because its schema changed.
+type DecodeError struct {
+	Endpoint string
+	Err      error
+}
+
+// Error formats the DecodeError with the endpoint and the decoding error.
+func (err *DecodeError) Error() string {
+	return fmt.Sprintf("could not decode the payload of %s: %v", err.Endpoint, err.Err)
+}
+
+// Unwrap returns the decoding error.
+func (err *DecodeError) Unwrap() error {
+	return err.Err
+}
+
+// InvalidModelError is returned when a payload of the API lacks a field required by its model.
+type InvalidModelError struct {
+	Model string
+	// ID identifies the invalid record, if the payload carries anything identifying it.
+	ID    string
+	Field string
+}
+
+// Error formats the InvalidModelError with the record and its missing field.
+func (err *InvalidModelError) Error() string {
+	if err.ID == "" || err.ID == "0" {
+		return fmt.Sprintf("invalid intra %s: missing %s", err.Model, err.Field)
+	}
+	return fmt.Sprintf("invalid intra %s '%s': missing %s", err.Model, err.ID, err.Field)
+}
+
+// IsNotFound reports whether the error is the API telling that the requested resource does not exist.
+func IsNotFound(err error) bool {
+	var httpErr *HTTPError
+	return errors.As(err, &httpErr) && httpErr.Response.StatusCode == http.StatusNotFound
+}
+
+// InvalidFieldError is returned when a query is built with a field name which is not of the API's format.
+type InvalidFieldError struct {
+	field string
+}
+
+// Error formats the InvalidFieldError with the invalid field name.
+func (err *InvalidFieldError) Error() string {
+	return fmt.Sprintf("invalid field name '%s': expected lowercase letters, digits and underscores", err.field)
+}
@@ -2,9 +2,18 @@ package intra
 
 import (
 	"context"
+	"time"
 )
 
 type Client interface {
 	GetTeamMembers(ctx context.Context, teamID int) ([]string, error)
 	GetUserEmail(ctx context.Context, login string) (string, error)
+	// GetUserEmails returns the emails of the users mapped by their login. If some of them were not found, or had no
+	// email, the error is a *UsersNotFoundError and the emails of the others are returned along with it.
+	GetUserEmails(ctx context.Context, logins []string) (map[string]string, error)
+	// ListScaleTeams returns the scale teams beginning between `from` and `to`, of every campus if `campusID` is 0. A
+	// scale team lacking a required field is logged and left out, and the unknown fields of the payloads are logged.
+	ListScaleTeams(ctx context.Context, campusID int, from, to time.Time) ([]ScaleTeam, error)
+	// GetScaleTeam returns a scale team. The error satisfies IsNotFound if it does not exist anymore.
+	GetScaleTeam(ctx context.Context, id int) (*ScaleTeam, error)
 }
@@ -1,86 +1,276 @@
 package intra
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
 	"time"
 
+	"github.com/gustavobelfort/42-jitsi/internal/config"
 	"github.com/gustavobelfort/42-jitsi/internal/intra/oauth"
+	"github.com/gustavobelfort/42-jitsi/internal/logging"
+	"github.com/sirupsen/logrus"
 )
 
 var (
-	baseURL = "https://api.intra.42.fr/"
+	// pageSize is the number of items requested per page on the list endpoints, the maximum allowed by the API.
+	pageSize = 100
 )
 
+// rangeTimeLayout is the time format of the values of the `range` parameters.
+const rangeTimeLayout = "2006-01-02T15:04:05Z"
+
 // intraClient to make request to 42's API.
 type intraClient struct {
 	oauthClient *oauth.Client
+	limiter     *limiter
+	maxRetries  int
 }
 
-// NewClient returns a new client made to make request to 42's API.
+// NewClient returns a new client made to make request to 42's API, under the configured rate limits.
 func NewClient(clientID, clientSecret string, httpClient *http.Client) (Client, error) {
-	client, err := oauth.NewClient(baseURL, clientID, clientSecret, httpClient)
+	client, err := oauth.NewClient(
+		config.Conf.Intra.URL,
+		clientID,
+		clientSecret,
+		httpClient,
+		oauth.TokenRefreshSkewOption(config.Conf.Intra.TokenRefreshSkew),
+	)
 	if err != nil {
 		return nil, err
 	}
 
 	return &intraClient{
 		oauthClient: client,
+		limiter:     newLimiter(config.Conf.Intra.RateLimit, config.Conf.Intra.HourlyLimit),
+		maxRetries:  config.Conf.Intra.MaxRetries,
 	}, nil
 }
 
-// Request makes a request to the API through the configured client.
-func (c *intraClient) request(ctx context.Context, method, endpoint string, params oauth.Params, data map[string]interface{}, v interface{}) error {
-	resp, err := c.oauthClient.Request(ctx, method, endpoint, params, data)
-	if err != nil {
-		return err
-	}
-	if resp.StatusCode == 429 {
-		retryAfter, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+// do makes a request to the API through the configured client and returns the successful response.
+//
+// The request waits for the client's rate limiter, and is retried up to `maxRetries` times if the API answers that
+// the application's rate limit was exceeded anyway.
+func (c *intraClient) do(ctx context.Context, method, endpoint string, params oauth.Params, data map[string]interface{}) (*http.Response, error) {
+	logger := logging.ContextLog(ctx, logrus.StandardLogger()).WithField("endpoint", endpoint)
+	for retries := 0; ; retries++ {
+		if err := c.wait(ctx, logger); err != nil {
+			return nil, err
+		}
+
+		resp, err := c.oauthClient.Request(ctx, method, endpoint, params, data)
 		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusTooManyRequests {
+			if err := validateResponse(resp); err != nil {
+				resp.Body.Close()
+				return nil, err
+			}
+			return resp, nil
+		}
+
+		retryAfter, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+		if err != nil || retries >= c.maxRetries {
 			// If can't parse "Retry-After", return the response
 			// atoi's error won't be useful.
-			return &HTTPError{Response: resp}
+			resp.Body.Close()
+			return nil, &HTTPError{Response: resp}
+		}
+		resp.Body.Close()
+
+		logger.WithField("retry", retries+1).Warnf("rate limited by the intra api: retrying in %ds", retryAfter)
+		if err := sleep(ctx, time.Second*time.Duration(retryAfter)); err != nil {
+			return nil, err
 		}
-		time.Sleep(time.Second * time.Duration(retryAfter))
-		return c.request(ctx, method, endpoint, params, data, v)
 	}
+}
 
-	if err := validateResponse(resp); err != nil {
+// wait waits for the rate limiter to allow a request, and logs how long it waited.
+func (c *intraClient) wait(ctx context.Context, logger *logrus.Entry) error {
+	waited, err := c.limiter.Wait(ctx)
+	if err != nil {
 		return err
 	}
+	switch {
+	case waited >= time.Second:
+		logger.WithField("waited", waited.String()).Warn("request held by the intra api rate limiter")
+	case waited > 0:
+		logger.WithField("waited", waited.String()).Debug("request held by the intra api rate limiter")
+	}
+	return nil
+}
 
-	return json.NewDecoder(resp.Body).Decode(v)
+// sleep pauses for the duration, unless the context is done before.
+func sleep(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Request makes a request to the API through the configured client and decodes its response into v.
+func (c *intraClient) request(ctx context.Context, method, endpoint string, params oauth.Params, data map[string]interface{}, v interface{}) error {
+	resp, err := c.do(ctx, method, endpoint, params, data)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return decode(endpoint, resp.Body, v)
 }
 
 // GetUserEmail returns a user's email with 42's API.
 func (c *intraClient) GetUserEmail(ctx context.Context, login string) (string, error) {
-	endpoint := fmt.Sprintf("/v2/users/%s", login)
-
-	user := make(map[string]interface{})
+	endpoint := fmt.Sprintf("/v2/users/%s", url.PathEscape(login))
 
+	var user User
 	if err := c.request(ctx, http.MethodGet, endpoint, nil, nil, &user); err != nil {
 		return "", err
 	}
+	if user.Email == "" {
+		return "", &InvalidModelError{Model: "user", ID: login, Field: "email"}
+	}
+	return user.Email, nil
+}
+
+// GetUserEmails returns the emails of many users with 42's API, requested by chunks of `pageSize` logins.
+func (c *intraClient) GetUserEmails(ctx context.Context, logins []string) (map[string]string, error) {
+	logins = uniqueLogins(logins)
+	emails := make(map[string]string, len(logins))
+	var invalid map[string]error
+	for start := 0; start < len(logins); start += pageSize {
+		end := start + pageSize
+		if end > len(logins) {
+			end = len(logins)
+		}
+		chunk := make([]interface{}, 0, end-start)
+		for _, login := range logins[start:end] {
+			chunk = append(chunk, login)
+		}
+
+		pages := c.paginate("/v2/users", NewQuery().Filter("login", chunk...))
+		for {
+			var users []User
+			if !pages.Next(ctx, &users) {
+				break
+			}
+			for _, user := range users {
+				if user.Email == "" {
+					if invalid == nil {
+						invalid = make(map[string]error)
+					}
+					invalid[user.Login] = &InvalidModelError{Model: "user", ID: user.Login, Field: "email"}
+					continue
+				}
+				emails[user.Login] = user.Email
+			}
+		}
+		if err := pages.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	if missing := missingLogins(logins, emails); len(missing) > 0 {
+		return emails, &UsersNotFoundError{Logins: missing, Errors: invalid}
+	}
+	return emails, nil
+}
+
+// uniqueLogins returns the logins without their duplicates, in the same order.
+func uniqueLogins(logins []string) []string {
+	seen := make(map[string]bool, len(logins))
+	unique := make([]string, 0, len(logins))
+	for _, login := range logins {
+		if !seen[login] {
+			seen[login] = true
+			unique = append(unique, login)
+		}
+	}
+	return unique
+}
 
-	return user["email"].(string), nil
+// missingLogins returns the logins which are not in the emails.
+func missingLogins(logins []string, emails map[string]string) []string {
+	var missing []string
+	for _, login := range logins {
+		if _, ok := emails[login]; !ok {
+			missing = append(missing, login)
+		}
+	}
+	return missing
 }
 
 // GetTeamMembers returns the members of a team with 42's API.
 func (c *intraClient) GetTeamMembers(ctx context.Context, teamID int) ([]string, error) {
 	endpoint := fmt.Sprintf("/v2/teams/%d/users", teamID)
 
-	users := make([]map[string]interface{}, 0)
-	if err := c.request(ctx, http.MethodGet, endpoint, nil, nil, &users); err != nil {
+	logins := make([]string, 0)
+	pages := c.paginate(endpoint, NewQuery())
+	for {
+		var users []User
+		if !pages.Next(ctx, &users) {
+			break
+		}
+		for _, user := range users {
+			logins = append(logins, user.Login)
+		}
+	}
+	if err := pages.Err(); err != nil {
 		return nil, err
 	}
+	return logins, nil
+}
 
-	logins := make([]string, len(users))
-	for i, user := range users {
-		logins[i] = user["login"].(string)
+// ListScaleTeams returns the scale teams beginning between `from` and `to` with 42's API.
+//
+// Every page is requested. The scale teams are decoded one by one, so that an invalid one is logged and left out
+// instead of failing the whole listing.
+func (c *intraClient) ListScaleTeams(ctx context.Context, campusID int, from, to time.Time) ([]ScaleTeam, error) {
+	endpoint := "/v2/scale_teams"
+	if campusID != 0 {
+		endpoint = fmt.Sprintf("/v2/campus/%d/scale_teams", campusID)
 	}
-	return logins, nil
+	query := NewQuery().Range("begin_at", from, to).Sort("begin_at")
+	logger := logging.ContextLog(ctx, logrus.StandardLogger()).WithField("endpoint", endpoint)
+
+	scaleTeams := make([]ScaleTeam, 0)
+	pages := c.paginate(endpoint, query)
+	for {
+		var page []json.RawMessage
+		if !pages.Next(ctx, &page) {
+			break
+		}
+		for _, payload := range page {
+			var scaleTeam ScaleTeam
+			if err := decode(endpoint, bytes.NewReader(payload), &scaleTeam); err != nil {
+				logging.LogError(logger, err, "leaving out an invalid intra scale team")
+				continue
+			}
+			scaleTeams = append(scaleTeams, scaleTeam)
+		}
+	}
+	if err := pages.Err(); err != nil {
+		return nil, err
+	}
+	return scaleTeams, nil
+}
+
+// GetScaleTeam returns a scale team with 42's API.
+func (c *intraClient) GetScaleTeam(ctx context.Context, id int) (*ScaleTeam, error) {
+	endpoint := fmt.Sprintf("/v2/scale_teams/%d", id)
+
+	scaleTeam := &ScaleTeam{}
+	if err := c.request(ctx, http.MethodGet, endpoint, nil, nil, scaleTeam); err != nil {
+		return nil, err
+	}
+	return scaleTeam, nil
 }
@@ -3,10 +3,12 @@ package intra
 import (
 	"context"
 	"errors"
-	"strconv"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gustavobelfort/42-jitsi/internal/config"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
@@ -15,6 +17,8 @@ func TestIntraClient(t *testing.T) {
 	suite.Run(t, new(IntraClientSuite))
 }
 
+// IntraClientSuite tests the client against responses the fake API of intratest does not serve, e.g: malformed
+// payloads or other pagination headers, and its internals.
 type IntraClientSuite struct {
 	suite.Suite
 
@@ -26,7 +30,8 @@ func (s *IntraClientSuite) SetupSuite() {
 	s.Require().Implements((*Client)(nil), &intraClient{})
 
 	s.mock = NewServerMock()
-	baseURL = s.mock.Server.URL
+	config.Conf.Intra.URL = s.mock.Server.URL
+	config.Conf.Intra.MaxRetries = 3
 	client, err := NewClient("id", "secret", s.mock.Server.Client())
 	s.Require().NoError(err)
 	s.Require().NotNil(client)
@@ -39,86 +44,127 @@ func (s *IntraClientSuite) SetupTest() {
 	s.mock.Calls = []mock.Call{}
 }
 
-func (s *IntraClientSuite) Test00_GetUserEmail() {
+func (s *IntraClientSuite) Test00_RateLimitHandling_Error() {
 	expectedLogin := "xlogin"
-	expectedEmail := "xlogin@student.42campus.org"
-	expectedPayload := gin.H{
-		"email": expectedEmail,
-	}
 
-	s.mock.On("GetUser", expectedLogin).Return(200, expectedPayload, gin.H{}, gin.H{}).Once()
+	s.mock.On("GetUser", expectedLogin).Return(429, gin.H{}, gin.H{}).Once()
 	email, err := s.client.GetUserEmail(context.Background(), expectedLogin)
-	s.NoError(err)
-	s.Equal(expectedEmail, email)
+	s.Error(err)
+	s.Zero(email)
 }
 
-func (s *IntraClientSuite) Test01_GetUserEmail_Error() {
-	expectedLogin := "xlogin"
+func (s *IntraClientSuite) Test01_GetTeamMembers_LinkHeader() {
+	next := fmt.Sprintf(`<%s/v2/teams/4242/users?page[number]=2&page[size]=2>; rel="next"`, s.mock.Server.URL)
+	last := fmt.Sprintf(`<%s/v2/teams/4242/users?page[number]=1&page[size]=2>; rel="first"`, s.mock.Server.URL)
 
-	s.mock.On("GetUser", expectedLogin).Return(404, gin.H{}, gin.H{}).Once()
-	email, err := s.client.GetUserEmail(context.Background(), expectedLogin)
-	s.Error(err)
-	s.Zero(email)
+	s.mock.On("GetTeamUsers", "4242", "1").Return(200, []gin.H{{"id": 1, "login": "xlogin"}, {"id": 2, "login": "ylogin"}}, gin.H{"Link": next}).Once()
+	s.mock.On("GetTeamUsers", "4242", "2").Return(200, []gin.H{{"id": 3, "login": "zlogin"}}, gin.H{"Link": last}).Once()
+
+	logins, err := s.client.GetTeamMembers(context.Background(), 4242)
+	s.Require().NoError(err)
+	s.Equal([]string{"xlogin", "ylogin", "zlogin"}, logins)
 }
 
-func (s *IntraClientSuite) Test02_GetTeamMembers() {
-	expectedID := 4242
-	expectedLogins := []string{"xlogin", "ylogin", "zlogin"}
-	expectedPayload := []gin.H{
-		{"login": expectedLogins[0]},
-		{"login": expectedLogins[1]},
-		{"login": expectedLogins[2]},
-	}
+func (s *IntraClientSuite) Test02_GetTeamMembers_TotalHeader() {
+	pageSize = 2
+	defer func() { pageSize = 100 }()
 
-	s.mock.On("GetTeamUsers", strconv.Itoa(expectedID)).Return(200, expectedPayload, gin.H{}).Once()
-	logins, err := s.client.GetTeamMembers(context.Background(), expectedID)
-	s.NoError(err)
-	s.Equal(expectedLogins, logins)
+	headers := gin.H{"X-Total": "4", "X-Per-Page": "2"}
+	s.mock.On("GetTeamUsers", "4242", "1").Return(200, []gin.H{{"id": 1, "login": "xlogin"}, {"id": 2, "login": "ylogin"}}, headers).Once()
+	s.mock.On("GetTeamUsers", "4242", "2").Return(200, []gin.H{{"id": 3, "login": "zlogin"}, {"id": 4, "login": "wlogin"}}, headers).Once()
 
+	logins, err := s.client.GetTeamMembers(context.Background(), 4242)
+	s.Require().NoError(err)
+	s.Equal([]string{"xlogin", "ylogin", "zlogin", "wlogin"}, logins)
 }
 
-func (s *IntraClientSuite) Test03_GetTeamMembers_Error() {
-	expectedID := 4242
+func (s *IntraClientSuite) Test03_Paginate_ContextCanceled() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.mock.On("GetTeamUsers", "4242", "1").Return(200, []gin.H{{"id": 1, "login": "xlogin"}}, gin.H{"X-Total": "200"}).Once()
 
-	s.mock.On("GetTeamUsers", strconv.Itoa(expectedID)).Return(404, gin.H{}, gin.H{}).Once()
-	logins, err := s.client.GetTeamMembers(context.Background(), expectedID)
-	s.Error(err)
-	s.Zero(logins)
+	pages := s.client.paginate("/v2/teams/4242/users", NewQuery())
+	var users []gin.H
+	s.True(pages.Next(ctx, &users))
+	s.Len(users, 1)
+
+	cancel()
+	s.False(pages.Next(ctx, &users))
+	s.True(errors.Is(pages.Err(), context.Canceled))
 }
 
-func (s *IntraClientSuite) Test04_RateLimitHandling() {
-	expectedLogin := "xlogin"
-	expectedEmail := "xlogin@student.42campus.org"
-	expectedPayload := gin.H{
-		"email": expectedEmail,
-	}
+func (s *IntraClientSuite) Test04_Paginate_InvalidQuery() {
+	pages := s.client.paginate("/v2/teams/4242/users", NewQuery().Filter("login]=", "xlogin"))
 
-	s.mock.On("GetUser", expectedLogin).Return(429, gin.H{}, gin.H{"Retry-After": "1"}).Twice()
-	s.mock.On("GetUser", expectedLogin).Return(200, expectedPayload, gin.H{}, gin.H{}).Once()
-	email, err := s.client.GetUserEmail(context.Background(), expectedLogin)
-	s.NoError(err)
-	s.Equal(expectedEmail, email)
+	var users []gin.H
+	s.False(pages.Next(context.Background(), &users))
+	s.IsType(&InvalidFieldError{}, pages.Err())
 }
 
-func (s *IntraClientSuite) Test05_RateLimitHandling_Error() {
-	expectedLogin := "xlogin"
+func (s *IntraClientSuite) Test05_RateLimitHandling_MaxRetries() {
+	s.client.maxRetries = 1
+	defer func() { s.client.maxRetries = 3 }()
 
-	s.mock.On("GetUser", expectedLogin).Return(429, gin.H{}, gin.H{}).Once()
-	email, err := s.client.GetUserEmail(context.Background(), expectedLogin)
-	s.Error(err)
-	s.Zero(email)
+	s.mock.On("GetUser", "xlogin").Return(429, gin.H{}, gin.H{"Retry-After": "1"}).Twice()
+	_, err := s.client.GetUserEmail(context.Background(), "xlogin")
+	s.Require().Error(err)
+	s.IsType(&HTTPError{}, err)
 }
 
-func (s *IntraClientSuite) Test06_ContextCanceled() {
-	expectedLogin := "xlogin"
+func (s *IntraClientSuite) Test06_RateLimitHandling_ContextDone() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel()
 
-	expectedContext, cancel := context.WithCancel(context.Background())
-	cancel()
+	s.mock.On("GetUser", "xlogin").Return(429, gin.H{}, gin.H{"Retry-After": "60"}).Once()
+	_, err := s.client.GetUserEmail(ctx, "xlogin")
+	s.True(errors.Is(err, context.DeadlineExceeded))
+}
+
+func (s *IntraClientSuite) Test07_RateLimiter() {
+	s.client.limiter = newLimiter(1, 0)
+	defer func() { s.client.limiter = newLimiter(0, 0) }()
+
+	s.mock.On("GetUser", "xlogin").Return(200, gin.H{"id": 1, "login": "xlogin", "email": "xlogin@student.42campus.org"}, gin.H{}).Once()
+	_, err := s.client.GetUserEmail(context.Background(), "xlogin")
+	s.Require().NoError(err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel()
+	_, err = s.client.GetUserEmail(ctx, "xlogin")
+	s.Equal(RateLimitDeadlineError, err)
+}
+
+func (s *IntraClientSuite) Test08_GetUserEmails_Chunks() {
+	pageSize = 2
+	defer func() { pageSize = 100 }()
 
-	email, err := s.client.GetUserEmail(expectedContext, expectedLogin)
+	s.mock.On("GetUsers", "xlogin,ylogin", "1").Return(200, []gin.H{
+		{"id": 1, "login": "xlogin", "email": "xlogin@student.42campus.org"},
+		{"id": 2, "login": "ylogin", "email": "ylogin@student.42campus.org"},
+	}).Once()
+	s.mock.On("GetUsers", "xlogin,ylogin", "2").Return(200, []gin.H{}).Once()
+	s.mock.On("GetUsers", "zlogin", "1").Return(200, []gin.H{
+		{"id": 3, "login": "zlogin", "email": "zlogin@student.42campus.org"},
+	}).Once()
+
+	emails, err := s.client.GetUserEmails(context.Background(), []string{"xlogin", "ylogin", "zlogin"})
+	s.Require().NoError(err)
+	s.Len(emails, 3)
+}
+
+func (s *IntraClientSuite) Test09_GetUserEmails_Error() {
+	s.mock.On("GetUsers", "xlogin", "1").Return(404, gin.H{}).Once()
+
+	emails, err := s.client.GetUserEmails(context.Background(), []string{"xlogin"})
 	s.Error(err)
-	s.True(errors.Is(err, context.Canceled))
-	s.Zero(email)
+	s.Nil(emails)
+}
+
+func (s *IntraClientSuite) Test10_GetUserEmails_SchemaChanged() {
+	s.mock.On("GetUsers", "xlogin", "1").Return(200, gin.H{"users": []gin.H{}}).Once()
+
+	_, err := s.client.GetUserEmails(context.Background(), []string{"xlogin"})
+	var decodeErr *DecodeError
+	s.True(errors.As(err, &decodeErr))
 }
 
 func (s *IntraClientSuite) TearDownTest() {
@@ -127,7 +173,7 @@ func (s *IntraClientSuite) TearDownTest() {
 
 func (s *IntraClientSuite) TearDownSuite() {
 	s.mock.Server.Close()
-	baseURL = "https://api.intra.42.fr/"
+	config.Conf.Intra.URL = ""
 }
 
 //func TestGetUserEmail(t *testing.T) {
@@ -0,0 +1,75 @@
+package intratest
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/gustavobelfort/42-jitsi/internal/intra"
+)
+
+// Fixtures are the records served by the fake API.
+type Fixtures struct {
+	Users      []intra.User `json:"users"`
+	Teams      []Team       `json:"teams"`
+	ScaleTeams []ScaleTeam  `json:"scale_teams"`
+}
+
+// Team is a team served by the fake API, whose members are users of the fixtures.
+type Team struct {
+	ID     int      `json:"id"`
+	Logins []string `json:"logins"`
+}
+
+// ScaleTeam is a scale team served by the fake API: its payload as returned by the API, and the campus it belongs to.
+type ScaleTeam struct {
+	CampusID int             `json:"campus_id"`
+	Payload  json.RawMessage `json:"payload"`
+}
+
+// LoadFixtures reads the fixtures of a JSON file.
+func LoadFixtures(path string) (*Fixtures, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	fixtures := &Fixtures{}
+	if err := json.NewDecoder(file).Decode(fixtures); err != nil {
+		return nil, err
+	}
+	return fixtures, nil
+}
+
+// ShiftScaleTeams moves the scale teams in time so that the first one begins at `first`, keeping the delays between
+// them. It lets the fixtures be served as upcoming evaluations.
+func (fixtures *Fixtures) ShiftScaleTeams(first time.Time) error {
+	payloads := make([]map[string]interface{}, len(fixtures.ScaleTeams))
+	beginAts := make([]time.Time, len(fixtures.ScaleTeams))
+	var earliest time.Time
+	for i, scaleTeam := range fixtures.ScaleTeams {
+		if err := json.Unmarshal(scaleTeam.Payload, &payloads[i]); err != nil {
+			return err
+		}
+		decoded, err := decodeScaleTeam(scaleTeam.Payload)
+		if err != nil {
+			return err
+		}
+		beginAts[i] = decoded.BeginAt
+		if earliest.IsZero() || decoded.BeginAt.Before(earliest) {
+			earliest = decoded.BeginAt
+		}
+	}
+
+	shift := first.Sub(earliest)
+	for i := range fixtures.ScaleTeams {
+		payloads[i]["begin_at"] = beginAts[i].Add(shift).UTC().Format(time.RFC3339)
+		payload, err := json.Marshal(payloads[i])
+		if err != nil {
+			return err
+		}
+		fixtures.ScaleTeams[i].Payload = payload
+	}
+	return nil
+}
@@ -0,0 +1,474 @@
+package intratest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gustavobelfort/42-jitsi/internal/intra"
+)
+
+const (
+	// defaultPageSize and defaultMaxPageSize are the number of items per page of the API, by default and at most.
+	defaultPageSize    = 30
+	defaultMaxPageSize = 100
+
+	// tokenLifetime is how long the tokens of the API are valid.
+	tokenLifetime = time.Hour * 2
+
+	// rangeTimeLayout is the time format of the values of the `range` parameters.
+	rangeTimeLayout = "2006-01-02T15:04:05Z"
+)
+
+// API is a fake of the intra API serving fixtures, with the API's authentication, pagination and rate limiting.
+type API struct {
+	handler http.Handler
+
+	mu           sync.Mutex
+	fixtures     Fixtures
+	tokens       map[string]bool
+	clientID     string
+	clientSecret string
+	rateLimit    int
+	window       time.Time
+	windowCount  int
+	throttled    int
+	failed       int
+	failStatus   int
+	maxPageSize  int
+	requests     map[string]int
+}
+
+// Option configures an API.
+type Option func(*API)
+
+// CredentialsOption restricts the tokens to the application with the given credentials. Any application gets one by
+// default.
+func CredentialsOption(clientID, clientSecret string) Option {
+	return func(api *API) {
+		api.clientID, api.clientSecret = clientID, clientSecret
+	}
+}
+
+// RateLimitOption answers "429 Too Many Requests" to the requests exceeding `perSecond` within a second.
+func RateLimitOption(perSecond int) Option {
+	return func(api *API) {
+		api.rateLimit = perSecond
+	}
+}
+
+// MaxPageSizeOption sets the number of items per page of the API at most, so that the listings span several pages
+// without as many fixtures.
+func MaxPageSizeOption(size int) Option {
+	return func(api *API) {
+		api.maxPageSize = size
+	}
+}
+
+// NewAPI returns a fake API serving the fixtures.
+func NewAPI(fixtures *Fixtures, options ...Option) *API {
+	api := &API{
+		tokens:      make(map[string]bool),
+		maxPageSize: defaultMaxPageSize,
+		requests:    make(map[string]int),
+	}
+	if fixtures != nil {
+		api.fixtures = *fixtures
+	}
+	for _, option := range options {
+		option(api)
+	}
+
+	router := gin.New()
+	router.Use(gin.Recovery())
+	router.POST("/oauth/token", api.token)
+
+	v2 := router.Group("/v2", api.count, api.authenticate, api.fail, api.limit)
+	v2.GET("/users", api.listUsers)
+	v2.GET("/users/:login", api.getUser)
+	v2.GET("/teams/:id/users", api.listTeamUsers)
+	v2.GET("/scale_teams", api.listScaleTeams)
+	v2.GET("/scale_teams/:id", api.getScaleTeam)
+	v2.GET("/campus/:id/scale_teams", api.listScaleTeams)
+	api.handler = router
+	return api
+}
+
+// NewServer starts a server serving a new fake API, which has to be closed.
+func NewServer(fixtures *Fixtures, options ...Option) (*httptest.Server, *API) {
+	api := NewAPI(fixtures, options...)
+	return httptest.NewServer(api), api
+}
+
+// ServeHTTP serves the request like the intra API would.
+func (api *API) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	api.handler.ServeHTTP(w, req)
+}
+
+// Throttle answers "429 Too Many Requests" with a `Retry-After` of a second to the next `requests` API requests.
+func (api *API) Throttle(requests int) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	api.throttled = requests
+}
+
+// Fail answers the next `requests` API requests with the status, e.g: "503 Service Unavailable".
+func (api *API) Fail(requests, status int) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	api.failed, api.failStatus = requests, status
+}
+
+// Requests returns the number of requests received by the route, e.g: "/v2/users/:login", including the rejected
+// ones.
+func (api *API) Requests(route string) int {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	return api.requests[route]
+}
+
+// AddScaleTeam serves a new scale team, or replaces the one with the same id.
+func (api *API) AddScaleTeam(campusID int, payload json.RawMessage) error {
+	scaleTeam, err := decodeScaleTeam(payload)
+	if err != nil {
+		return err
+	}
+
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	api.removeScaleTeam(scaleTeam.ID)
+	api.fixtures.ScaleTeams = append(api.fixtures.ScaleTeams, ScaleTeam{CampusID: campusID, Payload: payload})
+	return nil
+}
+
+// SetTeam serves the team, replacing the one with the same id. Its members are the users of the fixtures.
+func (api *API) SetTeam(team Team) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	for i := range api.fixtures.Teams {
+		if api.fixtures.Teams[i].ID == team.ID {
+			api.fixtures.Teams[i] = team
+			return
+		}
+	}
+	api.fixtures.Teams = append(api.fixtures.Teams, team)
+}
+
+// RemoveScaleTeam stops serving a scale team, as if it was destroyed.
+func (api *API) RemoveScaleTeam(id int) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	api.removeScaleTeam(id)
+}
+
+func (api *API) removeScaleTeam(id int) {
+	kept := api.fixtures.ScaleTeams[:0]
+	for _, scaleTeam := range api.fixtures.ScaleTeams {
+		if decoded, err := decodeScaleTeam(scaleTeam.Payload); err != nil || decoded.ID != id {
+			kept = append(kept, scaleTeam)
+		}
+	}
+	api.fixtures.ScaleTeams = kept
+}
+
+func (api *API) token(ctx *gin.Context) {
+	var credentials struct {
+		GrantType    string `json:"grant_type" form:"grant_type"`
+		ClientID     string `json:"client_id" form:"client_id"`
+		ClientSecret string `json:"client_secret" form:"client_secret"`
+	}
+	if err := ctx.ShouldBind(&credentials); err != nil || credentials.GrantType != "client_credentials" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request"})
+		return
+	}
+	if api.clientID != "" && (credentials.ClientID != api.clientID || credentials.ClientSecret != api.clientSecret) {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_client"})
+		return
+	}
+
+	api.mu.Lock()
+	token := fmt.Sprintf("intratest-%d", len(api.tokens)+1)
+	api.tokens[token] = true
+	api.mu.Unlock()
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"access_token": token,
+		"token_type":   "bearer",
+		"expires_in":   int(tokenLifetime.Seconds()),
+		"scope":        "public",
+		"created_at":   time.Now().Unix(),
+	})
+}
+
+// count counts the requests of each route.
+func (api *API) count(ctx *gin.Context) {
+	api.mu.Lock()
+	api.requests[ctx.FullPath()]++
+	api.mu.Unlock()
+}
+
+// authenticate rejects the requests without a token issued by the API.
+func (api *API) authenticate(ctx *gin.Context) {
+	token := strings.TrimPrefix(ctx.GetHeader("Authorization"), "Bearer ")
+
+	api.mu.Lock()
+	valid := api.tokens[token]
+	api.mu.Unlock()
+	if !valid {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Not authorized"})
+	}
+}
+
+// fail answers the failed requests with their status.
+func (api *API) fail(ctx *gin.Context) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	if api.failed > 0 {
+		api.failed--
+		ctx.AbortWithStatusJSON(api.failStatus, gin.H{"error": http.StatusText(api.failStatus)})
+	}
+}
+
+// limit rejects the throttled requests and the ones exceeding the rate limit.
+func (api *API) limit(ctx *gin.Context) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(api.window) >= time.Second {
+		api.window, api.windowCount = now, 0
+	}
+	api.windowCount++
+
+	if api.throttled > 0 || (api.rateLimit > 0 && api.windowCount > api.rateLimit) {
+		if api.throttled > 0 {
+			api.throttled--
+		}
+		ctx.Header("Retry-After", "1")
+		ctx.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "Too Many Requests"})
+	}
+}
+
+func (api *API) getUser(ctx *gin.Context) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	user, ok := api.user(ctx.Param("login"))
+	if !ok {
+		ctx.JSON(http.StatusNotFound, gin.H{})
+		return
+	}
+	ctx.JSON(http.StatusOK, user)
+}
+
+func (api *API) listUsers(ctx *gin.Context) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	var logins map[string]bool
+	if filter, ok := ctx.GetQuery("filter[login]"); ok {
+		logins = make(map[string]bool)
+		for _, login := range strings.Split(filter, ",") {
+			logins[login] = true
+		}
+	}
+
+	users := make([]interface{}, 0)
+	for _, user := range api.fixtures.Users {
+		if logins == nil || logins[user.Login] {
+			users = append(users, user)
+		}
+	}
+	api.paginate(ctx, users)
+}
+
+func (api *API) listTeamUsers(ctx *gin.Context) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	id, _ := strconv.Atoi(ctx.Param("id"))
+	for _, team := range api.fixtures.Teams {
+		if team.ID != id {
+			continue
+		}
+		users := make([]interface{}, 0, len(team.Logins))
+		for _, login := range team.Logins {
+			if user, ok := api.user(login); ok {
+				users = append(users, user)
+			}
+		}
+		api.paginate(ctx, users)
+		return
+	}
+	ctx.JSON(http.StatusNotFound, gin.H{})
+}
+
+func (api *API) getScaleTeam(ctx *gin.Context) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	id, _ := strconv.Atoi(ctx.Param("id"))
+	for _, scaleTeam := range api.fixtures.ScaleTeams {
+		if decoded, err := decodeScaleTeam(scaleTeam.Payload); err == nil && decoded.ID == id {
+			ctx.JSON(http.StatusOK, scaleTeam.Payload)
+			return
+		}
+	}
+	ctx.JSON(http.StatusNotFound, gin.H{})
+}
+
+// listScaleTeams lists the scale teams, of the campus if the route has one, filtered by `range[begin_at]` and sorted
+// by `sort`, either "begin_at" or "id".
+func (api *API) listScaleTeams(ctx *gin.Context) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	campusID, _ := strconv.Atoi(ctx.Param("id"))
+	from, to, err := parseRange(ctx.Query("range[begin_at]"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	type listed struct {
+		decodedScaleTeam
+		payload json.RawMessage
+	}
+	scaleTeams := make([]listed, 0)
+	for _, scaleTeam := range api.fixtures.ScaleTeams {
+		decoded, err := decodeScaleTeam(scaleTeam.Payload)
+		if err != nil || (campusID != 0 && scaleTeam.CampusID != campusID) {
+			continue
+		}
+		if (!from.IsZero() && decoded.BeginAt.Before(from)) || (!to.IsZero() && decoded.BeginAt.After(to)) {
+			continue
+		}
+		scaleTeams = append(scaleTeams, listed{decodedScaleTeam: *decoded, payload: scaleTeam.Payload})
+	}
+	sort.SliceStable(scaleTeams, func(i, j int) bool {
+		if ctx.Query("sort") == "begin_at" {
+			return scaleTeams[i].BeginAt.Before(scaleTeams[j].BeginAt)
+		}
+		return scaleTeams[i].ID < scaleTeams[j].ID
+	})
+
+	items := make([]interface{}, len(scaleTeams))
+	for i, scaleTeam := range scaleTeams {
+		items[i] = scaleTeam.payload
+	}
+	api.paginate(ctx, items)
+}
+
+func (api *API) user(login string) (intra.User, bool) {
+	for _, user := range api.fixtures.Users {
+		if user.Login == login {
+			return user, true
+		}
+	}
+	return intra.User{}, false
+}
+
+// paginate responds with the page of the items requested by `page[number]` and `page[size]`, along with the
+// pagination headers of the API. It is called with the lock held.
+func (api *API) paginate(ctx *gin.Context, items []interface{}) {
+	size, err := strconv.Atoi(ctx.DefaultQuery("page[size]", strconv.Itoa(defaultPageSize)))
+	if err != nil || size <= 0 {
+		size = defaultPageSize
+	}
+	if size > api.maxPageSize {
+		size = api.maxPageSize
+	}
+	number, err := strconv.Atoi(ctx.DefaultQuery("page[number]", "1"))
+	if err != nil || number <= 0 {
+		number = 1
+	}
+	last := (len(items) + size - 1) / size
+	if last == 0 {
+		last = 1
+	}
+
+	links := []string{
+		pageLink(ctx, 1, size, "first"),
+		pageLink(ctx, last, size, "last"),
+	}
+	if number > 1 {
+		links = append(links, pageLink(ctx, number-1, size, "prev"))
+	}
+	if number < last {
+		links = append(links, pageLink(ctx, number+1, size, "next"))
+	}
+	ctx.Header("Link", strings.Join(links, ", "))
+	ctx.Header("X-Page", strconv.Itoa(number))
+	ctx.Header("X-Per-Page", strconv.Itoa(size))
+	ctx.Header("X-Total", strconv.Itoa(len(items)))
+
+	start, end := (number-1)*size, number*size
+	if start > len(items) {
+		start = len(items)
+	}
+	if end > len(items) {
+		end = len(items)
+	}
+	ctx.JSON(http.StatusOK, items[start:end])
+}
+
+// pageLink formats the link to a page of the requested listing. It links to the requested uri, which keeps the path
+// prefix of the API if it is served under one, e.g: with http.StripPrefix.
+func pageLink(ctx *gin.Context, number, size int, rel string) string {
+	query := ctx.Request.URL.Query()
+	query.Set("page[number]", strconv.Itoa(number))
+	query.Set("page[size]", strconv.Itoa(size))
+	path := ctx.Request.URL.EscapedPath()
+	if requested, err := url.ParseRequestURI(ctx.Request.RequestURI); err == nil {
+		path = requested.EscapedPath()
+	}
+	return fmt.Sprintf(`<http://%s%s?%s>; rel="%s"`, ctx.Request.Host, path, query.Encode(), rel)
+}
+
+// parseRange parses the bounds of a `range` parameter, which are zero if it is empty.
+func parseRange(value string) (time.Time, time.Time, error) {
+	var from, to time.Time
+	if value == "" {
+		return from, to, nil
+	}
+	bounds := strings.Split(value, ",")
+	if len(bounds) != 2 {
+		return from, to, fmt.Errorf("invalid range '%s'", value)
+	}
+	from, err := parseTime(bounds[0])
+	if err != nil {
+		return from, to, err
+	}
+	to, err = parseTime(bounds[1])
+	return from, to, err
+}
+
+func parseTime(value string) (time.Time, error) {
+	if parsed, err := time.Parse(rangeTimeLayout, value); err == nil {
+		return parsed, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// decodedScaleTeam is what the fake API reads of the payloads it serves. They are decoded leniently, so that the tests
+// can serve payloads the client rejects.
+type decodedScaleTeam struct {
+	ID      int       `json:"id"`
+	BeginAt time.Time `json:"begin_at"`
+}
+
+func decodeScaleTeam(payload json.RawMessage) (*decodedScaleTeam, error) {
+	scaleTeam := &decodedScaleTeam{}
+	if err := json.Unmarshal(payload, scaleTeam); err != nil {
+		return nil, err
+	}
+	return scaleTeam, nil
+}
@@ -0,0 +1,247 @@
+package intratest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gustavobelfort/42-jitsi/internal/config"
+	"github.com/gustavobelfort/42-jitsi/internal/intra"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestAPI(t *testing.T) {
+	suite.Run(t, new(APISuite))
+}
+
+type APISuite struct {
+	suite.Suite
+
+	fixtures *Fixtures
+	server   *httptest.Server
+	api      *API
+	client   intra.Client
+}
+
+func (s *APISuite) SetupSuite() {
+	fixtures, err := LoadFixtures("testdata/fixtures.json")
+	s.Require().NoError(err)
+	s.fixtures = fixtures
+}
+
+func (s *APISuite) SetupTest() {
+	s.server, s.api = NewServer(s.fixtures, CredentialsOption("id", "secret"))
+
+	config.Conf.Intra.URL = s.server.URL
+	config.Conf.Intra.MaxRetries = 1
+	client, err := intra.NewClient("id", "secret", s.server.Client())
+	s.Require().NoError(err)
+	s.client = client
+}
+
+func (s *APISuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *APISuite) TearDownSuite() {
+	config.Conf.Intra.URL = ""
+	config.Conf.Intra.MaxRetries = 0
+}
+
+func (s *APISuite) Test00_GetUserEmail() {
+	email, err := s.client.GetUserEmail(context.Background(), "xcorrector")
+	s.Require().NoError(err)
+	s.Equal("xcorrector@student.42.fr", email)
+
+	_, err = s.client.GetUserEmail(context.Background(), "unknown")
+	s.True(intra.IsNotFound(err))
+}
+
+func (s *APISuite) Test01_GetUserEmails() {
+	emails, err := s.client.GetUserEmails(context.Background(), []string{"xcorrected", "ycorrected", "unknown"})
+	s.Require().Error(err)
+	s.IsType(&intra.UsersNotFoundError{}, err)
+	s.Equal(map[string]string{
+		"xcorrected": "xcorrected@student.42.fr",
+		"ycorrected": "ycorrected@student.42.us.org",
+	}, emails)
+	s.Equal(1, s.api.Requests("/v2/users"))
+}
+
+func (s *APISuite) Test02_GetTeamMembers() {
+	logins, err := s.client.GetTeamMembers(context.Background(), 101)
+	s.Require().NoError(err)
+	s.Equal([]string{"xcorrected", "xteammate"}, logins)
+
+	_, err = s.client.GetTeamMembers(context.Background(), 4242)
+	s.True(intra.IsNotFound(err))
+}
+
+func (s *APISuite) Test03_ListScaleTeams() {
+	from := time.Date(2020, 5, 4, 0, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour * 24)
+
+	scaleTeams, err := s.client.ListScaleTeams(context.Background(), 0, from, to)
+	s.Require().NoError(err)
+	s.Equal([]int{1001, 1002}, s.ids(scaleTeams))
+
+	scaleTeams, err = s.client.ListScaleTeams(context.Background(), 1, from, to.Add(time.Hour*24))
+	s.Require().NoError(err)
+	s.Equal([]int{1001, 1003}, s.ids(scaleTeams))
+}
+
+func (s *APISuite) Test04_GetScaleTeam() {
+	scaleTeam, err := s.client.GetScaleTeam(context.Background(), 1002)
+	s.Require().NoError(err)
+	s.Equal(1002, scaleTeam.ID)
+	s.Equal("ycorrector", scaleTeam.Corrector.Login)
+
+	s.api.RemoveScaleTeam(1002)
+	_, err = s.client.GetScaleTeam(context.Background(), 1002)
+	s.True(intra.IsNotFound(err))
+
+	payload, err := json.Marshal(scaleTeam)
+	s.Require().NoError(err)
+	s.Require().NoError(s.api.AddScaleTeam(7, payload))
+	_, err = s.client.GetScaleTeam(context.Background(), 1002)
+	s.NoError(err)
+}
+
+func (s *APISuite) Test05_Pagination() {
+	req := s.authenticated("/v2/users?page[size]=2&page[number]=2")
+	resp, err := http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+
+	var users []intra.User
+	s.Require().NoError(json.NewDecoder(resp.Body).Decode(&users))
+	s.Require().Len(users, 2)
+	s.Equal("xteammate", users[0].Login)
+	s.Equal("2", resp.Header.Get("X-Page"))
+	s.Equal("2", resp.Header.Get("X-Per-Page"))
+	s.Equal("5", resp.Header.Get("X-Total"))
+	s.Contains(resp.Header.Get("Link"), `page%5Bnumber%5D=3&page%5Bsize%5D=2>; rel="next"`)
+	s.Contains(resp.Header.Get("Link"), `page%5Bnumber%5D=1&page%5Bsize%5D=2>; rel="prev"`)
+}
+
+func (s *APISuite) Test06_Unauthorized() {
+	resp, err := http.Get(s.server.URL + "/v2/users/xcorrector")
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Equal(http.StatusUnauthorized, resp.StatusCode)
+
+	config.Conf.Intra.URL = s.server.URL
+	client, err := intra.NewClient("id", "wrong", s.server.Client())
+	s.Require().NoError(err)
+	_, err = client.GetUserEmail(context.Background(), "xcorrector")
+	s.Error(err)
+}
+
+func (s *APISuite) Test07_Throttle() {
+	s.api.Throttle(1)
+
+	email, err := s.client.GetUserEmail(context.Background(), "xcorrector")
+	s.Require().NoError(err)
+	s.Equal("xcorrector@student.42.fr", email)
+	s.Equal(2, s.api.Requests("/v2/users/:login"))
+
+	s.api.Throttle(2)
+	_, err = s.client.GetUserEmail(context.Background(), "xcorrector")
+	s.Require().Error(err)
+	s.IsType(&intra.HTTPError{}, err)
+	s.Equal(http.StatusTooManyRequests, err.(*intra.HTTPError).Response.StatusCode)
+}
+
+func (s *APISuite) Test08_RateLimit() {
+	s.server.Close()
+	s.server, s.api = NewServer(s.fixtures, RateLimitOption(1))
+
+	statuses := make([]int, 2)
+	for i := range statuses {
+		resp, err := http.DefaultClient.Do(s.authenticated("/v2/users/xcorrector"))
+		s.Require().NoError(err)
+		resp.Body.Close()
+		statuses[i] = resp.StatusCode
+	}
+	s.Equal([]int{http.StatusOK, http.StatusTooManyRequests}, statuses)
+	s.Equal(2, s.api.Requests("/v2/users/:login"))
+}
+
+func (s *APISuite) Test09_ShiftScaleTeams() {
+	fixtures, err := LoadFixtures("testdata/fixtures.json")
+	s.Require().NoError(err)
+
+	first := time.Date(2030, 1, 1, 10, 0, 0, 0, time.UTC)
+	s.Require().NoError(fixtures.ShiftScaleTeams(first))
+
+	var beginAts []time.Time
+	for _, scaleTeam := range fixtures.ScaleTeams {
+		var payload struct {
+			BeginAt time.Time `json:"begin_at"`
+		}
+		s.Require().NoError(json.Unmarshal(scaleTeam.Payload, &payload))
+		beginAts = append(beginAts, payload.BeginAt)
+	}
+	s.Equal([]time.Time{first, first.Add(time.Minute * 90), first.Add(time.Hour*19 + time.Minute*15)}, beginAts)
+}
+
+func (s *APISuite) Test10_Fail() {
+	s.api.Fail(1, http.StatusBadGateway)
+
+	resp, err := http.DefaultClient.Do(s.authenticated("/v2/users/xcorrector"))
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Equal(http.StatusBadGateway, resp.StatusCode)
+
+	_, err = s.client.GetUserEmail(context.Background(), "xcorrector")
+	s.NoError(err)
+}
+
+func (s *APISuite) Test11_SetTeam() {
+	s.api.SetTeam(Team{ID: 101, Logins: []string{"xteammate", "unknown"}})
+	s.api.SetTeam(Team{ID: 103, Logins: []string{"ycorrected"}})
+
+	logins, err := s.client.GetTeamMembers(context.Background(), 101)
+	s.Require().NoError(err)
+	s.Equal([]string{"xteammate"}, logins)
+
+	logins, err = s.client.GetTeamMembers(context.Background(), 103)
+	s.Require().NoError(err)
+	s.Equal([]string{"ycorrected"}, logins)
+}
+
+func (s *APISuite) Test12_MaxPageSize() {
+	s.server.Close()
+	s.server, s.api = NewServer(s.fixtures, MaxPageSizeOption(2))
+	config.Conf.Intra.URL = s.server.URL
+	client, err := intra.NewClient("id", "secret", s.server.Client())
+	s.Require().NoError(err)
+
+	emails, err := client.GetUserEmails(context.Background(), []string{"xcorrector", "xcorrected", "xteammate"})
+	s.Require().NoError(err)
+	s.Len(emails, 3)
+	s.Equal(2, s.api.Requests("/v2/users"))
+}
+
+// authenticated returns a request to the endpoint of the API with a valid token.
+func (s *APISuite) authenticated(endpoint string) *http.Request {
+	s.api.mu.Lock()
+	s.api.tokens["token"] = true
+	s.api.mu.Unlock()
+
+	req, err := http.NewRequest(http.MethodGet, s.server.URL+endpoint, nil)
+	s.Require().NoError(err)
+	req.Header.Set("Authorization", "Bearer token")
+	return req
+}
+
+func (s *APISuite) ids(scaleTeams []intra.ScaleTeam) []int {
+	ids := make([]int, len(scaleTeams))
+	for i, scaleTeam := range scaleTeams {
+		ids[i] = scaleTeam.ID
+	}
+	return ids
+}
@@ -0,0 +1,109 @@
+package intra
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// bucket is a token bucket holding up to `capacity` tokens, refilled at `rate` tokens per second.
+//
+// Its tokens may go negative, the deficit being the requests reserved ahead of the refill.
+type bucket struct {
+	capacity float64
+	rate     float64
+	tokens   float64
+	last     time.Time
+}
+
+func newBucket(limit int, period time.Duration, now time.Time) *bucket {
+	return &bucket{
+		capacity: float64(limit),
+		rate:     float64(limit) / period.Seconds(),
+		tokens:   float64(limit),
+		last:     now,
+	}
+}
+
+// reserve takes a token and returns how long to wait before it is available.
+func (b *bucket) reserve(now time.Time) time.Duration {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens += elapsed.Seconds() * b.rate
+		if b.tokens > b.capacity {
+			b.tokens = b.capacity
+		}
+		b.last = now
+	}
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// limiter holds the requests to the API under the application's rate limits, with a bucket per limit.
+type limiter struct {
+	mu      sync.Mutex
+	buckets []*bucket
+	now     func() time.Time
+}
+
+// newLimiter returns a limiter allowing `perSecond` requests per second and `perHour` requests per hour. A limit of 0
+// is not enforced.
+func newLimiter(perSecond, perHour int) *limiter {
+	l := &limiter{now: time.Now}
+	now := l.now()
+	if perSecond > 0 {
+		l.buckets = append(l.buckets, newBucket(perSecond, time.Second, now))
+	}
+	if perHour > 0 {
+		l.buckets = append(l.buckets, newBucket(perHour, time.Hour, now))
+	}
+	return l
+}
+
+// Wait blocks until a request is allowed and returns how long it waited.
+//
+// It fails right away with RateLimitDeadlineError if the request would not be allowed before the context's deadline,
+// and stops waiting when the context is done. In both cases, the reserved token is given back.
+func (l *limiter) Wait(ctx context.Context) (time.Duration, error) {
+	l.mu.Lock()
+	now := l.now()
+	var delay time.Duration
+	for _, b := range l.buckets {
+		if wait := b.reserve(now); wait > delay {
+			delay = wait
+		}
+	}
+	l.mu.Unlock()
+
+	if delay == 0 {
+		return 0, nil
+	}
+	if deadline, ok := ctx.Deadline(); ok && deadline.Before(now.Add(delay)) {
+		l.cancel()
+		return 0, RateLimitDeadlineError
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return delay, nil
+	case <-ctx.Done():
+		l.cancel()
+		return 0, ctx.Err()
+	}
+}
+
+// cancel gives back the token of a reservation which was not used.
+func (l *limiter) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, b := range l.buckets {
+		if b.tokens++; b.tokens > b.capacity {
+			b.tokens = b.capacity
+		}
+	}
+}
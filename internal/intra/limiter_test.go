@@ -0,0 +1,78 @@
+package intra
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBucket(t *testing.T) {
+	now := time.Date(2020, 7, 15, 21, 0, 0, 0, time.UTC)
+	b := newBucket(2, time.Second, now)
+
+	assert.Zero(t, b.reserve(now))
+	assert.Zero(t, b.reserve(now))
+	assert.Equal(t, time.Millisecond*500, b.reserve(now))
+	assert.Equal(t, time.Second, b.reserve(now))
+
+	// Two tokens were refilled, both already reserved.
+	assert.Equal(t, time.Millisecond*500, b.reserve(now.Add(time.Second)))
+
+	// The bucket never holds more than its capacity.
+	assert.Zero(t, b.reserve(now.Add(time.Hour)))
+	assert.Zero(t, b.reserve(now.Add(time.Hour)))
+	assert.Equal(t, time.Millisecond*500, b.reserve(now.Add(time.Hour)))
+}
+
+func TestLimiter(t *testing.T) {
+	t.Run("NoLimit", func(t *testing.T) {
+		l := newLimiter(0, 0)
+		for i := 0; i < 100; i++ {
+			waited, err := l.Wait(context.Background())
+			require.NoError(t, err)
+			assert.Zero(t, waited)
+		}
+	})
+
+	t.Run("Wait", func(t *testing.T) {
+		now := time.Now()
+		l := newLimiter(0, 3600*20)
+		l.now = func() time.Time { return now }
+		l.buckets[0].tokens = 0
+
+		waited, err := l.Wait(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, time.Millisecond*50, waited)
+	})
+
+	t.Run("DeadlineTooShort", func(t *testing.T) {
+		now := time.Now()
+		l := newLimiter(1, 0)
+		l.now = func() time.Time { return now }
+		_, err := l.Wait(context.Background())
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+		defer cancel()
+		_, err = l.Wait(ctx)
+		assert.Equal(t, RateLimitDeadlineError, err)
+		assert.Zero(t, l.buckets[0].tokens, "the token should be given back")
+	})
+
+	t.Run("ContextCanceled", func(t *testing.T) {
+		now := time.Now()
+		l := newLimiter(1, 0)
+		l.now = func() time.Time { return now }
+		_, err := l.Wait(context.Background())
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(time.Millisecond*10, cancel)
+		_, err = l.Wait(ctx)
+		assert.Equal(t, context.Canceled, err)
+		assert.Zero(t, l.buckets[0].tokens, "the token should be given back")
+	})
+}
@@ -0,0 +1,78 @@
+package intra
+
+import (
+	"context"
+	"time"
+
+	"github.com/gustavobelfort/42-jitsi/internal/db"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/mock"
+)
+
+type ClientMock struct {
+	mock.Mock
+}
+
+func (m *ClientMock) GetTeamMembers(ctx context.Context, teamID int) ([]string, error) {
+	toReturn := m.Called(ctx, teamID)
+	return toReturn.Get(0).([]string), toReturn.Error(1)
+}
+
+func (m *ClientMock) GetUserEmail(ctx context.Context, login string) (string, error) {
+	toReturn := m.Called(ctx, login)
+	return toReturn.String(0), toReturn.Error(1)
+}
+
+func (m *ClientMock) GetUserEmails(ctx context.Context, logins []string) (map[string]string, error) {
+	toReturn := m.Called(ctx, logins)
+	emails, _ := toReturn.Get(0).(map[string]string)
+	return emails, toReturn.Error(1)
+}
+
+func (m *ClientMock) ListScaleTeams(ctx context.Context, campusID int, from, to time.Time) ([]ScaleTeam, error) {
+	toReturn := m.Called(ctx, campusID, from, to)
+	return toReturn.Get(0).([]ScaleTeam), toReturn.Error(1)
+}
+
+func (m *ClientMock) GetScaleTeam(ctx context.Context, id int) (*ScaleTeam, error) {
+	toReturn := m.Called(ctx, id)
+	return toReturn.Get(0).(*ScaleTeam), toReturn.Error(1)
+}
+
+type ProfileManagerMock struct {
+	mock.Mock
+}
+
+func (m *ProfileManagerMock) DB() *gorm.DB {
+	return m.Called().Get(0).(*gorm.DB)
+}
+
+func (m *ProfileManagerMock) Store(tx *gorm.DB, login, email string, fetchedAt time.Time) error {
+	return m.Called(tx, login, email, fetchedAt).Error(0)
+}
+
+func (m *ProfileManagerMock) Invalidate(tx *gorm.DB, login string) (bool, error) {
+	toReturn := m.Called(tx, login)
+	return toReturn.Bool(0), toReturn.Error(1)
+}
+
+func (m *ProfileManagerMock) Get(tx *gorm.DB, options ...db.GetOption) ([]db.Profile, error) {
+	toReturn := m.Called(tx, options)
+	return toReturn.Get(0).([]db.Profile), toReturn.Error(1)
+}
+
+type ProfileMock struct {
+	mock.Mock
+}
+
+func (m *ProfileMock) GetLogin() string {
+	return m.Called().String(0)
+}
+
+func (m *ProfileMock) GetEmail() string {
+	return m.Called().String(0)
+}
+
+func (m *ProfileMock) GetFetchedAt() time.Time {
+	return m.Called().Get(0).(time.Time)
+}
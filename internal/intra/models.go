@@ -0,0 +1,226 @@
+package intra
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// The models of the API's payloads only carry the fields used by the project. They are validated once decoded, so
+// that a payload lacking one of them is reported instead of being used half empty.
+
+// model is a payload of the API which can be validated.
+type model interface {
+	validate() error
+}
+
+// User is a user of the intra.
+type User struct {
+	ID    int    `json:"id"`
+	Login string `json:"login"`
+	// Email is null for the users whose email the application is not allowed to see.
+	Email       string       `json:"email"`
+	Campus      []Campus     `json:"campus"`
+	CampusUsers []CampusUser `json:"campus_users"`
+}
+
+func (user *User) validate() error {
+	switch {
+	case user.Login == "":
+		return &InvalidModelError{Model: "user", ID: strconv.Itoa(user.ID), Field: "login"}
+	case user.ID == 0:
+		return &InvalidModelError{Model: "user", ID: user.Login, Field: "id"}
+	}
+	for i := range user.Campus {
+		if err := user.Campus[i].validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PrimaryCampus returns the campus the user belongs to, and false if they have none.
+func (user *User) PrimaryCampus() (Campus, bool) {
+	for _, campusUser := range user.CampusUsers {
+		if !campusUser.IsPrimary {
+			continue
+		}
+		for _, campus := range user.Campus {
+			if campus.ID == campusUser.CampusID {
+				return campus, true
+			}
+		}
+	}
+	if len(user.Campus) == 1 {
+		return user.Campus[0], true
+	}
+	return Campus{}, false
+}
+
+// CampusUser is the membership of a user to a campus.
+type CampusUser struct {
+	CampusID  int  `json:"campus_id"`
+	IsPrimary bool `json:"is_primary"`
+}
+
+// Campus is a campus of 42.
+type Campus struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	TimeZone string `json:"time_zone"`
+}
+
+func (campus *Campus) validate() error {
+	switch {
+	case campus.ID == 0:
+		return &InvalidModelError{Model: "campus", ID: campus.Name, Field: "id"}
+	case campus.TimeZone == "":
+		return &InvalidModelError{Model: "campus", ID: strconv.Itoa(campus.ID), Field: "time_zone"}
+	}
+	return nil
+}
+
+// Location returns the time zone of the campus.
+func (campus *Campus) Location() (*time.Location, error) {
+	return time.LoadLocation(campus.TimeZone)
+}
+
+// Team is a group of users registered to a project.
+type Team struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func (team *Team) validate() error {
+	if team.ID == 0 {
+		return &InvalidModelError{Model: "team", ID: team.Name, Field: "id"}
+	}
+	return nil
+}
+
+// UnmarshalJSON decodes the team leniently, even within a scale team, as only its id is used.
+func (team *Team) UnmarshalJSON(d []byte) error {
+	type teamTwin Team
+	return json.Unmarshal(d, (*teamTwin)(team))
+}
+
+// Corrector is the user evaluating a scale team. The API hides them behind the string "invisible" until the
+// evaluation is over, in which case Login is empty.
+type Corrector struct {
+	ID    int    `json:"id"`
+	Login string `json:"login"`
+}
+
+// UnmarshalJSON decodes the corrector, either a user or the string "invisible".
+func (corrector *Corrector) UnmarshalJSON(d []byte) error {
+	var hidden string
+	if json.Unmarshal(d, &hidden) == nil {
+		*corrector = Corrector{}
+		return nil
+	}
+	type correctorTwin Corrector
+	return json.Unmarshal(d, (*correctorTwin)(corrector))
+}
+
+// ScaleTeam is the evaluation of a team.
+type ScaleTeam struct {
+	ID        int       `json:"id"`
+	BeginAt   time.Time `json:"begin_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Corrector Corrector `json:"corrector"`
+	Team      Team      `json:"team"`
+}
+
+func (scaleTeam *ScaleTeam) validate() error {
+	switch {
+	case scaleTeam.ID == 0:
+		return &InvalidModelError{Model: "scale team", Field: "id"}
+	case scaleTeam.BeginAt.IsZero():
+		return &InvalidModelError{Model: "scale team", ID: strconv.Itoa(scaleTeam.ID), Field: "begin_at"}
+	case scaleTeam.UpdatedAt.IsZero():
+		return &InvalidModelError{Model: "scale team", ID: strconv.Itoa(scaleTeam.ID), Field: "updated_at"}
+	}
+	return scaleTeam.Team.validate()
+}
+
+// Location is the session of a user on a computer of a campus. EndAt is nil while the session is ongoing.
+type Location struct {
+	ID       int        `json:"id"`
+	BeginAt  time.Time  `json:"begin_at"`
+	EndAt    *time.Time `json:"end_at"`
+	Host     string     `json:"host"`
+	CampusID int        `json:"campus_id"`
+	User     User       `json:"user"`
+}
+
+func (location *Location) validate() error {
+	switch {
+	case location.ID == 0:
+		return &InvalidModelError{Model: "location", ID: location.Host, Field: "id"}
+	case location.BeginAt.IsZero():
+		return &InvalidModelError{Model: "location", ID: strconv.Itoa(location.ID), Field: "begin_at"}
+	}
+	return location.User.validate()
+}
+
+// scaleTeamFields are the fields of the scale teams' payloads known to the project, whether they are used or not.
+var scaleTeamFields = map[string]bool{
+	"id": true, "begin_at": true, "updated_at": true, "corrector": true, "team": true,
+	"scale_id": true, "comment": true, "created_at": true, "feedback": true, "final_mark": true, "flag": true,
+	"correcteds": true, "truant": true, "filled_at": true, "questions_with_answers": true, "scale": true,
+	"feedbacks": true,
+}
+
+// UnmarshalJSON decodes the scale team. A field unknown to the project is logged rather than rejected, so that a
+// field added to the API's schema does not stop the scale teams from being decoded.
+func (scaleTeam *ScaleTeam) UnmarshalJSON(d []byte) error {
+	type scaleTeamTwin ScaleTeam
+	if err := json.Unmarshal(d, (*scaleTeamTwin)(scaleTeam)); err != nil {
+		return err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(d, &fields); err != nil {
+		return err
+	}
+	var unknown []string
+	for field := range fields {
+		if !scaleTeamFields[field] {
+			unknown = append(unknown, field)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		logrus.WithFields(logrus.Fields{"scale_team_id": scaleTeam.ID, "fields": unknown}).
+			Warn("unknown fields in the intra scale team: the api's schema may have changed")
+	}
+	return nil
+}
+
+// decode decodes the payload of the endpoint into v, then validates it if it is a model or a slice of models.
+func decode(endpoint string, body io.Reader, v interface{}) error {
+	if err := json.NewDecoder(body).Decode(v); err != nil {
+		return &DecodeError{Endpoint: endpoint, Err: err}
+	}
+	if m, ok := v.(model); ok {
+		return m.validate()
+	}
+
+	value := reflect.ValueOf(v).Elem()
+	if value.Kind() != reflect.Slice {
+		return nil
+	}
+	for i := 0; i < value.Len(); i++ {
+		if m, ok := value.Index(i).Addr().Interface().(model); ok {
+			if err := m.validate(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package intra
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecode(t *testing.T) {
+	t.Run("User", func(t *testing.T) {
+		payload := `{
+	"id": 1,
+	"login": "xlogin",
+	"email": "xlogin@student.42campus.org",
+	"campus": [{"id": 21, "name": "Paris", "time_zone": "Europe/Paris"}, {"id": 22, "name": "Madrid", "time_zone": "Europe/Madrid"}],
+	"campus_users": [{"campus_id": 21, "is_primary": false}, {"campus_id": 22, "is_primary": true}]
+}`
+		var user User
+		require.NoError(t, decode("/v2/users/xlogin", strings.NewReader(payload), &user))
+		assert.Equal(t, "xlogin@student.42campus.org", user.Email)
+
+		campus, ok := user.PrimaryCampus()
+		require.True(t, ok)
+		assert.Equal(t, Campus{ID: 22, Name: "Madrid", TimeZone: "Europe/Madrid"}, campus)
+		location, err := campus.Location()
+		require.NoError(t, err)
+		assert.Equal(t, "Europe/Madrid", location.String())
+	})
+
+	t.Run("SingleCampus", func(t *testing.T) {
+		var user User
+		payload := `{"id": 1, "login": "xlogin", "campus": [{"id": 21, "name": "Paris", "time_zone": "Europe/Paris"}], "campus_users": []}`
+		require.NoError(t, decode("/v2/users/xlogin", strings.NewReader(payload), &user))
+		campus, ok := user.PrimaryCampus()
+		require.True(t, ok)
+		assert.Equal(t, 21, campus.ID)
+
+		_, ok = (&User{ID: 1, Login: "xlogin"}).PrimaryCampus()
+		assert.False(t, ok)
+	})
+
+	t.Run("InvalidCampus", func(t *testing.T) {
+		var user User
+		payload := `{"id": 1, "login": "xlogin", "campus": [{"id": 21, "name": "Paris"}]}`
+		err := decode("/v2/users/xlogin", strings.NewReader(payload), &user)
+		assert.Equal(t, &InvalidModelError{Model: "campus", ID: "21", Field: "time_zone"}, err)
+	})
+
+	t.Run("NullEmail", func(t *testing.T) {
+		var user User
+		require.NoError(t, decode("/v2/users/xlogin", strings.NewReader(`{"id": 1, "login": "xlogin", "email": null}`), &user))
+		assert.Empty(t, user.Email)
+	})
+
+	t.Run("MissingField", func(t *testing.T) {
+		var user User
+		err := decode("/v2/users/xlogin", strings.NewReader(`{"login": "xlogin"}`), &user)
+		assert.Equal(t, &InvalidModelError{Model: "user", ID: "xlogin", Field: "id"}, err)
+		assert.Equal(t, "invalid intra user 'xlogin': missing id", err.Error())
+
+		var null User
+		err = decode("/v2/users/xlogin", strings.NewReader(`null`), &null)
+		assert.Equal(t, "invalid intra user: missing login", err.Error())
+	})
+
+	t.Run("SchemaChanged", func(t *testing.T) {
+		var user User
+		err := decode("/v2/users/xlogin", strings.NewReader(`{"id": 1, "login": "xlogin", "email": {"address": "x"}}`), &user)
+		var decodeErr *DecodeError
+		require.True(t, errors.As(err, &decodeErr))
+		assert.Equal(t, "/v2/users/xlogin", decodeErr.Endpoint)
+		assert.Contains(t, err.Error(), "User.email")
+	})
+
+	t.Run("Page", func(t *testing.T) {
+		var users []User
+		err := decode("/v2/users", strings.NewReader(`[{"id": 1, "login": "xlogin"}, {"id": 2}]`), &users)
+		assert.Equal(t, &InvalidModelError{Model: "user", ID: "2", Field: "login"}, err)
+	})
+
+	t.Run("NestedModels", func(t *testing.T) {
+		var scaleTeam ScaleTeam
+		err := decode("/v2/scale_teams/21", strings.NewReader(`{"id": 21, "begin_at": "2020-07-15T21:00:00.000Z", "updated_at": "2020-07-14T10:00:00.000Z", "team": {"name": "team"}}`), &scaleTeam)
+		assert.Equal(t, &InvalidModelError{Model: "team", ID: "team", Field: "id"}, err)
+
+		var location Location
+		err = decode("/v2/locations/42", strings.NewReader(`{"id": 42, "begin_at": "2020-07-15T21:00:00.000Z", "end_at": null, "host": "e1r1p1", "campus_id": 21, "user": {"id": 1, "login": "xlogin"}}`), &location)
+		require.NoError(t, err)
+		assert.Nil(t, location.EndAt)
+		assert.Equal(t, 21, location.CampusID)
+		assert.Equal(t, "xlogin", location.User.Login)
+
+		var anonymous Location
+		err = decode("/v2/locations/42", strings.NewReader(`{"id": 42, "begin_at": "2020-07-15T21:00:00.000Z", "user": {"id": 1}}`), &anonymous)
+		assert.Equal(t, &InvalidModelError{Model: "user", ID: "1", Field: "login"}, err)
+	})
+
+	t.Run("ScaleTeam", func(t *testing.T) {
+		payload := `{
+	"id": 21,
+	"begin_at": "2020-07-15T21:00:00.000Z",
+	"updated_at": "2020-07-14T10:00:00.000Z",
+	"corrector": "invisible",
+	"team": {"id": 42, "name": "team", "users": [{"id": 1, "login": "xlogin", "leader": true}]},
+	"scale": {"id": 4, "name": "scale"}
+}`
+		var scaleTeam ScaleTeam
+		require.NoError(t, decode("/v2/scale_teams/21", strings.NewReader(payload), &scaleTeam))
+		assert.Equal(t, Corrector{}, scaleTeam.Corrector)
+		assert.Equal(t, 42, scaleTeam.Team.ID)
+	})
+
+	t.Run("ScaleTeamUnknownField", func(t *testing.T) {
+		hook := test.NewGlobal()
+		defer hook.Reset()
+
+		var scaleTeams []ScaleTeam
+		err := decode("/v2/scale_teams", strings.NewReader(`[{"id": 21, "begin_at": "2020-07-15T21:00:00.000Z", "updated_at": "2020-07-14T10:00:00.000Z", "team": {"id": 42}, "beginning": "2020-07-15T21:00:00.000Z"}]`), &scaleTeams)
+		require.NoError(t, err)
+		require.Len(t, scaleTeams, 1)
+		assert.Equal(t, 21, scaleTeams[0].ID)
+
+		require.NotNil(t, hook.LastEntry())
+		assert.Equal(t, logrus.WarnLevel, hook.LastEntry().Level)
+		assert.Equal(t, []string{"beginning"}, hook.LastEntry().Data["fields"])
+	})
+}
@@ -7,33 +7,56 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
 
-	"golang.org/x/oauth2"
+	"github.com/gustavobelfort/42-jitsi/internal/resilient"
 )
 
 // Client is a OAuth2 client made simplify authenticated requests to an OAuth2 API.
 type Client struct {
 	client *http.Client
-	ctx    context.Context
+	source *apiTokenSource
 
 	baseURL *url.URL
 }
 
 // NewClient returns a new OAuth2 client configured with the given base url, client id and client secret.
-func NewClient(baseURL, clientID, clientSecret string, httpClient *http.Client) (*Client, error) {
+//
+// The requests, including the token's, are sent with the given http client behind a circuit breaker. The requests are
+// retried on failures, except the token's which the token source retries itself.
+func NewClient(baseURL, clientID, clientSecret string, httpClient *http.Client, options ...TokenOption) (*Client, error) {
 	parsedURL, err := url.Parse(baseURL)
 	if err != nil {
 		return nil, err
 	}
 
-	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, httpClient)
+	source := newTokenSource(resilient.WrapClient(httpClient, resilient.RetriesOption(0)), parsedURL, clientID, clientSecret, options...)
 	return &Client{
-		client: oauth2.NewClient(ctx, newTokenSource(parsedURL, clientID, clientSecret)),
+		client: resilient.WrapClient(httpClient),
+		source: source,
 
 		baseURL: parsedURL,
 	}, nil
 }
 
+// TokenStats returns the state of the client's token, e.g: its age and how many times it could not be refreshed.
+func (c *Client) TokenStats() TokenStats {
+	return c.source.Stats()
+}
+
+// Endpoint returns the endpoint of a url of the API, e.g: a link of its responses, to be requested with the client.
+//
+// Only the path of the url is used, so that the requests are never sent to another host. It is stripped of the path of
+// the base url if it begins with it, as the base path is joined to the endpoints again.
+func (c *Client) Endpoint(link *url.URL) string {
+	base := strings.TrimSuffix(c.baseURL.EscapedPath(), "/")
+	endpoint := link.EscapedPath()
+	if base != "" && strings.HasPrefix(endpoint, base+"/") {
+		return strings.TrimPrefix(endpoint, base)
+	}
+	return endpoint
+}
+
 func (*Client) prepareBody(method string, data map[string]interface{}) (io.Reader, error) {
 	if method == http.MethodGet || data == nil {
 		return nil, nil
@@ -67,12 +90,17 @@ func (c *Client) prepareRequest(ctx context.Context, method, endpoint string, pa
 	return req.WithContext(ctx), nil
 }
 
-// Request makes a request to the API through the configured client.
+// Request makes a request to the API through the configured client, authenticated with the current token.
 func (c *Client) Request(ctx context.Context, method, endpoint string, params Params, data map[string]interface{}) (*http.Response, error) {
 	request, err := c.prepareRequest(ctx, method, endpoint, params, data)
 	if err != nil {
 		return nil, err
 	}
 
+	token, err := c.source.TokenContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	token.SetAuthHeader(request)
 	return c.client.Do(request)
 }
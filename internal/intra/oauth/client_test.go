@@ -3,7 +3,9 @@ package oauth
 import (
 	"context"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -103,6 +105,26 @@ func (s *ClientSuite) Test01_Post() {
 	s.client.Request(context.Background(), http.MethodPost, "/post_query_and_body", Params(expectedQuery), expectedBody)
 }
 
+func (s *ClientSuite) Test02_TokenStats() {
+	stats := s.client.TokenStats()
+	s.Equal(1, stats.Refreshes, "the token must be reused by the requests")
+	s.Zero(stats.RefreshFailures)
+	s.True(stats.Expiry.After(time.Now()))
+}
+
+func (s *ClientSuite) Test03_TokenRetriedOnce() {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	transport := &countingTransport{}
+	client, err := NewClient(server.URL, s.clientID, s.clientSecret, &http.Client{Transport: transport}, TokenRetriesOption(2, time.Millisecond))
+	s.Require().NoError(err)
+
+	_, err = client.Request(context.Background(), http.MethodGet, "/get", nil, nil)
+	s.Error(err)
+	s.Equal(int32(2), atomic.LoadInt32(&transport.count), "the token requests must only be retried by the token source")
+}
+
 func (s *ClientSuite) TearDownTest() {
 	s.mock.AssertExpectations(s.T())
 }
@@ -3,6 +3,7 @@ package oauth
 import (
 	"fmt"
 	"net/url"
+	"sort"
 	"strings"
 )
 
@@ -31,16 +32,26 @@ func (p Params) Set(key string, values ...interface{}) {
 	p.Add(key, values...)
 }
 
-// Encode encodes the parameters into a url query parameter format.
+// Encode encodes the parameters into a url query parameter format, sorted by key.
+//
+// The keys and values are escaped, while the values of a field are joined with ',' as expected by 42's API.
 func (p Params) Encode() string {
+	keys := make([]string, 0, len(p))
+	for key := range p {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
 	buffer := new(strings.Builder)
-	l := len(p)
-	for key, value := range p {
-		buffer.WriteString(fmt.Sprintf("%s=%s", key, strings.Join(value, ",")))
-		l--
-		if l > 0 {
+	for i, key := range keys {
+		if i > 0 {
 			buffer.WriteString("&")
 		}
+		values := make([]string, len(p[key]))
+		for j, value := range p[key] {
+			values[j] = url.QueryEscape(value)
+		}
+		buffer.WriteString(fmt.Sprintf("%s=%s", url.QueryEscape(key), strings.Join(values, ",")))
 	}
 	return buffer.String()
 }
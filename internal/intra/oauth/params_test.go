@@ -48,4 +48,10 @@ func TestParams(t *testing.T) {
 		assert.Equal(t, expected, params)
 	})
 
+	t.Run("Encode", func(t *testing.T) {
+		params := Params{"range[begin_at]": {"2020-07-15T21:00:00Z", "2020-07-16T21:00:00Z"}, "filter[login]": {"x&y=z"}}
+		expected := "filter%5Blogin%5D=x%26y%3Dz&range%5Bbegin_at%5D=2020-07-15T21%3A00%3A00Z,2020-07-16T21%3A00%3A00Z"
+		assert.Equal(t, expected, params.Encode())
+	})
+
 }
@@ -2,16 +2,30 @@ package oauth
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"net/url"
 	"sync"
 	"time"
 
+	"github.com/sirupsen/logrus"
 	"golang.org/x/oauth2"
 )
 
+// Default settings of the token sources.
+const (
+	// DefaultRefreshSkew is how long before its expiry a token is refreshed.
+	DefaultRefreshSkew = time.Minute * 5
+	// DefaultTokenAttempts is the number of times a token is requested before giving up.
+	DefaultTokenAttempts = 3
+	// DefaultTokenBackoff is the wait before the second attempt to request a token, doubled at each attempt.
+	DefaultTokenBackoff = time.Millisecond * 500
+)
+
 type apiToken struct {
 	AccessToken string `json:"access_token"`
 	TokenType   string `json:"token_type"`
@@ -28,25 +42,92 @@ func (t *apiToken) token() *oauth2.Token {
 	}
 }
 
+// TokenStats describes the token of a Client and how its refreshes went.
+type TokenStats struct {
+	// IssuedAt and Expiry are the times the current token was created and expires at, zero if there is none yet.
+	IssuedAt time.Time
+	Expiry   time.Time
+	// Refreshes is the number of tokens fetched, RefreshFailures the number of fetches which failed after every
+	// attempt and LastError the error of the latest one.
+	Refreshes       int
+	RefreshFailures int
+	LastError       error
+}
+
+// Age returns how long ago the current token was issued, 0 if there is none.
+func (stats TokenStats) Age(now time.Time) time.Duration {
+	if stats.IssuedAt.IsZero() {
+		return 0
+	}
+	return now.Sub(stats.IssuedAt)
+}
+
+// TokenOption configures the token source of a Client.
+type TokenOption func(*apiTokenSource)
+
+// TokenRefreshSkewOption sets how long before its expiry a token is refreshed.
+func TokenRefreshSkewOption(skew time.Duration) TokenOption {
+	return func(ts *apiTokenSource) {
+		ts.refreshSkew = skew
+	}
+}
+
+// TokenRetriesOption sets the number of times a token is requested before giving up, and the wait before the second
+// attempt, doubled at each attempt.
+func TokenRetriesOption(attempts int, backoff time.Duration) TokenOption {
+	return func(ts *apiTokenSource) {
+		ts.attempts, ts.backoff = attempts, backoff
+	}
+}
+
+// apiTokenSource requests the tokens of the client credentials flow with the client's own http client.
+//
+// A token is refreshed `refreshSkew` before it expires. If the refresh fails, the current token is used until it
+// actually expires, so that the requests are not rejected while the API is briefly unavailable. The token is requested
+// without holding the lock, and the callers needing one while it is requested wait for the same request.
 type apiTokenSource struct {
+	client *http.Client
+
 	clientID     string
 	clientSecret string
 
 	tokenEndpoint string
-	token         *oauth2.Token
+	refreshSkew   time.Duration
+	attempts      int
+	backoff       time.Duration
+
+	token      *oauth2.Token
+	stats      TokenStats
+	refreshing *refresh
 
 	m *sync.Mutex
 }
 
-func newTokenSource(baseURL *url.URL, clientID, clientSecret string) oauth2.TokenSource {
-	return &apiTokenSource{
+// refresh is a token request, whose outcome is shared by the callers waiting for it.
+type refresh struct {
+	done  chan struct{}
+	token *oauth2.Token
+	err   error
+}
+
+func newTokenSource(client *http.Client, baseURL *url.URL, clientID, clientSecret string, options ...TokenOption) *apiTokenSource {
+	ts := &apiTokenSource{
+		client: client,
+
 		clientID:     clientID,
 		clientSecret: clientSecret,
 
 		tokenEndpoint: joinURL(baseURL, "/oauth/token").String(),
+		refreshSkew:   DefaultRefreshSkew,
+		attempts:      DefaultTokenAttempts,
+		backoff:       DefaultTokenBackoff,
 
 		m: new(sync.Mutex),
 	}
+	for _, option := range options {
+		option(ts)
+	}
+	return ts
 }
 
 func (ts *apiTokenSource) prepareRequestBody() (io.Reader, error) {
@@ -58,56 +139,173 @@ func (ts *apiTokenSource) prepareRequestBody() (io.Reader, error) {
 	return buffer, nil
 }
 
-func (ts *apiTokenSource) requestToken() (*http.Response, error) {
+func (ts *apiTokenSource) requestToken(ctx context.Context) (*http.Response, error) {
 	reader, err := ts.prepareRequestBody()
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := http.Post(ts.tokenEndpoint, "application/json", reader)
+	req, err := http.NewRequest(http.MethodPost, ts.tokenEndpoint, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := ts.client.Do(req.WithContext(ctx))
 	if err != nil {
 		return nil, err
 	}
 
 	if 200 > resp.StatusCode || resp.StatusCode > 299 {
+		body, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
 		return nil, &oauth2.RetrieveError{
 			Response: resp,
+			Body:     body,
 		}
 	}
 
 	return resp, nil
 }
 
-func (ts *apiTokenSource) treatResponseBody(body io.ReadCloser) error {
+func (ts *apiTokenSource) treatResponseBody(body io.ReadCloser) (*apiToken, error) {
 	t := new(apiToken)
 	defer body.Close()
 	decoder := json.NewDecoder(body)
 	if err := decoder.Decode(t); err != nil {
-		return err
+		return nil, err
 	}
-	ts.token = t.token()
-	return nil
+	return t, nil
+}
+
+// fetch requests a new token, again with an exponential backoff while the failure is not the credentials' fault, unless
+// the context is done before.
+func (ts *apiTokenSource) fetch(ctx context.Context) (*apiToken, error) {
+	backoff := ts.backoff
+	for attempt := 1; ; attempt++ {
+		resp, err := ts.requestToken(ctx)
+		if err == nil {
+			return ts.treatResponseBody(resp.Body)
+		}
+		if attempt >= ts.attempts || !isTemporary(err) || ctx.Err() != nil {
+			return nil, err
+		}
+
+		logrus.WithError(err).WithField("attempt", attempt).Warnf("could not request a token: retrying in %v", backoff)
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+		backoff *= 2
+	}
+}
+
+// isTemporary reports whether the token request failed for a reason which may go away by itself.
+func isTemporary(err error) bool {
+	retrieveErr, ok := err.(*oauth2.RetrieveError)
+	if !ok {
+		return true
+	}
+	status := retrieveErr.Response.StatusCode
+	return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
 }
 
-// Token returns the current token if it is valid.
+// Token returns the current token, unless it expires in less than `refreshSkew`.
 //
 // Otherwise it requests a new token.
 func (ts *apiTokenSource) Token() (*oauth2.Token, error) {
+	return ts.TokenContext(context.Background())
+}
+
+// TokenContext is Token, giving up once the context is done.
+func (ts *apiTokenSource) TokenContext(ctx context.Context) (*oauth2.Token, error) {
+	for {
+		ts.m.Lock()
+		if ts.token != nil && time.Until(ts.token.Expiry) > ts.skew() {
+			token := ts.token
+			ts.m.Unlock()
+			return token, nil
+		}
+		r := ts.refreshing
+		if r == nil {
+			r = &refresh{done: make(chan struct{})}
+			ts.refreshing = r
+			ts.m.Unlock()
+
+			ts.refresh(ctx, r)
+			return r.token, r.err
+		}
+		ts.m.Unlock()
+
+		select {
+		case <-r.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		// The caller who requested the token gave up on it, which is not a reason for this one to.
+		if r.err != nil && isDone(r.err) {
+			continue
+		}
+		return r.token, r.err
+	}
+}
+
+// refresh requests a new token and stores it, then shares the outcome with the callers waiting for it.
+func (ts *apiTokenSource) refresh(ctx context.Context, r *refresh) {
+	t, err := ts.fetch(ctx)
+
 	ts.m.Lock()
 	defer ts.m.Unlock()
-	if ts.token.Valid() {
-		return ts.token, nil
-	}
+	defer close(r.done)
+	ts.refreshing = nil
 
-	resp, err := ts.requestToken()
 	if err != nil {
-		return nil, err
+		ts.stats.RefreshFailures++
+		ts.stats.LastError = err
+
+		logger := logrus.WithError(err).WithFields(logrus.Fields{
+			"token_age":        ts.stats.Age(time.Now()).String(),
+			"refresh_failures": ts.stats.RefreshFailures,
+		})
+		if ts.token.Valid() {
+			logger.Warnf("could not refresh the token, using it until it expires at %s: %v", ts.token.Expiry, err)
+			r.token = ts.token
+			return
+		}
+		logger.Errorf("could not request a token: %v", err)
+		r.err = err
+		return
 	}
 
-	if err := ts.treatResponseBody(resp.Body); err != nil {
-		return nil, err
+	ts.token = t.token()
+	ts.stats.IssuedAt = time.Unix(t.CreatedAt, 0)
+	ts.stats.Expiry = ts.token.Expiry
+	ts.stats.Refreshes++
+	logrus.WithField("expiry", ts.token.Expiry).Debug("requested a new token")
+	r.token = ts.token
+}
+
+// isDone reports whether the error is the one of a done context.
+func isDone(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// skew returns how long before its expiry the current token is refreshed: `refreshSkew`, unless it is more than half
+// of the token's lifetime.
+func (ts *apiTokenSource) skew() time.Duration {
+	if half := ts.token.Expiry.Sub(ts.stats.IssuedAt) / 2; ts.refreshSkew > half {
+		return half
 	}
-	return ts.token, nil
+	return ts.refreshSkew
+}
+
+// Stats returns the state of the token source.
+func (ts *apiTokenSource) Stats() TokenStats {
+	ts.m.Lock()
+	defer ts.m.Unlock()
+	return ts.stats
 }
 
 // MarshalJSON returns a valid JSON corresponding to the body used to request a token.
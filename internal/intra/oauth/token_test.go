@@ -0,0 +1,215 @@
+package oauth
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/oauth2"
+)
+
+func TestTokenSource(t *testing.T) {
+	suite.Run(t, new(TokenSourceSuite))
+}
+
+type TokenSourceSuite struct {
+	suite.Suite
+
+	mock   *ServerMock
+	source *apiTokenSource
+}
+
+// countingTransport counts the requests sent through it.
+type countingTransport struct {
+	count int32
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	atomic.AddInt32(&t.count, 1)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// blockingTransport holds the requests sent through it until it is released.
+type blockingTransport struct {
+	countingTransport
+	release chan struct{}
+}
+
+func (t *blockingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	<-t.release
+	return t.countingTransport.RoundTrip(req)
+}
+
+func (s *TokenSourceSuite) SetupSuite() {
+	s.mock = NewServerMock()
+}
+
+func (s *TokenSourceSuite) SetupTest() {
+	s.mock.ExpectedCalls = []*mock.Call{}
+	s.mock.Calls = []mock.Call{}
+
+	baseURL, err := url.Parse(s.mock.Server.URL)
+	s.Require().NoError(err)
+	s.source = newTokenSource(
+		s.mock.Server.Client(),
+		baseURL,
+		"id",
+		"secret",
+		TokenRefreshSkewOption(time.Minute*5),
+		TokenRetriesOption(2, time.Millisecond),
+	)
+}
+
+// expectToken sets the expectation of a token request answered with `status`, and a token created `age` ago and
+// valid for `lifetime` if it succeeds.
+func (s *TokenSourceSuite) expectToken(status int, token string, age, lifetime time.Duration) *mock.Call {
+	response := gin.H{}
+	if status == http.StatusOK {
+		response = gin.H{
+			"access_token": token,
+			"token_type":   "Bearer",
+			"created_at":   time.Now().Add(-age).Unix(),
+			"expires_in":   int(lifetime.Seconds()),
+		}
+	}
+	return s.mock.On("/oauth/token", http.MethodPost, mock.Anything, mock.Anything, "").Return(status, response, gin.H{})
+}
+
+func (s *TokenSourceSuite) Test00_InjectedClient() {
+	transport := &countingTransport{}
+	s.source.client = &http.Client{Transport: transport}
+	s.expectToken(http.StatusOK, "token", 0, time.Hour*2).Once()
+
+	token, err := s.source.Token()
+	s.Require().NoError(err)
+	s.Equal("token", token.AccessToken)
+	s.Equal(int32(1), atomic.LoadInt32(&transport.count), "the token must be requested with the injected client")
+}
+
+func (s *TokenSourceSuite) Test01_ReusedUntilSkew() {
+	s.expectToken(http.StatusOK, "token", 0, time.Hour*2).Once()
+
+	first, err := s.source.Token()
+	s.Require().NoError(err)
+	second, err := s.source.Token()
+	s.Require().NoError(err)
+	s.Equal(first, second)
+	s.Equal(1, s.source.Stats().Refreshes)
+}
+
+func (s *TokenSourceSuite) Test02_RefreshedAheadOfExpiry() {
+	s.expectToken(http.StatusOK, "old", time.Minute*116, time.Hour*2).Once()
+	s.expectToken(http.StatusOK, "new", 0, time.Hour*2).Once()
+
+	token, err := s.source.Token()
+	s.Require().NoError(err)
+	s.Equal("old", token.AccessToken)
+	s.True(token.Valid())
+
+	token, err = s.source.Token()
+	s.Require().NoError(err)
+	s.Equal("new", token.AccessToken, "a token expiring within the skew must be refreshed")
+
+	stats := s.source.Stats()
+	s.Equal(2, stats.Refreshes)
+	s.True(stats.Age(time.Now()) < time.Minute)
+}
+
+func (s *TokenSourceSuite) Test03_ShortLivedToken() {
+	s.expectToken(http.StatusOK, "token", 0, time.Minute*4).Once()
+
+	_, err := s.source.Token()
+	s.Require().NoError(err)
+	_, err = s.source.Token()
+	s.Require().NoError(err, "a token is kept for half its lifetime when it is shorter than twice the skew")
+}
+
+func (s *TokenSourceSuite) Test04_RefreshFailure() {
+	s.expectToken(http.StatusOK, "old", time.Minute*116, time.Hour*2).Once()
+	s.expectToken(http.StatusServiceUnavailable, "", 0, 0).Twice()
+
+	_, err := s.source.Token()
+	s.Require().NoError(err)
+
+	token, err := s.source.Token()
+	s.Require().NoError(err, "the current token must be used until it expires")
+	s.Equal("old", token.AccessToken)
+
+	stats := s.source.Stats()
+	s.Equal(1, stats.RefreshFailures)
+	s.IsType(&oauth2.RetrieveError{}, stats.LastError)
+}
+
+func (s *TokenSourceSuite) Test05_Retried() {
+	s.expectToken(http.StatusBadGateway, "", 0, 0).Once()
+	s.expectToken(http.StatusOK, "token", 0, time.Hour*2).Once()
+
+	token, err := s.source.Token()
+	s.Require().NoError(err)
+	s.Equal("token", token.AccessToken)
+}
+
+func (s *TokenSourceSuite) Test06_InvalidCredentials() {
+	s.expectToken(http.StatusUnauthorized, "", 0, 0).Once()
+
+	token, err := s.source.Token()
+	s.Require().Error(err)
+	s.Nil(token)
+	s.Equal(1, s.source.Stats().RefreshFailures)
+}
+
+func (s *TokenSourceSuite) Test07_BackoffCancelled() {
+	s.source.backoff = time.Hour
+	s.expectToken(http.StatusServiceUnavailable, "", 0, 0).Once()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+	token, err := s.source.TokenContext(ctx)
+	s.Equal(context.DeadlineExceeded, err, "the backoff must not outlive the context")
+	s.Nil(token)
+}
+
+func (s *TokenSourceSuite) Test08_SharedRequest() {
+	transport := &blockingTransport{release: make(chan struct{})}
+	s.source.client = &http.Client{Transport: transport}
+	s.expectToken(http.StatusOK, "token", 0, time.Hour*2).Once()
+
+	tokens := make(chan string, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			token, err := s.source.Token()
+			s.NoError(err)
+			tokens <- token.AccessToken
+		}()
+	}
+	s.Eventually(func() bool {
+		s.source.m.Lock()
+		defer s.source.m.Unlock()
+		return s.source.refreshing != nil
+	}, time.Second, time.Millisecond)
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := s.source.TokenContext(cancelled)
+	s.Equal(context.Canceled, err, "a caller waiting for the token must be able to give up")
+	s.Zero(s.source.Stats().Refreshes, "the lock must not be held while the token is requested")
+
+	close(transport.release)
+	s.Equal("token", <-tokens)
+	s.Equal("token", <-tokens)
+	s.Equal(int32(1), atomic.LoadInt32(&transport.count), "the callers must share the same request")
+}
+
+func (s *TokenSourceSuite) TearDownTest() {
+	s.mock.AssertExpectations(s.T())
+}
+
+func (s *TokenSourceSuite) TearDownSuite() {
+	s.mock.Server.Close()
+}
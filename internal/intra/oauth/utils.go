@@ -5,11 +5,17 @@ import (
 	"path"
 )
 
+// joinURL returns the url with the elements, already escaped, joined to its path.
 func joinURL(src *url.URL, elem ...string) *url.URL {
 	newURL := new(url.URL)
 	*newURL = *src
 
-	newURL.Path = path.Join(newURL.Path, path.Join(elem...))
+	escaped := path.Join(newURL.EscapedPath(), path.Join(elem...))
+	if unescaped, err := url.PathUnescape(escaped); err == nil {
+		newURL.Path, newURL.RawPath = unescaped, escaped
+	} else {
+		newURL.Path, newURL.RawPath = escaped, ""
+	}
 
 	return newURL
 }
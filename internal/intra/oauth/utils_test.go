@@ -0,0 +1,41 @@
+package oauth
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJoinURL(t *testing.T) {
+	base, err := url.Parse("https://api.intra.42.fr/")
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://api.intra.42.fr/v2/users/xlogin", joinURL(base, "/v2/users/xlogin").String())
+	assert.Equal(t, "https://api.intra.42.fr/v2/users/x%2Flogin%3F", joinURL(base, "/v2/users/"+url.PathEscape("x/login?")).String())
+	assert.Equal(t, "https://api.intra.42.fr/oauth/token", joinURL(base, "/oauth/token").String())
+
+	prefixed, err := url.Parse("http://localhost:4242/intra%20api/")
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:4242/intra%20api/v2/users/xlogin", joinURL(prefixed, "/v2/users/xlogin").String())
+}
+
+func TestClient_Endpoint(t *testing.T) {
+	client, err := NewClient("http://localhost:4242/intra/", "id", "secret", nil)
+	require.NoError(t, err)
+
+	tests := map[string]string{
+		"http://localhost:4242/intra/v2/users?page[number]=2": "/v2/users",
+		"http://api.example.com/intra/v2/users":               "/v2/users",
+		"/intra/v2/teams/42/users":                            "/v2/teams/42/users",
+		// The link of an upstream unaware of the base path.
+		"http://localhost:4242/v2/users":       "/v2/users",
+		"http://localhost:4242/intranet/users": "/intranet/users",
+	}
+	for link, expected := range tests {
+		parsed, err := url.Parse(link)
+		require.NoError(t, err)
+		assert.Equal(t, expected, client.Endpoint(parsed), link)
+	}
+}
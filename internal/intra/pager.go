@@ -0,0 +1,104 @@
+package intra
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+
+	"github.com/gustavobelfort/42-jitsi/internal/intra/oauth"
+)
+
+// nextLinkRegexp matches the link to the next page in a `Link` header.
+var nextLinkRegexp = regexp.MustCompile(`<([^>]*)>\s*;\s*rel="next"`)
+
+// pager walks through the pages of a list endpoint of the API.
+//
+// It follows the `Link` headers of the responses. Without them, it relies on their `X-Total` header, or stops at the
+// first page which is not full.
+type pager struct {
+	client   *intraClient
+	endpoint string
+	params   oauth.Params
+
+	size   int
+	number int
+	done   bool
+	err    error
+}
+
+// paginate returns a pager requesting the query on the endpoint, with `pageSize` items per page unless set.
+func (c *intraClient) paginate(endpoint string, query *Query) *pager {
+	params, err := query.Params()
+	if err == nil && len(params["page[size]"]) == 0 {
+		params.Set("page[size]", pageSize)
+	}
+
+	p := &pager{client: c, endpoint: endpoint, params: params, number: 1, err: err}
+	if err == nil {
+		p.size, p.err = strconv.Atoi(url.Values(params).Get("page[size]"))
+		p.params.Set("page[number]", p.number)
+	}
+	return p
+}
+
+// Next requests the next page and decodes it into `page`, a pointer to a slice, and reports whether there was one.
+// It stops at the first error, which is then returned by Err.
+func (p *pager) Next(ctx context.Context, page interface{}) bool {
+	if p.done || p.err != nil {
+		return false
+	}
+	if p.err = ctx.Err(); p.err != nil {
+		return false
+	}
+
+	resp, err := p.client.do(ctx, http.MethodGet, p.endpoint, p.params, nil)
+	if err != nil {
+		p.err = err
+		return false
+	}
+	defer resp.Body.Close()
+	if p.err = decode(p.endpoint, resp.Body, page); p.err != nil {
+		return false
+	}
+
+	p.advance(resp.Header, reflect.ValueOf(page).Elem().Len())
+	return true
+}
+
+// Err returns the error which stopped the pager, if any.
+func (p *pager) Err() error {
+	return p.err
+}
+
+// advance prepares the request of the page following the one just received, or marks the pager as done.
+func (p *pager) advance(header http.Header, length int) {
+	if link := header.Get("Link"); link != "" {
+		match := nextLinkRegexp.FindStringSubmatch(link)
+		if match == nil {
+			p.done = true
+			return
+		}
+		next, err := url.Parse(match[1])
+		if err != nil {
+			p.err = err
+			return
+		}
+		p.endpoint, p.params = p.client.oauthClient.Endpoint(next), oauth.Params(next.Query())
+		return
+	}
+
+	if total, err := strconv.Atoi(header.Get("X-Total")); err == nil {
+		perPage, err := strconv.Atoi(header.Get("X-Per-Page"))
+		if err != nil {
+			perPage = p.size
+		}
+		p.done = length == 0 || p.number*perPage >= total
+	} else {
+		p.done = length < p.size
+	}
+	p.number++
+	p.params.Set("page[number]", p.number)
+}
@@ -0,0 +1,94 @@
+package intra
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gustavobelfort/42-jitsi/internal/intra/oauth"
+)
+
+// fieldRegexp matches the names of the fields which can be filtered, ranged or sorted by.
+var fieldRegexp = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// Query builds the parameters of the list endpoints of 42's API, e.g:
+//
+//	NewQuery().Filter("campus_id", 21).Range("begin_at", from, to).Sort("begin_at").PageSize(100)
+//
+// The first invalid field name is reported by Params.
+type Query struct {
+	params oauth.Params
+	err    error
+}
+
+// NewQuery returns an empty query.
+func NewQuery() *Query {
+	return &Query{params: oauth.Params{}}
+}
+
+// Filter keeps the items whose field is one of the values.
+func (q *Query) Filter(field string, values ...interface{}) *Query {
+	return q.set("filter", field, values...)
+}
+
+// Range keeps the items whose field is between min and max.
+func (q *Query) Range(field string, min, max interface{}) *Query {
+	return q.set("range", field, min, max)
+}
+
+// Sort sorts the items by the fields, in descending order for the fields prefixed with '-'.
+func (q *Query) Sort(fields ...string) *Query {
+	for _, field := range fields {
+		q.validate(strings.TrimPrefix(field, "-"))
+	}
+	q.params.Set("sort", strings.Join(fields, ","))
+	return q
+}
+
+// PageSize sets the number of items per page.
+func (q *Query) PageSize(size int) *Query {
+	q.params.Set("page[size]", size)
+	return q
+}
+
+// Page sets the number of the requested page, starting at 1.
+func (q *Query) Page(number int) *Query {
+	q.params.Set("page[number]", number)
+	return q
+}
+
+// Params returns a copy of the parameters of the query, or the error of its first invalid field.
+func (q *Query) Params() (oauth.Params, error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	params := make(oauth.Params, len(q.params))
+	for key, values := range q.params {
+		params[key] = append([]string(nil), values...)
+	}
+	return params, nil
+}
+
+func (q *Query) set(kind, field string, values ...interface{}) *Query {
+	q.validate(field)
+	formatted := make([]interface{}, len(values))
+	for i, value := range values {
+		formatted[i] = formatValue(value)
+	}
+	q.params.Set(kind+"["+field+"]", formatted...)
+	return q
+}
+
+func (q *Query) validate(field string) {
+	if q.err == nil && !fieldRegexp.MatchString(field) {
+		q.err = &InvalidFieldError{field: field}
+	}
+}
+
+// formatValue formats the times the way the API parses them, the other values are formatted by oauth.Params.
+func formatValue(value interface{}) interface{} {
+	if t, ok := value.(time.Time); ok {
+		return t.UTC().Format(rangeTimeLayout)
+	}
+	return value
+}
@@ -0,0 +1,48 @@
+package intra
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gustavobelfort/42-jitsi/internal/intra/oauth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuery(t *testing.T) {
+	t.Run("Params", func(t *testing.T) {
+		from := time.Date(2020, 7, 15, 23, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+		params, err := NewQuery().
+			Filter("campus_id", 21, 42).
+			Range("begin_at", from, from.Add(time.Hour)).
+			Sort("-begin_at", "id").
+			PageSize(50).
+			Page(2).
+			Params()
+		require.NoError(t, err)
+		assert.Equal(t, oauth.Params{
+			"filter[campus_id]": {"21", "42"},
+			"range[begin_at]":   {"2020-07-15T21:00:00Z", "2020-07-15T22:00:00Z"},
+			"sort":              {"-begin_at,id"},
+			"page[size]":        {"50"},
+			"page[number]":      {"2"},
+		}, params)
+	})
+
+	t.Run("ParamsAreCopied", func(t *testing.T) {
+		query := NewQuery().Page(1)
+		params, err := query.Params()
+		require.NoError(t, err)
+		params.Set("page[number]", 2)
+
+		params, err = query.Params()
+		require.NoError(t, err)
+		assert.Equal(t, oauth.Params{"page[number]": {"1"}}, params)
+	})
+
+	t.Run("InvalidField", func(t *testing.T) {
+		_, err := NewQuery().Filter("login", "xlogin").Sort("begin_at&admin=true").Params()
+		require.Error(t, err)
+		assert.IsType(t, &InvalidFieldError{}, err)
+	})
+}
@@ -38,9 +38,15 @@ func (m *ServerMock) initRouter() {
 		ctx.JSON(toReturn.Int(0), toReturn.Get(1))
 	})
 
+	// Mocking users index requests
+	m.router.GET("/v2/users", func(ctx *gin.Context) {
+		toReturn := m.MethodCalled("GetUsers", ctx.Query("filter[login]"), ctx.Query("page[number]"))
+		ctx.JSON(toReturn.Int(0), toReturn.Get(1))
+	})
+
 	// Mocking team show users index request
 	m.router.GET("/v2/teams/:id/users", func(ctx *gin.Context) {
-		toReturn := m.MethodCalled("GetTeamUsers", ctx.Param("id"))
+		toReturn := m.MethodCalled("GetTeamUsers", ctx.Param("id"), ctx.Query("page[number]"))
 		for key, value := range toReturn.Get(2).(gin.H) {
 			ctx.Header(key, value.(string))
 		}
@@ -0,0 +1,25 @@
+package jitsi
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// InvalidTokenError is returned when a token is not a well formed HS256 JWT.
+	InvalidTokenError = errors.New("the token is not a valid HS256 JWT")
+	// InvalidSignatureError is returned when a token was not signed with the expected secret.
+	InvalidSignatureError = errors.New("the token's signature is invalid")
+	// ExpiredTokenError is returned when a token is expired.
+	ExpiredTokenError = errors.New("the token is expired")
+)
+
+// InvalidOptionError is returned when a configured jitsi option is not of the form "key=value".
+type InvalidOptionError struct {
+	option string
+}
+
+// Error formats the InvalidOptionError with the faulty option.
+func (err *InvalidOptionError) Error() string {
+	return fmt.Sprintf("invalid jitsi option '%s': expected format 'key=value'", err.option)
+}
@@ -0,0 +1,57 @@
+package jitsi
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/gustavobelfort/42-jitsi/internal/config"
+)
+
+// URL returns the link to the given room on the configured jitsi server, with the configured options.
+func URL(roomName string) (string, error) {
+	serverURL, err := url.Parse(config.Conf.Jitsi.URL)
+	if err != nil {
+		return "", err
+	}
+	serverURL.Path = path.Join("/", serverURL.Path, roomName)
+
+	fragment, err := encodeOptions(config.Conf.Jitsi.Options)
+	if err != nil {
+		return "", err
+	}
+	link := serverURL.String()
+	if fragment != "" {
+		link = fmt.Sprintf("%s#%s", link, fragment)
+	}
+	return link, nil
+}
+
+// WithToken returns the given room link authenticated with the given token.
+func WithToken(link, token string) (string, error) {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return "", err
+	}
+	parsed.RawQuery = url.Values{"jwt": {token}}.Encode()
+	return parsed.String(), nil
+}
+
+// encodeOptions formats the "key=value" options into the url fragment expected by jitsi.
+//
+// The values are escaped the way jitsi decodes them (with `decodeURIComponent`), thus spaces are not turned into '+'.
+func encodeOptions(options []string) (string, error) {
+	encoded := make([]string, 0, len(options))
+	for _, option := range options {
+		kv := strings.SplitN(option, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return "", &InvalidOptionError{option: option}
+		}
+		value := strings.Replace(url.QueryEscape(kv[1]), "+", "%20", -1)
+		encoded = append(encoded, fmt.Sprintf("%s=%s", kv[0], value))
+	}
+	sort.Strings(encoded)
+	return strings.Join(encoded, "&"), nil
+}
@@ -0,0 +1,50 @@
+package jitsi
+
+import (
+	"testing"
+
+	"github.com/gustavobelfort/42-jitsi/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setConfig(url string, options ...string) {
+	config.Conf.Jitsi = config.Jitsi{
+		URL:     url,
+		Options: options,
+	}
+}
+
+func TestURL(t *testing.T) {
+	t.Run("NoOptions", func(t *testing.T) {
+		setConfig("https://meet.jit.si")
+
+		link, err := URL("21-xlogin")
+		require.NoError(t, err)
+		assert.Equal(t, "https://meet.jit.si/21-xlogin", link)
+	})
+
+	t.Run("ServerWithPath", func(t *testing.T) {
+		setConfig("https://jitsi.42campus.org/rooms/")
+
+		link, err := URL("21-xlogin")
+		require.NoError(t, err)
+		assert.Equal(t, "https://jitsi.42campus.org/rooms/21-xlogin", link)
+	})
+
+	t.Run("WithOptions", func(t *testing.T) {
+		setConfig("https://meet.jit.si", `config.subject="42 Evaluation"`, "config.prejoinPageEnabled=false")
+
+		link, err := URL("21-xlogin")
+		require.NoError(t, err)
+		assert.Equal(t, "https://meet.jit.si/21-xlogin#config.prejoinPageEnabled=false&config.subject=%2242%20Evaluation%22", link)
+	})
+
+	t.Run("InvalidOption", func(t *testing.T) {
+		setConfig("https://meet.jit.si", "config.prejoinPageEnabled")
+
+		_, err := URL("21-xlogin")
+		require.Error(t, err)
+		assert.IsType(t, &InvalidOptionError{}, err)
+	})
+}
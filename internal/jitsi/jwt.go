@@ -0,0 +1,113 @@
+package jitsi
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// Claims are the claims of the tokens expected by jitsi's prosody `token` authentication.
+//
+// See: https://github.com/jitsi/lib-jitsi-meet/blob/master/doc/tokens.md
+type Claims struct {
+	Audience  string `json:"aud"`
+	Issuer    string `json:"iss"`
+	Subject   string `json:"sub"`
+	Room      string `json:"room"`
+	ExpiresAt int64  `json:"exp"`
+	Moderator bool   `json:"moderator"`
+
+	Context ClaimsContext `json:"context"`
+}
+
+// ClaimsContext is the context of the user a token was signed for.
+type ClaimsContext struct {
+	User ClaimsUser `json:"user"`
+}
+
+// ClaimsUser describes the user a token was signed for.
+type ClaimsUser struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Email     string `json:"email,omitempty"`
+	Moderator bool   `json:"moderator"`
+}
+
+type tokenHeader struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ"`
+}
+
+var encoding = base64.RawURLEncoding
+
+func signature(unsigned, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unsigned))
+	return encoding.EncodeToString(mac.Sum(nil))
+}
+
+func encodeSegment(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return encoding.EncodeToString(data), nil
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := encoding.DecodeString(segment)
+	if err != nil {
+		return InvalidTokenError
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return InvalidTokenError
+	}
+	return nil
+}
+
+// Sign returns the HS256 signed JWT of the given claims.
+func Sign(claims *Claims, secret string) (string, error) {
+	header, err := encodeSegment(&tokenHeader{Algorithm: "HS256", Type: "JWT"})
+	if err != nil {
+		return "", err
+	}
+	payload, err := encodeSegment(claims)
+	if err != nil {
+		return "", err
+	}
+	unsigned := header + "." + payload
+	return unsigned + "." + signature(unsigned, secret), nil
+}
+
+// Verify checks the signature and the expiration of an HS256 JWT and returns its claims.
+func Verify(token, secret string) (*Claims, error) {
+	segments := strings.Split(token, ".")
+	if len(segments) != 3 {
+		return nil, InvalidTokenError
+	}
+
+	header := &tokenHeader{}
+	if err := decodeSegment(segments[0], header); err != nil {
+		return nil, err
+	}
+	if header.Algorithm != "HS256" {
+		return nil, InvalidTokenError
+	}
+
+	expected := signature(segments[0]+"."+segments[1], secret)
+	if !hmac.Equal([]byte(expected), []byte(segments[2])) {
+		return nil, InvalidSignatureError
+	}
+
+	claims := &Claims{}
+	if err := decodeSegment(segments[1], claims); err != nil {
+		return nil, err
+	}
+	if time.Now().Unix() >= claims.ExpiresAt {
+		return nil, ExpiredTokenError
+	}
+	return claims, nil
+}
@@ -0,0 +1,70 @@
+package jitsi
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignVerify(t *testing.T) {
+	expected := &Claims{
+		Audience:  "jitsi",
+		Issuer:    "app_id",
+		Subject:   "*",
+		Room:      "room",
+		ExpiresAt: time.Now().Add(time.Hour).Unix(),
+		Moderator: true,
+		Context: ClaimsContext{
+			User: ClaimsUser{ID: "xlogin", Name: "xlogin", Moderator: true},
+		},
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		token, err := Sign(expected, "secret")
+		require.NoError(t, err)
+		assert.Len(t, strings.Split(token, "."), 3)
+
+		claims, err := Verify(token, "secret")
+		require.NoError(t, err)
+		assert.Equal(t, expected, claims)
+	})
+
+	t.Run("BadSecret", func(t *testing.T) {
+		token, err := Sign(expected, "secret")
+		require.NoError(t, err)
+
+		_, err = Verify(token, "bad_secret")
+		assert.Equal(t, InvalidSignatureError, err)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		expired := *expected
+		expired.ExpiresAt = time.Now().Add(-time.Minute).Unix()
+		token, err := Sign(&expired, "secret")
+		require.NoError(t, err)
+
+		_, err = Verify(token, "secret")
+		assert.Equal(t, ExpiredTokenError, err)
+	})
+
+	t.Run("Malformed", func(t *testing.T) {
+		_, err := Verify("not.a-token", "secret")
+		assert.Equal(t, InvalidTokenError, err)
+
+		_, err = Verify("not.a.token", "secret")
+		assert.Equal(t, InvalidTokenError, err)
+	})
+
+	t.Run("BadAlgorithm", func(t *testing.T) {
+		header, err := encodeSegment(&tokenHeader{Algorithm: "none", Type: "JWT"})
+		require.NoError(t, err)
+		payload, err := encodeSegment(expected)
+		require.NoError(t, err)
+
+		_, err = Verify(header+"."+payload+".", "secret")
+		assert.Equal(t, InvalidTokenError, err)
+	})
+}
@@ -0,0 +1,27 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+)
+
+// UnknownNotifierError is returned when the configured notifier is not one of the registered backends.
+type UnknownNotifierError struct {
+	Notifier string
+	Backends []string
+}
+
+// Error formats the UnknownNotifierError with the configured notifier and the registered backends.
+func (err *UnknownNotifierError) Error() string {
+	return fmt.Sprintf("unknown notifier '%s': expected one of '%s'", err.Notifier, strings.Join(err.Backends, "', '"))
+}
+
+// UnsupportedKindError is returned when a backend is asked to send a kind of message it does not support.
+type UnsupportedKindError struct {
+	Kind Kind
+}
+
+// Error formats the UnsupportedKindError with the kind of the message.
+func (err *UnsupportedKindError) Error() string {
+	return fmt.Sprintf("unsupported kind of message '%s'", err.Kind)
+}
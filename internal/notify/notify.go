@@ -0,0 +1,61 @@
+package notify
+
+import (
+	"time"
+)
+
+// Kind is what a message tells about an evaluation.
+type Kind string
+
+const (
+	// Reminder sends the link to the meeting of an upcoming evaluation to its participants.
+	Reminder Kind = "reminder"
+	// Cancel tells the participants of an evaluation that it was cancelled.
+	Cancel Kind = "cancel"
+	// Reschedule tells the participants of an evaluation that it was moved.
+	Reschedule Kind = "reschedule"
+	// NoShow reports to the staff the participants who never joined the meeting of an evaluation.
+	NoShow Kind = "no_show"
+)
+
+// Role is the role of a participant in an evaluation.
+type Role string
+
+const (
+	// Corrector is the participant evaluating the team, who moderates the meeting.
+	Corrector Role = "corrector"
+	// Corrected is a member of the evaluated team.
+	Corrected Role = "corrected"
+)
+
+// Participant is a participant of an evaluation, with their role in it.
+type Participant struct {
+	Login string
+	Role  Role
+}
+
+// Meeting is the meeting of an evaluation, whose link is sent to its participants.
+type Meeting struct {
+	Provider string
+	RoomName string
+	URL      string
+}
+
+// Message is a message about an evaluation.
+type Message struct {
+	Kind        Kind
+	ScaleTeamID int
+	// BeginAt is the beginning of the evaluation, the new one for a reschedule.
+	BeginAt time.Time
+	// PreviousBeginAt is the beginning the evaluation was moved from, only set for a reschedule.
+	PreviousBeginAt time.Time
+	// Meeting is the meeting whose link is sent by a reminder.
+	Meeting Meeting
+	// Participants are the recipients of the message, or the absentees for a no-show report.
+	Participants []Participant
+}
+
+// Notifier sends the messages about the evaluations through a backend, e.g: slack_that.
+type Notifier interface {
+	Notify(message Message) error
+}
@@ -0,0 +1,63 @@
+package notify
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/gustavobelfort/42-jitsi/internal/config"
+	"github.com/gustavobelfort/42-jitsi/internal/intra"
+)
+
+// Factory initiates the notifier of a backend from the configuration, with the client looking the participants up.
+type Factory func(intra intra.Client) (Notifier, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Factory)
+)
+
+// Register makes a backend available by its name. It is meant to be called by the init function of the backend's
+// package, and panics if the name is already registered.
+func Register(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if factory == nil {
+		panic("notify: Register factory is nil")
+	}
+	if _, registered := registry[name]; registered {
+		panic(fmt.Sprintf("notify: Register called twice for backend '%s'", name))
+	}
+	registry[name] = factory
+}
+
+// Backends returns the sorted names of the registered backends.
+func Backends() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New initiates the notifier of the backend registered with the name.
+func New(name string, intra intra.Client) (Notifier, error) {
+	registryMu.RLock()
+	factory, registered := registry[name]
+	registryMu.RUnlock()
+
+	if !registered {
+		return nil, &UnknownNotifierError{Notifier: name, Backends: Backends()}
+	}
+	return factory(intra)
+}
+
+// NewFromConfig initiates the notifier of the configured backend.
+func NewFromConfig(intra intra.Client) (Notifier, error) {
+	return New(config.Conf.Notifier, intra)
+}
@@ -0,0 +1,65 @@
+package notify
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gustavobelfort/42-jitsi/internal/config"
+	"github.com/gustavobelfort/42-jitsi/internal/intra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type notifierMock struct {
+	intra intra.Client
+}
+
+func (m *notifierMock) Notify(message Message) error {
+	return nil
+}
+
+func TestRegistry(t *testing.T) {
+	Register("mock", func(intra intra.Client) (Notifier, error) {
+		return &notifierMock{intra: intra}, nil
+	})
+	Register("broken", func(intra intra.Client) (Notifier, error) {
+		return nil, errors.New("testing")
+	})
+	defer func() {
+		delete(registry, "mock")
+		delete(registry, "broken")
+		config.Conf.Notifier = ""
+	}()
+
+	t.Run("Backends", func(t *testing.T) {
+		assert.Equal(t, []string{"broken", "mock"}, Backends())
+	})
+
+	t.Run("New", func(t *testing.T) {
+		notifier, err := New("mock", nil)
+		require.NoError(t, err)
+		assert.IsType(t, &notifierMock{}, notifier)
+
+		_, err = New("broken", nil)
+		assert.EqualError(t, err, "testing")
+	})
+
+	t.Run("NewFromConfig", func(t *testing.T) {
+		config.Conf.Notifier = "mock"
+		notifier, err := NewFromConfig(nil)
+		require.NoError(t, err)
+		assert.IsType(t, &notifierMock{}, notifier)
+	})
+
+	t.Run("UnknownNotifier", func(t *testing.T) {
+		_, err := New("email", nil)
+		assert.Equal(t, &UnknownNotifierError{Notifier: "email", Backends: []string{"broken", "mock"}}, err)
+		assert.EqualError(t, err, "unknown notifier 'email': expected one of 'broken', 'mock'")
+	})
+
+	t.Run("RegisterTwice", func(t *testing.T) {
+		assert.Panics(t, func() {
+			Register("mock", func(intra intra.Client) (Notifier, error) { return nil, nil })
+		})
+	})
+}
@@ -0,0 +1,113 @@
+package reminder
+
+import (
+	"sort"
+	"time"
+
+	"github.com/gustavobelfort/42-jitsi/internal/config"
+	"github.com/gustavobelfort/42-jitsi/internal/db"
+	"github.com/jinzhu/gorm"
+	"github.com/sirupsen/logrus"
+)
+
+// Plan creates the reminders of a scale team beginning at `beginAt`, one for each configured offset.
+//
+// The reminders that would already be due are merged into the latest of them, so that the participants of an
+// evaluation planned at the last minute are not notified several times at once.
+func Plan(tx *gorm.DB, manager db.ReminderManager, scaleTeamID int, beginAt time.Time) error {
+	now := time.Now()
+	offsets := offsets()
+	for i, remindBefore := range offsets {
+		if i+1 < len(offsets) && !beginAt.Add(-offsets[i+1]).After(now) {
+			// A later reminder is already due as well.
+			continue
+		}
+		if _, err := manager.Create(tx, scaleTeamID, remindBefore, beginAt.Add(-remindBefore)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Reset deletes the reminders of a scale team, sent or not, and plans them again for its new `beginAt`.
+func Reset(tx *gorm.DB, manager db.ReminderManager, scaleTeamID int, beginAt time.Time) error {
+	reminders, err := manager.Get(tx, db.ReminderScaleTeamOption(scaleTeamID))
+	if err != nil {
+		return err
+	}
+	for _, reminder := range reminders {
+		if err := reminder.Delete(tx); err != nil {
+			return err
+		}
+	}
+	return Plan(tx, manager, scaleTeamID, beginAt)
+}
+
+// Notified reports whether the participants of a scale team were notified, i.e: if any of its reminders was sent.
+func Notified(tx *gorm.DB, manager db.ReminderManager, scaleTeamID int) (bool, error) {
+	sent, err := manager.Get(tx, db.ReminderScaleTeamOption(scaleTeamID), db.ReminderSentOption(true))
+	if err != nil {
+		return false, err
+	}
+	return len(sent) > 0, nil
+}
+
+// ScaleTeamsBackfill is the name of the backfill of MigrateScaleTeams.
+const ScaleTeamsBackfill = "reminders"
+
+// MigrateScaleTeams plans the reminders of the upcoming scale teams that were created before the reminders were stored
+// in the database. It is run by the migrate command.
+func MigrateScaleTeams(tx *gorm.DB, scaleTeamManager db.ScaleTeamManager, reminderManager db.ReminderManager) error {
+	scaleTeams, err := scaleTeamManager.Get(tx, pendingOptions()...)
+	if err != nil {
+		return err
+	}
+	if len(scaleTeams) == 0 {
+		return nil
+	}
+
+	logrus.Infof("planning the reminders of %d scale teams", len(scaleTeams))
+	for _, scaleTeam := range scaleTeams {
+		if err := Plan(tx, reminderManager, scaleTeam.GetID(), scaleTeam.GetBeginAt()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckScaleTeams returns a *db.PendingBackfillError if some scale teams still miss the reminders that
+// MigrateScaleTeams plans.
+func CheckScaleTeams(tx *gorm.DB, scaleTeamManager db.ScaleTeamManager) error {
+	scaleTeams, err := scaleTeamManager.Get(tx, pendingOptions()...)
+	if err != nil {
+		return err
+	}
+	if len(scaleTeams) > 0 {
+		return &db.PendingBackfillError{Backfill: ScaleTeamsBackfill, Count: len(scaleTeams)}
+	}
+	return nil
+}
+
+// pendingOptions selects the scale teams whose reminders MigrateScaleTeams plans. Those which already began would only
+// be reminded late.
+func pendingOptions() []db.GetOption {
+	return []db.GetOption{
+		db.ScaleTeamRemindersMissingOption(),
+		db.ScaleTeamBeginAtAfterOption(time.Now()),
+	}
+}
+
+// offsets returns the configured offsets without duplicates, from the earliest reminder to the latest.
+func offsets() []time.Duration {
+	seen := make(map[time.Duration]bool, len(config.Conf.Reminders))
+	offsets := make([]time.Duration, 0, len(config.Conf.Reminders))
+	for _, offset := range config.Conf.Reminders {
+		if seen[offset] {
+			continue
+		}
+		seen[offset] = true
+		offsets = append(offsets, offset)
+	}
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] > offsets[j] })
+	return offsets
+}
@@ -0,0 +1,237 @@
+package reminder
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gustavobelfort/42-jitsi/internal/config"
+	"github.com/gustavobelfort/42-jitsi/internal/db"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type ReminderManagerMock struct {
+	mock.Mock
+}
+
+func (m *ReminderManagerMock) DB() *gorm.DB {
+	return m.Called().Get(0).(*gorm.DB)
+}
+
+func (m *ReminderManagerMock) Create(tx *gorm.DB, scaleTeamID int, remindBefore time.Duration, remindAt time.Time) (db.Reminder, error) {
+	toReturn := m.Called(tx, scaleTeamID, remindBefore, remindAt)
+	return toReturn.Get(0).(db.Reminder), toReturn.Error(1)
+}
+
+func (m *ReminderManagerMock) Update(tx *gorm.DB, reminder db.Reminder) error {
+	return m.Called(tx, reminder).Error(0)
+}
+
+func (m *ReminderManagerMock) SetSent(tx *gorm.DB, id int, sentAt time.Time) error {
+	return m.Called(tx, id, sentAt).Error(0)
+}
+
+func (m *ReminderManagerMock) Delete(tx *gorm.DB, reminder db.Reminder) error {
+	return m.Called(tx, reminder).Error(0)
+}
+
+func (m *ReminderManagerMock) Get(tx *gorm.DB, options ...db.GetOption) ([]db.Reminder, error) {
+	toReturn := m.Called(tx, options)
+	return toReturn.Get(0).([]db.Reminder), toReturn.Error(1)
+}
+
+type ReminderMock struct {
+	db.Reminder
+	mock.Mock
+}
+
+func (m *ReminderMock) Delete(tx *gorm.DB) error {
+	return m.Called(tx).Error(0)
+}
+
+type ScaleTeamManagerMock struct {
+	db.ScaleTeamManager
+	mock.Mock
+}
+
+func (m *ScaleTeamManagerMock) Get(tx *gorm.DB, options ...db.GetOption) ([]db.ScaleTeam, error) {
+	toReturn := m.Called(tx, options)
+	return toReturn.Get(0).([]db.ScaleTeam), toReturn.Error(1)
+}
+
+type ScaleTeamMock struct {
+	db.ScaleTeam
+	mock.Mock
+}
+
+func (m *ScaleTeamMock) GetID() int {
+	return m.Called().Int(0)
+}
+
+func (m *ScaleTeamMock) GetBeginAt() time.Time {
+	return m.Called().Get(0).(time.Time)
+}
+
+func TestPlan(t *testing.T) {
+	config.Conf.Reminders = []time.Duration{0, time.Hour, time.Minute * 15, time.Hour}
+
+	t.Run("Upcoming", func(t *testing.T) {
+		manager := &ReminderManagerMock{}
+		beginAt := time.Now().Add(time.Hour * 2)
+
+		manager.On("Create", mock.Anything, 1, time.Hour, beginAt.Add(-time.Hour)).Return(&ReminderMock{}, nil).Once()
+		manager.On("Create", mock.Anything, 1, time.Minute*15, beginAt.Add(-time.Minute*15)).Return(&ReminderMock{}, nil).Once()
+		manager.On("Create", mock.Anything, 1, time.Duration(0), beginAt).Return(&ReminderMock{}, nil).Once()
+
+		require.NoError(t, Plan(nil, manager, 1, beginAt))
+		manager.AssertExpectations(t)
+	})
+
+	t.Run("LastMinute", func(t *testing.T) {
+		manager := &ReminderManagerMock{}
+		beginAt := time.Now().Add(time.Minute * 10)
+
+		manager.On("Create", mock.Anything, 1, time.Minute*15, beginAt.Add(-time.Minute*15)).Return(&ReminderMock{}, nil).Once()
+		manager.On("Create", mock.Anything, 1, time.Duration(0), beginAt).Return(&ReminderMock{}, nil).Once()
+
+		require.NoError(t, Plan(nil, manager, 1, beginAt))
+		manager.AssertExpectations(t)
+	})
+
+	t.Run("Past", func(t *testing.T) {
+		manager := &ReminderManagerMock{}
+		beginAt := time.Now().Add(-time.Minute)
+
+		manager.On("Create", mock.Anything, 1, time.Duration(0), beginAt).Return(&ReminderMock{}, nil).Once()
+
+		require.NoError(t, Plan(nil, manager, 1, beginAt))
+		manager.AssertExpectations(t)
+	})
+
+	t.Run("CreateError", func(t *testing.T) {
+		manager := &ReminderManagerMock{}
+		expectedError := errors.New("testing")
+
+		manager.On("Create", mock.Anything, 1, time.Hour, mock.Anything).Return(&ReminderMock{}, expectedError).Once()
+
+		assert.Equal(t, expectedError, Plan(nil, manager, 1, time.Now().Add(time.Hour*2)))
+		manager.AssertExpectations(t)
+	})
+}
+
+func TestReset(t *testing.T) {
+	config.Conf.Reminders = []time.Duration{time.Minute * 15}
+
+	t.Run("Valid", func(t *testing.T) {
+		manager := &ReminderManagerMock{}
+		sent := &ReminderMock{}
+		beginAt := time.Now().Add(time.Hour)
+
+		manager.On("Get", mock.Anything, mock.Anything).Return([]db.Reminder{sent}, nil).Once()
+		sent.On("Delete", mock.Anything).Return(nil).Once()
+		manager.On("Create", mock.Anything, 1, time.Minute*15, beginAt.Add(-time.Minute*15)).Return(&ReminderMock{}, nil).Once()
+
+		require.NoError(t, Reset(nil, manager, 1, beginAt))
+		manager.AssertExpectations(t)
+		sent.AssertExpectations(t)
+	})
+
+	t.Run("DeleteError", func(t *testing.T) {
+		manager := &ReminderManagerMock{}
+		sent := &ReminderMock{}
+		expectedError := errors.New("testing")
+
+		manager.On("Get", mock.Anything, mock.Anything).Return([]db.Reminder{sent}, nil).Once()
+		sent.On("Delete", mock.Anything).Return(expectedError).Once()
+
+		assert.Equal(t, expectedError, Reset(nil, manager, 1, time.Now().Add(time.Hour)))
+		manager.AssertExpectations(t)
+		sent.AssertExpectations(t)
+	})
+}
+
+func TestNotified(t *testing.T) {
+	t.Run("Sent", func(t *testing.T) {
+		manager := &ReminderManagerMock{}
+		manager.On("Get", mock.Anything, mock.Anything).Return([]db.Reminder{&ReminderMock{}}, nil).Once()
+
+		notified, err := Notified(nil, manager, 1)
+		require.NoError(t, err)
+		assert.True(t, notified)
+		manager.AssertExpectations(t)
+	})
+
+	t.Run("NotSent", func(t *testing.T) {
+		manager := &ReminderManagerMock{}
+		manager.On("Get", mock.Anything, mock.Anything).Return([]db.Reminder{}, nil).Once()
+
+		notified, err := Notified(nil, manager, 1)
+		require.NoError(t, err)
+		assert.False(t, notified)
+		manager.AssertExpectations(t)
+	})
+
+	t.Run("GetError", func(t *testing.T) {
+		manager := &ReminderManagerMock{}
+		expectedError := errors.New("testing")
+		manager.On("Get", mock.Anything, mock.Anything).Return([]db.Reminder{}, expectedError).Once()
+
+		_, err := Notified(nil, manager, 1)
+		assert.Equal(t, expectedError, err)
+		manager.AssertExpectations(t)
+	})
+}
+
+func TestMigrateScaleTeams(t *testing.T) {
+	config.Conf.Reminders = []time.Duration{time.Minute * 15}
+
+	t.Run("Valid", func(t *testing.T) {
+		stManager := &ScaleTeamManagerMock{}
+		rManager := &ReminderManagerMock{}
+		scaleTeam := &ScaleTeamMock{}
+		beginAt := time.Now().Add(time.Hour)
+
+		stManager.On("Get", mock.Anything, mock.Anything).Return([]db.ScaleTeam{scaleTeam}, nil).Once()
+		scaleTeam.On("GetID").Return(1).Once()
+		scaleTeam.On("GetBeginAt").Return(beginAt).Once()
+		rManager.On("Create", mock.Anything, 1, time.Minute*15, beginAt.Add(-time.Minute*15)).Return(&ReminderMock{}, nil).Once()
+
+		require.NoError(t, MigrateScaleTeams(nil, stManager, rManager))
+		stManager.AssertExpectations(t)
+		rManager.AssertExpectations(t)
+		scaleTeam.AssertExpectations(t)
+	})
+
+	t.Run("GetError", func(t *testing.T) {
+		stManager := &ScaleTeamManagerMock{}
+		expectedError := errors.New("testing")
+
+		stManager.On("Get", mock.Anything, mock.Anything).Return([]db.ScaleTeam{}, expectedError).Once()
+
+		assert.Equal(t, expectedError, MigrateScaleTeams(nil, stManager, &ReminderManagerMock{}))
+		stManager.AssertExpectations(t)
+	})
+}
+
+func TestCheckScaleTeams(t *testing.T) {
+	t.Run("Backfilled", func(t *testing.T) {
+		stManager := &ScaleTeamManagerMock{}
+		stManager.On("Get", mock.Anything, mock.Anything).Return([]db.ScaleTeam{}, nil).Once()
+
+		assert.NoError(t, CheckScaleTeams(nil, stManager))
+		stManager.AssertExpectations(t)
+	})
+
+	t.Run("Pending", func(t *testing.T) {
+		stManager := &ScaleTeamManagerMock{}
+		stManager.On("Get", mock.Anything, mock.Anything).Return([]db.ScaleTeam{&ScaleTeamMock{}}, nil).Once()
+
+		err := CheckScaleTeams(nil, stManager)
+		assert.Equal(t, &db.PendingBackfillError{Backfill: ScaleTeamsBackfill, Count: 1}, err)
+		assert.EqualError(t, err, "1 scale teams are pending the reminders backfill: run the migrate command")
+		stManager.AssertExpectations(t)
+	})
+}
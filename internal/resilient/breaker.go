@@ -0,0 +1,75 @@
+package resilient
+
+import (
+	"sync"
+	"time"
+)
+
+type breakerState int
+
+const (
+	closedState breakerState = iota
+	openState
+	halfOpenState
+)
+
+// breaker is the circuit breaker of an upstream.
+//
+// It opens after `threshold` requests failed in a row, and rejects the requests until `cooldown` elapsed. A single
+// trial request is then let through: the circuit closes if it succeeds, and opens again otherwise.
+type breaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+
+	state    breakerState
+	failures int
+	openedAt time.Time
+}
+
+// allow reports whether a request may be sent to the upstream.
+func (b *breaker) allow(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case openState:
+		if now.Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = halfOpenState
+		return true
+	case halfOpenState:
+		return false
+	default:
+		return true
+	}
+}
+
+// record updates the circuit with the outcome of a request, and reports whether it just opened.
+func (b *breaker) record(success bool, now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if success {
+		b.state, b.failures = closedState, 0
+		return false
+	}
+	b.failures++
+	if b.state == halfOpenState || (b.state == closedState && b.failures >= b.threshold) {
+		b.state, b.openedAt = openState, now
+		return true
+	}
+	return false
+}
+
+// abandon ends a request whose outcome is unknown, e.g: it was cancelled. It tells nothing about the upstream, but a
+// half-open circuit opens again with a fresh cooldown instead of waiting forever for the outcome of its trial.
+func (b *breaker) abandon(now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == halfOpenState {
+		b.state, b.openedAt = openState, now
+	}
+}
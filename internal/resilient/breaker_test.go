@@ -0,0 +1,62 @@
+package resilient
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBreaker(t *testing.T) {
+	now := time.Now()
+
+	t.Run("OpensAfterThreshold", func(t *testing.T) {
+		b := &breaker{threshold: 2, cooldown: time.Minute}
+
+		assert.False(t, b.record(false, now))
+		assert.True(t, b.allow(now))
+		assert.True(t, b.record(false, now))
+		assert.False(t, b.allow(now.Add(time.Second)))
+	})
+
+	t.Run("SuccessResetsFailures", func(t *testing.T) {
+		b := &breaker{threshold: 2, cooldown: time.Minute}
+
+		b.record(false, now)
+		b.record(true, now)
+		assert.False(t, b.record(false, now))
+		assert.True(t, b.allow(now))
+	})
+
+	t.Run("HalfOpen", func(t *testing.T) {
+		b := &breaker{threshold: 1, cooldown: time.Minute}
+		b.record(false, now)
+
+		later := now.Add(time.Minute)
+		assert.True(t, b.allow(later), "a trial request is let through after the cooldown")
+		assert.False(t, b.allow(later), "a single trial request is let through")
+
+		assert.True(t, b.record(false, later))
+		assert.False(t, b.allow(later.Add(time.Second)))
+
+		later = later.Add(time.Minute)
+		assert.True(t, b.allow(later))
+		b.record(true, later)
+		assert.True(t, b.allow(later))
+		assert.True(t, b.allow(later))
+	})
+	t.Run("Abandon", func(t *testing.T) {
+		b := &breaker{threshold: 1, cooldown: time.Minute}
+		b.record(false, now)
+
+		later := now.Add(time.Minute)
+		assert.True(t, b.allow(later))
+		b.abandon(later)
+		assert.False(t, b.allow(later.Add(time.Second)), "the circuit opens again with a fresh cooldown")
+		assert.True(t, b.allow(later.Add(time.Minute)), "another trial request is let through after it")
+
+		closed := &breaker{threshold: 1, cooldown: time.Minute}
+		closed.abandon(now)
+		assert.True(t, closed.allow(now), "an abandoned request is not a failure")
+	})
+}
@@ -0,0 +1,34 @@
+package resilient
+
+import (
+	"errors"
+	"fmt"
+)
+
+// UnavailableError is returned when an upstream could not be reached after every retry, or when its circuit is open.
+type UnavailableError struct {
+	Upstream string
+	// Attempts is the number of times the request was sent, 0 if the circuit was open.
+	Attempts int
+	Err      error
+}
+
+// Error formats the UnavailableError with the upstream and why it is considered unavailable.
+func (err *UnavailableError) Error() string {
+	if err.Attempts == 0 {
+		return fmt.Sprintf("upstream %s is unavailable: its circuit is open after repeated failures", err.Upstream)
+	}
+	return fmt.Sprintf("upstream %s is unavailable after %d attempts: %v", err.Upstream, err.Attempts, err.Err)
+}
+
+// Unwrap returns the error of the last attempt.
+func (err *UnavailableError) Unwrap() error {
+	return err.Err
+}
+
+// IsUnavailable reports whether the error comes from an unavailable upstream, in which case the operation is worth
+// trying again later.
+func IsUnavailable(err error) bool {
+	var unavailableErr *UnavailableError
+	return errors.As(err, &unavailableErr)
+}
@@ -0,0 +1,212 @@
+package resilient
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gustavobelfort/42-jitsi/internal/logging"
+	"github.com/sirupsen/logrus"
+)
+
+// Default settings of the transports.
+const (
+	DefaultMaxRetries       = 3
+	DefaultMinBackoff       = time.Millisecond * 100
+	DefaultMaxBackoff       = time.Second * 2
+	DefaultFailureThreshold = 5
+	DefaultCooldown         = time.Second * 30
+)
+
+// Transport is an http.RoundTripper retrying the failed requests with an exponential backoff, behind a circuit
+// breaker per upstream host.
+//
+// A request fails on a network error or a 5xx response. It is retried if it is idempotent, like net/http defines it,
+// or if it could not even be sent. The requests which fail after every retry, or are rejected by an open circuit,
+// return an UnavailableError.
+type Transport struct {
+	base       http.RoundTripper
+	maxRetries int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	threshold  int
+	cooldown   time.Duration
+
+	mu       sync.Mutex
+	breakers map[string]*breaker
+}
+
+// Option configures a Transport.
+type Option func(*Transport)
+
+// RetriesOption sets the number of times a failed request is retried.
+func RetriesOption(maxRetries int) Option {
+	return func(t *Transport) {
+		t.maxRetries = maxRetries
+	}
+}
+
+// BackoffOption sets the wait before the first retry, doubled at each retry up to `max`.
+func BackoffOption(min, max time.Duration) Option {
+	return func(t *Transport) {
+		t.minBackoff, t.maxBackoff = min, max
+	}
+}
+
+// BreakerOption sets the number of requests failing in a row which opens the circuit of an upstream, and how long it
+// stays open.
+func BreakerOption(threshold int, cooldown time.Duration) Option {
+	return func(t *Transport) {
+		t.threshold, t.cooldown = threshold, cooldown
+	}
+}
+
+// NewTransport returns a Transport sending the requests through `base`, or http.DefaultTransport if it is nil.
+func NewTransport(base http.RoundTripper, options ...Option) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	t := &Transport{
+		base:       base,
+		maxRetries: DefaultMaxRetries,
+		minBackoff: DefaultMinBackoff,
+		maxBackoff: DefaultMaxBackoff,
+		threshold:  DefaultFailureThreshold,
+		cooldown:   DefaultCooldown,
+		breakers:   make(map[string]*breaker),
+	}
+	for _, option := range options {
+		option(t)
+	}
+	return t
+}
+
+// WrapClient returns a copy of the client whose requests go through a Transport wrapping its own.
+func WrapClient(client *http.Client, options ...Option) *http.Client {
+	wrapped := &http.Client{}
+	if client != nil {
+		*wrapped = *client
+	}
+	wrapped.Transport = NewTransport(wrapped.Transport, options...)
+	return wrapped
+}
+
+// RoundTrip sends the request, retrying it while it fails and is retriable.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	upstream := req.URL.Host
+	fields := logrus.Fields{"upstream": upstream, "method": req.Method, "path": req.URL.Path}
+	circuit := t.breaker(upstream)
+	if !circuit.allow(time.Now()) {
+		return nil, logging.WithLog(&UnavailableError{Upstream: upstream}, logrus.ErrorLevel, fields)
+	}
+
+	recorded := false
+	defer func() {
+		if !recorded {
+			circuit.abandon(time.Now())
+		}
+	}()
+
+	rewindable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+	for attempt := 1; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if err == nil && resp.StatusCode < http.StatusInternalServerError {
+			recorded = true
+			circuit.record(true, time.Now())
+			return resp, nil
+		}
+		if ctxErr := req.Context().Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+
+		retriable := rewindable && (isIdempotent(req) || (err != nil && isDialError(err)))
+		if !retriable || attempt > t.maxRetries {
+			recorded = true
+			if circuit.record(false, time.Now()) {
+				logrus.WithFields(fields).Errorf("opening the circuit of %s for %v", upstream, t.cooldown)
+			}
+			if err != nil {
+				fields["attempts"] = attempt
+				return nil, logging.WithLog(&UnavailableError{Upstream: upstream, Attempts: attempt, Err: err}, logrus.ErrorLevel, fields)
+			}
+			return resp, nil
+		}
+
+		if resp != nil {
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		backoff := t.backoff(attempt)
+		logrus.WithFields(fields).WithField("attempt", attempt).Warnf("request to %s failed: retrying in %v", upstream, backoff)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		}
+
+		if req, err = rewind(req); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (t *Transport) breaker(upstream string) *breaker {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	b, ok := t.breakers[upstream]
+	if !ok {
+		b = &breaker{threshold: t.threshold, cooldown: t.cooldown}
+		t.breakers[upstream] = b
+	}
+	return b
+}
+
+// backoff returns the wait before the given retry: the exponential backoff, of which the second half is random.
+func (t *Transport) backoff(attempt int) time.Duration {
+	backoff := t.minBackoff << uint(attempt-1)
+	if backoff > t.maxBackoff || backoff <= 0 {
+		backoff = t.maxBackoff
+	}
+	half := backoff / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// rewind returns a copy of the request with a new body, to send it again.
+func rewind(req *http.Request) (*http.Request, error) {
+	if req.GetBody == nil {
+		return req, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	rewound := req.Clone(req.Context())
+	rewound.Body = body
+	return rewound, nil
+}
+
+// isIdempotent reports whether the request can be sent again without side effects, like net/http defines it.
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	_, hasKey := req.Header["Idempotency-Key"]
+	_, hasXKey := req.Header["X-Idempotency-Key"]
+	return hasKey || hasXKey
+}
+
+// isDialError reports whether the request failed before the connection was established, thus was never received.
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
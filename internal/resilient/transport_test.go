@@ -0,0 +1,205 @@
+package resilient
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gustavobelfort/42-jitsi/internal/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newServer returns a test server answering the given statuses in order, then the last one, and its hits counter.
+func newServer(statuses ...int) (*httptest.Server, *int32) {
+	hits := new(int32)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		hit := int(atomic.AddInt32(hits, 1))
+		status := statuses[len(statuses)-1]
+		if hit <= len(statuses) {
+			status = statuses[hit-1]
+		}
+		w.WriteHeader(status)
+		w.Write(body)
+	}))
+	return server, hits
+}
+
+func newClient(options ...Option) *http.Client {
+	options = append([]Option{BackoffOption(time.Millisecond, time.Millisecond*4)}, options...)
+	return WrapClient(nil, options...)
+}
+
+func TestTransport_RetriesIdempotent(t *testing.T) {
+	server, hits := newServer(http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusOK)
+	defer server.Close()
+
+	resp, err := newClient().Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(3), atomic.LoadInt32(hits))
+}
+
+func TestTransport_RewindsBody(t *testing.T) {
+	server, hits := newServer(http.StatusInternalServerError, http.StatusOK)
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodPut, server.URL, strings.NewReader("payload"))
+	require.NoError(t, err)
+	resp, err := newClient().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "payload", string(body))
+	assert.Equal(t, int32(2), atomic.LoadInt32(hits))
+}
+
+func TestTransport_GivesUp(t *testing.T) {
+	server, hits := newServer(http.StatusInternalServerError)
+	defer server.Close()
+
+	resp, err := newClient(RetriesOption(2)).Get(server.URL)
+	require.NoError(t, err, "the last response is returned as is")
+	resp.Body.Close()
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal(t, int32(3), atomic.LoadInt32(hits))
+}
+
+func TestTransport_NonIdempotent(t *testing.T) {
+	server, hits := newServer(http.StatusInternalServerError)
+	defer server.Close()
+
+	t.Run("NotRetried", func(t *testing.T) {
+		resp, err := newClient().Post(server.URL, "text/plain", strings.NewReader("payload"))
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, int32(1), atomic.LoadInt32(hits))
+	})
+
+	t.Run("IdempotencyKey", func(t *testing.T) {
+		atomic.StoreInt32(hits, 0)
+		req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("payload"))
+		require.NoError(t, err)
+		req.Header.Set("Idempotency-Key", "42")
+
+		resp, err := newClient(RetriesOption(1)).Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, int32(2), atomic.LoadInt32(hits))
+	})
+}
+
+func TestTransport_Unreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	listener.Close()
+
+	_, err = newClient(RetriesOption(1)).Post("http://"+addr, "text/plain", strings.NewReader("payload"))
+	require.Error(t, err)
+	assert.True(t, IsUnavailable(err))
+
+	var unavailableErr *UnavailableError
+	require.True(t, errors.As(err, &unavailableErr))
+	assert.Equal(t, 2, unavailableErr.Attempts, "a request which could not be sent is retried")
+	logError := &logging.WithLogError{}
+	assert.True(t, errors.As(err, &logError))
+}
+
+func TestTransport_Breaker(t *testing.T) {
+	server, hits := newServer(http.StatusInternalServerError)
+	defer server.Close()
+	client := newClient(RetriesOption(0), BreakerOption(2, time.Hour))
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(server.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	_, err := client.Get(server.URL)
+	require.Error(t, err)
+	assert.True(t, IsUnavailable(err))
+	assert.Equal(t, int32(2), atomic.LoadInt32(hits), "the open circuit rejects the request")
+
+	other, otherHits := newServer(http.StatusOK)
+	defer other.Close()
+	resp, err := client.Get(other.URL)
+	require.NoError(t, err, "the circuits are per upstream")
+	resp.Body.Close()
+	assert.Equal(t, int32(1), atomic.LoadInt32(otherHits))
+}
+
+func TestTransport_Cancelled(t *testing.T) {
+	server, hits := newServer(http.StatusInternalServerError)
+	defer server.Close()
+	client := WrapClient(nil, BackoffOption(time.Hour, time.Hour))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+
+	_, err = client.Do(req.WithContext(ctx))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Equal(t, int32(1), atomic.LoadInt32(hits))
+}
+
+func TestTransport_HalfOpenCancelled(t *testing.T) {
+	hits := new(int32)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(hits, 1) == 2 {
+			// The trial request hangs until it is cancelled.
+			<-r.Context().Done()
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	cooldown := time.Millisecond * 50
+	client := newClient(RetriesOption(0), BreakerOption(1, cooldown))
+
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	time.Sleep(cooldown)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	_, err = client.Do(req.WithContext(ctx))
+	require.Error(t, err)
+	assert.False(t, IsUnavailable(err), "the trial request was cancelled")
+
+	_, err = client.Get(server.URL)
+	require.Error(t, err)
+	assert.True(t, IsUnavailable(err), "the circuit opens again after the cancelled trial")
+
+	time.Sleep(cooldown)
+	resp, err = client.Get(server.URL)
+	require.NoError(t, err, "another trial request is let through after the cooldown")
+	resp.Body.Close()
+	assert.Equal(t, int32(3), atomic.LoadInt32(hits))
+}
+
+func TestTransport_Backoff(t *testing.T) {
+	transport := NewTransport(nil, BackoffOption(time.Millisecond*100, time.Millisecond*300))
+
+	for attempt, max := range []time.Duration{time.Millisecond * 100, time.Millisecond * 200, time.Millisecond * 300, time.Millisecond * 300} {
+		backoff := transport.backoff(attempt + 1)
+		assert.True(t, backoff >= max/2 && backoff <= max, "attempt %d: %v not within [%v, %v]", attempt+1, backoff, max/2, max)
+	}
+}
@@ -0,0 +1,16 @@
+package room
+
+import "errors"
+
+var (
+	// MissingSecretError is returned when no room secret is configured.
+	MissingSecretError = errors.New("the jitsi room secret is not configured")
+	// MissingAppSecretError is returned when the token authentication is enabled without any app secret configured.
+	MissingAppSecretError = errors.New("the jitsi jwt app secret is not configured")
+	// MissingMeetingError is returned when trying to get the links of a meeting that was never generated.
+	MissingMeetingError = errors.New("the meeting of the scale team was never generated")
+	// EmptyNameError is returned when the room template formats an empty room name.
+	EmptyNameError = errors.New("the room template formatted an empty room name")
+	// GuessableNameError is returned when the room template formats a room name which does not contain the hash.
+	GuessableNameError = errors.New("the room template formatted a room name without the hash")
+)
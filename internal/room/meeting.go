@@ -0,0 +1,70 @@
+package room
+
+import (
+	"github.com/gustavobelfort/42-jitsi/internal/db"
+	"github.com/jinzhu/gorm"
+	"github.com/sirupsen/logrus"
+)
+
+// Provider is the name of the video conference provider of the generated meetings.
+const Provider = "jitsi"
+
+// NewMeeting generates the meeting of a scale team.
+//
+// It is meant to be generated once, when the scale team is created, and then read from the database.
+func NewMeeting(scaleTeamID int) (db.Meeting, error) {
+	name, err := Name(scaleTeamID)
+	if err != nil {
+		return db.Meeting{}, err
+	}
+	link, err := Link(scaleTeamID)
+	if err != nil {
+		return db.Meeting{}, err
+	}
+	return db.Meeting{
+		Provider: Provider,
+		RoomName: name,
+		URL:      link,
+	}, nil
+}
+
+// MeetingsBackfill is the name of the backfill of MigrateMeetings.
+const MeetingsBackfill = "meetings"
+
+// MigrateMeetings generates and stores the meetings of the scale teams that were created before the meetings were
+// stored in the database. It is run by the migrate command.
+func MigrateMeetings(tx *gorm.DB, manager db.ScaleTeamManager) error {
+	scaleTeams, err := manager.Get(tx, db.ScaleTeamMeetingMissingOption())
+	if err != nil {
+		return err
+	}
+	if len(scaleTeams) == 0 {
+		return nil
+	}
+
+	logrus.Infof("generating the meetings of %d scale teams", len(scaleTeams))
+	for _, scaleTeam := range scaleTeams {
+		meeting, err := NewMeeting(scaleTeam.GetID())
+		if err != nil {
+			return err
+		}
+		scaleTeam.SetMeeting(meeting)
+		if err := scaleTeam.Save(tx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckMeetings returns a *db.PendingBackfillError if some scale teams still miss the meeting that MigrateMeetings
+// generates.
+func CheckMeetings(tx *gorm.DB, manager db.ScaleTeamManager) error {
+	scaleTeams, err := manager.Get(tx, db.ScaleTeamMeetingMissingOption())
+	if err != nil {
+		return err
+	}
+	if len(scaleTeams) > 0 {
+		return &db.PendingBackfillError{Backfill: MeetingsBackfill, Count: len(scaleTeams)}
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package room
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gustavobelfort/42-jitsi/internal/db"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type ScaleTeamManagerMock struct {
+	mock.Mock
+}
+
+func (m *ScaleTeamManagerMock) DB() *gorm.DB {
+	return m.Called().Get(0).(*gorm.DB)
+}
+
+func (m *ScaleTeamManagerMock) Create(tx *gorm.DB, id int, beginAt, intraUpdatedAt time.Time, meeting db.Meeting) (db.ScaleTeam, error) {
+	toReturn := m.Called(tx, id, beginAt, intraUpdatedAt, meeting)
+	return toReturn.Get(0).(db.ScaleTeam), toReturn.Error(1)
+}
+
+func (m *ScaleTeamManagerMock) Update(tx *gorm.DB, scaleTeam db.ScaleTeam) error {
+	return m.Called(tx, scaleTeam).Error(0)
+}
+
+func (m *ScaleTeamManagerMock) SetAttendanceChecked(tx *gorm.DB, id int) error {
+	return m.Called(tx, id).Error(0)
+}
+
+func (m *ScaleTeamManagerMock) Delete(tx *gorm.DB, scaleTeam db.ScaleTeam) error {
+	return m.Called(tx, scaleTeam).Error(0)
+}
+
+func (m *ScaleTeamManagerMock) Get(tx *gorm.DB, options ...db.GetOption) ([]db.ScaleTeam, error) {
+	toReturn := m.Called(tx, options)
+	return toReturn.Get(0).([]db.ScaleTeam), toReturn.Error(1)
+}
+
+type ScaleTeamMock struct {
+	db.ScaleTeam
+	mock.Mock
+}
+
+func (m *ScaleTeamMock) GetID() int {
+	return m.Called().Int(0)
+}
+
+func (m *ScaleTeamMock) SetMeeting(meeting db.Meeting) {
+	m.Called(meeting)
+}
+
+func (m *ScaleTeamMock) Save(tx *gorm.DB) error {
+	return m.Called(tx).Error(0)
+}
+
+func TestNewMeeting(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		setConfig("evaluation-{{.Hash}}", "secret", "")
+
+		meeting, err := NewMeeting(1)
+		require.NoError(t, err)
+		assert.Equal(t, db.Meeting{
+			Provider: "jitsi",
+			RoomName: "evaluation-bd28ee142ca5b46259f6e27fc3a4216f",
+			URL:      "https://meet.jit.si/evaluation-bd28ee142ca5b46259f6e27fc3a4216f",
+		}, meeting)
+	})
+
+	t.Run("MissingSecret", func(t *testing.T) {
+		setConfig("evaluation-{{.Hash}}", "", "")
+
+		_, err := NewMeeting(1)
+		assert.Equal(t, MissingSecretError, err)
+	})
+}
+
+func TestMigrateMeetings(t *testing.T) {
+	setConfig("{{.Hash}}", "secret", "")
+	tx := &gorm.DB{}
+
+	t.Run("Migrate", func(t *testing.T) {
+		manager := &ScaleTeamManagerMock{}
+		record := &ScaleTeamMock{}
+
+		manager.On("Get", tx, mock.Anything).Return([]db.ScaleTeam{record}, nil).Once()
+		record.On("GetID").Return(1).Once()
+		record.On("SetMeeting", db.Meeting{
+			Provider: "jitsi",
+			RoomName: "bd28ee142ca5b46259f6e27fc3a4216f",
+			URL:      "https://meet.jit.si/bd28ee142ca5b46259f6e27fc3a4216f",
+		}).Return().Once()
+		record.On("Save", tx).Return(nil).Once()
+
+		assert.NoError(t, MigrateMeetings(tx, manager))
+		manager.AssertExpectations(t)
+		record.AssertExpectations(t)
+	})
+
+	t.Run("NothingToMigrate", func(t *testing.T) {
+		manager := &ScaleTeamManagerMock{}
+
+		manager.On("Get", tx, mock.Anything).Return([]db.ScaleTeam{}, nil).Once()
+
+		assert.NoError(t, MigrateMeetings(tx, manager))
+		manager.AssertExpectations(t)
+	})
+
+	t.Run("SaveError", func(t *testing.T) {
+		manager := &ScaleTeamManagerMock{}
+		record := &ScaleTeamMock{}
+		expectedError := errors.New("testing")
+
+		manager.On("Get", tx, mock.Anything).Return([]db.ScaleTeam{record}, nil).Once()
+		record.On("GetID").Return(1).Once()
+		record.On("SetMeeting", mock.Anything).Return().Once()
+		record.On("Save", tx).Return(expectedError).Once()
+
+		assert.Equal(t, expectedError, MigrateMeetings(tx, manager))
+		manager.AssertExpectations(t)
+		record.AssertExpectations(t)
+	})
+}
+
+func TestCheckMeetings(t *testing.T) {
+	tx := &gorm.DB{}
+
+	t.Run("Backfilled", func(t *testing.T) {
+		manager := &ScaleTeamManagerMock{}
+		manager.On("Get", tx, mock.Anything).Return([]db.ScaleTeam{}, nil).Once()
+
+		assert.NoError(t, CheckMeetings(tx, manager))
+		manager.AssertExpectations(t)
+	})
+
+	t.Run("Pending", func(t *testing.T) {
+		manager := &ScaleTeamManagerMock{}
+		manager.On("Get", tx, mock.Anything).Return([]db.ScaleTeam{&ScaleTeamMock{}, &ScaleTeamMock{}}, nil).Once()
+
+		assert.Equal(t, &db.PendingBackfillError{Backfill: MeetingsBackfill, Count: 2}, CheckMeetings(tx, manager))
+		manager.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,77 @@
+package room
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/gustavobelfort/42-jitsi/internal/config"
+	"github.com/gustavobelfort/42-jitsi/internal/jitsi"
+)
+
+// hashLength is the number of hexadecimal characters of the HMAC kept in the room names.
+const hashLength = 32
+
+// Data is the data passed to the room name template.
+//
+// It purposely contains nothing that could identify the participants. The scale team id is kept for the campuses
+// which want to recognize the rooms of their evaluations, the name still has to contain the hash to stay unguessable.
+type Data struct {
+	Hash        string
+	ScaleTeamID int
+}
+
+// Hash returns the HMAC of the scale team id and the configured salt, keyed by the configured room secret.
+//
+// It is stable for a given scale team as long as the secret and the salt do not change.
+func Hash(scaleTeamID int) (string, error) {
+	if config.Conf.Jitsi.RoomSecret == "" {
+		return "", MissingSecretError
+	}
+	mac := hmac.New(sha256.New, []byte(config.Conf.Jitsi.RoomSecret))
+	mac.Write([]byte(config.Conf.Jitsi.RoomSalt))
+	mac.Write([]byte(strconv.Itoa(scaleTeamID)))
+	return hex.EncodeToString(mac.Sum(nil))[:hashLength], nil
+}
+
+// Name formats the room name of a scale team with the configured room template.
+func Name(scaleTeamID int) (string, error) {
+	hash, err := Hash(scaleTeamID)
+	if err != nil {
+		return "", err
+	}
+
+	tmpl, err := template.New("room").Parse(config.Conf.Jitsi.RoomTemplate)
+	if err != nil {
+		return "", err
+	}
+
+	buffer := new(strings.Builder)
+	if err := tmpl.Execute(buffer, &Data{Hash: hash, ScaleTeamID: scaleTeamID}); err != nil {
+		return "", err
+	}
+	if buffer.Len() == 0 {
+		return "", EmptyNameError
+	}
+	if !strings.Contains(buffer.String(), hash) {
+		return "", GuessableNameError
+	}
+	return normalizeName(buffer.String()), nil
+}
+
+// normalizeName normalizes a room name the way jitsi does, so that the links and the tokens' room claims match.
+func normalizeName(name string) string {
+	return strings.ToLower(name)
+}
+
+// Link returns the link to the room of a scale team on the configured jitsi server.
+func Link(scaleTeamID int) (string, error) {
+	name, err := Name(scaleTeamID)
+	if err != nil {
+		return "", err
+	}
+	return jitsi.URL(name)
+}
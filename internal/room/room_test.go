@@ -0,0 +1,133 @@
+package room
+
+import (
+	"testing"
+
+	"github.com/gustavobelfort/42-jitsi/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setConfig(template, secret, salt string) {
+	config.Conf.Jitsi = config.Jitsi{
+		URL:          "https://meet.jit.si",
+		RoomTemplate: template,
+		RoomSecret:   secret,
+		RoomSalt:     salt,
+	}
+}
+
+func TestHash(t *testing.T) {
+	t.Run("Stable", func(t *testing.T) {
+		setConfig("{{.Hash}}", "secret", "salt")
+
+		first, err := Hash(21)
+		require.NoError(t, err)
+		second, err := Hash(21)
+		require.NoError(t, err)
+
+		assert.Len(t, first, hashLength)
+		assert.Equal(t, first, second)
+	})
+
+	t.Run("DependsOnScaleTeam", func(t *testing.T) {
+		setConfig("{{.Hash}}", "secret", "salt")
+
+		first, err := Hash(21)
+		require.NoError(t, err)
+		second, err := Hash(42)
+		require.NoError(t, err)
+
+		assert.NotEqual(t, first, second)
+	})
+
+	t.Run("DependsOnSecretAndSalt", func(t *testing.T) {
+		setConfig("{{.Hash}}", "secret", "salt")
+		reference, err := Hash(21)
+		require.NoError(t, err)
+
+		setConfig("{{.Hash}}", "other_secret", "salt")
+		otherSecret, err := Hash(21)
+		require.NoError(t, err)
+
+		setConfig("{{.Hash}}", "secret", "other_salt")
+		otherSalt, err := Hash(21)
+		require.NoError(t, err)
+
+		assert.NotEqual(t, reference, otherSecret)
+		assert.NotEqual(t, reference, otherSalt)
+	})
+
+	t.Run("MissingSecret", func(t *testing.T) {
+		setConfig("{{.Hash}}", "", "salt")
+
+		_, err := Hash(21)
+		assert.Equal(t, MissingSecretError, err)
+	})
+}
+
+func TestName(t *testing.T) {
+	t.Run("Template", func(t *testing.T) {
+		setConfig("evaluation-{{.Hash}}", "secret", "")
+
+		name, err := Name(1)
+		require.NoError(t, err)
+		assert.Equal(t, "evaluation-bd28ee142ca5b46259f6e27fc3a4216f", name)
+	})
+
+	t.Run("ScaleTeamID", func(t *testing.T) {
+		setConfig("{{.ScaleTeamID}}-{{.Hash}}", "secret", "")
+
+		name, err := Name(1)
+		require.NoError(t, err)
+		assert.Equal(t, "1-bd28ee142ca5b46259f6e27fc3a4216f", name)
+	})
+
+	t.Run("Normalized", func(t *testing.T) {
+		setConfig("Evaluation-{{.Hash}}", "secret", "")
+
+		name, err := Name(1)
+		require.NoError(t, err)
+		assert.Equal(t, "evaluation-bd28ee142ca5b46259f6e27fc3a4216f", name)
+
+		link, err := Link(1)
+		require.NoError(t, err)
+		assert.Equal(t, "https://meet.jit.si/evaluation-bd28ee142ca5b46259f6e27fc3a4216f", link)
+	})
+
+	t.Run("WithoutHash", func(t *testing.T) {
+		setConfig("evaluation-{{.ScaleTeamID}}", "secret", "")
+
+		_, err := Name(1)
+		assert.Equal(t, GuessableNameError, err)
+	})
+
+	t.Run("BadTemplate", func(t *testing.T) {
+		setConfig("{{.Hash", "secret", "")
+
+		_, err := Name(1)
+		assert.Error(t, err)
+	})
+
+	t.Run("UnknownField", func(t *testing.T) {
+		setConfig("{{.Logins}}", "secret", "")
+
+		_, err := Name(1)
+		assert.Error(t, err)
+	})
+
+	t.Run("EmptyName", func(t *testing.T) {
+		setConfig("", "secret", "")
+
+		_, err := Name(1)
+		assert.Equal(t, EmptyNameError, err)
+	})
+}
+
+func TestLink(t *testing.T) {
+	setConfig("{{.Hash}}", "secret", "")
+
+	link, err := Link(1)
+	require.NoError(t, err)
+	assert.Equal(t, "https://meet.jit.si/bd28ee142ca5b46259f6e27fc3a4216f", link)
+}
@@ -0,0 +1,70 @@
+package room
+
+import (
+	"time"
+
+	"github.com/gustavobelfort/42-jitsi/internal/config"
+	"github.com/gustavobelfort/42-jitsi/internal/db"
+	"github.com/gustavobelfort/42-jitsi/internal/jitsi"
+)
+
+// Participant is a participant of an evaluation.
+type Participant struct {
+	Login     string
+	Email     string
+	Moderator bool
+}
+
+// Token signs the token of a participant to join the given room.
+//
+// The token expires at `beginAt` plus the configured duration. Only moderators get the moderator role.
+func Token(roomName string, beginAt time.Time, participant Participant) (string, error) {
+	jwtConf := config.Conf.Jitsi.JWT
+	if jwtConf.AppSecret == "" {
+		return "", MissingAppSecretError
+	}
+
+	claims := &jitsi.Claims{
+		Audience:  jwtConf.Audience,
+		Issuer:    jwtConf.AppID,
+		Subject:   jwtConf.Subject,
+		Room:      normalizeName(roomName),
+		ExpiresAt: beginAt.Add(jwtConf.Duration).Unix(),
+		Moderator: participant.Moderator,
+		Context: jitsi.ClaimsContext{
+			User: jitsi.ClaimsUser{
+				ID:        participant.Login,
+				Name:      participant.Login,
+				Email:     participant.Email,
+				Moderator: participant.Moderator,
+			},
+		},
+	}
+	return jitsi.Sign(claims, jwtConf.AppSecret)
+}
+
+// Links returns the links to the stored meeting of a scale team for each of its participants, indexed by login.
+//
+// If the token authentication is disabled, every participant gets the meeting's link.
+func Links(meeting db.Meeting, beginAt time.Time, participants []Participant) (map[string]string, error) {
+	links := make(map[string]string, len(participants))
+
+	if meeting.IsZero() {
+		return nil, MissingMeetingError
+	}
+
+	for _, participant := range participants {
+		if !config.Conf.Jitsi.JWT.Enabled {
+			links[participant.Login] = meeting.URL
+			continue
+		}
+		token, err := Token(meeting.RoomName, beginAt, participant)
+		if err != nil {
+			return nil, err
+		}
+		if links[participant.Login], err = jitsi.WithToken(meeting.URL, token); err != nil {
+			return nil, err
+		}
+	}
+	return links, nil
+}
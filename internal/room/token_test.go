@@ -0,0 +1,114 @@
+package room
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gustavobelfort/42-jitsi/internal/config"
+	"github.com/gustavobelfort/42-jitsi/internal/db"
+	"github.com/gustavobelfort/42-jitsi/internal/jitsi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setJWTConfig(enabled bool, appSecret string) {
+	config.Conf.Jitsi.JWT = config.JitsiJWT{
+		Enabled:   enabled,
+		AppID:     "app_id",
+		AppSecret: appSecret,
+		Audience:  "jitsi",
+		Subject:   "meet.jitsi",
+		Duration:  time.Hour,
+	}
+}
+
+func TestToken(t *testing.T) {
+	beginAt := time.Now().Add(time.Minute * 15)
+
+	t.Run("Moderator", func(t *testing.T) {
+		setConfig("{{.Hash}}", "secret", "")
+		setJWTConfig(true, "app_secret")
+
+		token, err := Token("bd28ee142ca5b46259f6e27fc3a4216f", beginAt, Participant{Login: "xlogin", Email: "xlogin@42.fr", Moderator: true})
+		require.NoError(t, err)
+
+		claims, err := jitsi.Verify(token, "app_secret")
+		require.NoError(t, err)
+		assert.Equal(t, "jitsi", claims.Audience)
+		assert.Equal(t, "app_id", claims.Issuer)
+		assert.Equal(t, "meet.jitsi", claims.Subject)
+		assert.Equal(t, "bd28ee142ca5b46259f6e27fc3a4216f", claims.Room)
+		assert.Equal(t, beginAt.Add(time.Hour).Unix(), claims.ExpiresAt)
+		assert.True(t, claims.Moderator)
+		assert.Equal(t, jitsi.ClaimsUser{ID: "xlogin", Name: "xlogin", Email: "xlogin@42.fr", Moderator: true}, claims.Context.User)
+	})
+
+	t.Run("NotModerator", func(t *testing.T) {
+		setConfig("{{.Hash}}", "secret", "")
+		setJWTConfig(true, "app_secret")
+
+		token, err := Token("bd28ee142ca5b46259f6e27fc3a4216f", beginAt, Participant{Login: "ylogin"})
+		require.NoError(t, err)
+
+		claims, err := jitsi.Verify(token, "app_secret")
+		require.NoError(t, err)
+		assert.False(t, claims.Moderator)
+		assert.False(t, claims.Context.User.Moderator)
+	})
+
+	t.Run("MissingAppSecret", func(t *testing.T) {
+		setConfig("{{.Hash}}", "secret", "")
+		setJWTConfig(true, "")
+
+		_, err := Token("bd28ee142ca5b46259f6e27fc3a4216f", beginAt, Participant{Login: "ylogin"})
+		assert.Equal(t, MissingAppSecretError, err)
+	})
+}
+
+func TestLinks(t *testing.T) {
+	participants := []Participant{{Login: "xlogin", Moderator: true}, {Login: "ylogin"}}
+	meeting := db.Meeting{
+		Provider: Provider,
+		RoomName: "bd28ee142ca5b46259f6e27fc3a4216f",
+		URL:      "https://meet.jit.si/bd28ee142ca5b46259f6e27fc3a4216f#config.subject=%2242%20Evaluation%22",
+	}
+
+	t.Run("JWTDisabled", func(t *testing.T) {
+		setConfig("{{.Hash}}", "secret", "")
+		setJWTConfig(false, "")
+
+		links, err := Links(meeting, time.Now(), participants)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"xlogin": meeting.URL,
+			"ylogin": meeting.URL,
+		}, links)
+	})
+
+	t.Run("JWTEnabled", func(t *testing.T) {
+		setConfig("{{.Hash}}", "secret", "")
+		setJWTConfig(true, "app_secret")
+
+		links, err := Links(meeting, time.Now(), participants)
+		require.NoError(t, err)
+		require.Len(t, links, 2)
+
+		for _, participant := range participants {
+			parsed, err := url.Parse(links[participant.Login])
+			require.NoError(t, err)
+			assert.Equal(t, "/bd28ee142ca5b46259f6e27fc3a4216f", parsed.Path)
+			assert.Equal(t, "config.subject=%2242%20Evaluation%22", parsed.EscapedFragment())
+
+			claims, err := jitsi.Verify(parsed.Query().Get("jwt"), "app_secret")
+			require.NoError(t, err)
+			assert.Equal(t, participant.Login, claims.Context.User.ID)
+			assert.Equal(t, participant.Moderator, claims.Moderator)
+		}
+	})
+
+	t.Run("MissingMeeting", func(t *testing.T) {
+		_, err := Links(db.Meeting{}, time.Now(), participants)
+		assert.Equal(t, MissingMeetingError, err)
+	})
+}
@@ -0,0 +1,50 @@
+package slack
+
+// block is a Block Kit layout block of a message posted with the Slack Web API.
+type block struct {
+	Type     string        `json:"type"`
+	Text     *textObject   `json:"text,omitempty"`
+	Elements []interface{} `json:"elements,omitempty"`
+}
+
+// textObject is a Block Kit text, either "plain_text" or "mrkdwn".
+type textObject struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// button is a Block Kit button opening a link.
+type button struct {
+	Type  string      `json:"type"`
+	Text  *textObject `json:"text"`
+	URL   string      `json:"url"`
+	Style string      `json:"style,omitempty"`
+}
+
+// messageBlocks lays out a message like the slack_that one with the attachment: its title as header, the text, a
+// button to the link if any and its pretext as context.
+func messageBlocks(text string, attachment Attachment, link string) []block {
+	blocks := make([]block, 0, 4)
+	if attachment.Title != "" {
+		blocks = append(blocks, block{Type: "header", Text: &textObject{Type: "plain_text", Text: attachment.Title}})
+	}
+	blocks = append(blocks, block{Type: "section", Text: &textObject{Type: "mrkdwn", Text: text}})
+	if link != "" {
+		blocks = append(blocks, block{
+			Type: "actions",
+			Elements: []interface{}{button{
+				Type:  "button",
+				Text:  &textObject{Type: "plain_text", Text: "Join the meeting"},
+				URL:   link,
+				Style: "primary",
+			}},
+		})
+	}
+	if attachment.Pretext != "" {
+		blocks = append(blocks, block{
+			Type:     "context",
+			Elements: []interface{}{textObject{Type: "mrkdwn", Text: attachment.Pretext}},
+		})
+	}
+	return blocks
+}
@@ -1,6 +1,8 @@
 package slack
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -9,6 +11,7 @@ import (
 	"time"
 
 	"github.com/gustavobelfort/42-jitsi/internal/intra"
+	"github.com/gustavobelfort/42-jitsi/internal/resilient"
 )
 
 // ThatClient is the tipe that will hold the SlackThat client configurations
@@ -26,8 +29,22 @@ func (client *ThatClient) getURL(endpoint string) string {
 	return urlCopy.String()
 }
 
-func (client *ThatClient) request(method string, endpoint string, reader io.Reader) error {
-	request, err := http.NewRequest(method, client.getURL(endpoint), reader)
+// request sends a request to the slack_that server and decodes its response into v if it is not nil. It fails unless
+// the response has the expected status.
+//
+// As slack_that may have posted a message before failing, the POST requests are only retried by the resilient
+// transport if they could not be sent at all.
+func (client *ThatClient) request(method string, endpoint string, reader io.Reader, status int, v interface{}) error {
+	// The body is buffered so that the request can be sent again if it fails.
+	var body io.Reader
+	if reader != nil {
+		buffer := new(bytes.Buffer)
+		if _, err := buffer.ReadFrom(reader); err != nil {
+			return err
+		}
+		body = buffer
+	}
+	request, err := http.NewRequest(method, client.getURL(endpoint), body)
 	if err != nil {
 		return err
 	}
@@ -35,10 +52,14 @@ func (client *ThatClient) request(method string, endpoint string, reader io.Read
 	if err != nil {
 		return err
 	}
+	defer resp.Body.Close()
 
-	if resp.StatusCode != 201 {
+	if resp.StatusCode != status {
 		return fmt.Errorf("unable to send request to the slack that client")
 	}
+	if v != nil {
+		return json.NewDecoder(resp.Body).Decode(v)
+	}
 
 	return nil
 }
@@ -49,7 +70,7 @@ func (client *ThatClient) postMessage(options ...PostMessageOptions) error {
 		opt(params)
 	}
 
-	if err := client.request(http.MethodPost, "/", params); err != nil {
+	if err := client.request(http.MethodPost, "/", params, http.StatusCreated, nil); err != nil {
 		return err
 	}
 
@@ -59,7 +80,7 @@ func (client *ThatClient) postMessage(options ...PostMessageOptions) error {
 // GetHealth makes a GET request to the slack_that API's health endpoint.
 func (client *ThatClient) GetHealth() (map[string]interface{}, error) {
 	data := make(map[string]interface{})
-	if err := client.request(http.MethodGet, "/health", nil); err != nil {
+	if err := client.request(http.MethodGet, "/health", nil, http.StatusOK, &data); err != nil {
 		return nil, err
 	}
 	return data, nil
@@ -75,7 +96,8 @@ func New(intra intra.Client, baseURL string) (SlackThat, error) {
 
 	timeout := time.Duration(5 * time.Second)
 	baseClient := http.Client{
-		Timeout: timeout,
+		Timeout:   timeout,
+		Transport: resilient.NewTransport(nil),
 	}
 
 	client := &ThatClient{
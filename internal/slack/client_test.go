@@ -0,0 +1,294 @@
+package slack_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gustavobelfort/42-jitsi/internal/config"
+	"github.com/gustavobelfort/42-jitsi/internal/db"
+	"github.com/gustavobelfort/42-jitsi/internal/intra"
+	"github.com/gustavobelfort/42-jitsi/internal/intra/intratest"
+	"github.com/gustavobelfort/42-jitsi/internal/jitsi"
+	"github.com/gustavobelfort/42-jitsi/internal/notify"
+	"github.com/gustavobelfort/42-jitsi/internal/resilient"
+	"github.com/gustavobelfort/42-jitsi/internal/room"
+	"github.com/gustavobelfort/42-jitsi/internal/slack"
+	"github.com/gustavobelfort/42-jitsi/internal/slack/slackthattest"
+	"github.com/stretchr/testify/suite"
+)
+
+var meeting = db.Meeting{
+	Provider: "jitsi",
+	RoomName: "bd28ee142ca5b46259f6e27fc3a4216f",
+	URL:      "https://meet.jit.si/bd28ee142ca5b46259f6e27fc3a4216f",
+}
+
+func TestThatClient(t *testing.T) {
+	suite.Run(t, new(ThatClientSuite))
+}
+
+// ThatClientSuite tests the slack_that client against the fake APIs of slackthattest and intratest.
+type ThatClientSuite struct {
+	suite.Suite
+
+	intraServer *httptest.Server
+	intra       intra.Client
+
+	server *httptest.Server
+	api    *slackthattest.API
+	client slack.SlackThat
+}
+
+func (s *ThatClientSuite) SetupSuite() {
+	s.Require().Implements((*slack.SlackThat)(nil), &slack.ThatClient{})
+
+	s.intraServer, _ = intratest.NewServer(&intratest.Fixtures{
+		Users: []intra.User{
+			{ID: 1, Login: "xlogin", Email: "xlogin@student.42campus.org"},
+			{ID: 2, Login: "ylogin", Email: "ylogin@student.42campus.org"},
+		},
+	})
+	config.Conf.Intra.URL = s.intraServer.URL
+	config.Conf.SlackThat.Workspace = "testWorkspace"
+	config.Conf.Jitsi.JWT = config.JitsiJWT{
+		AppID:     "app_id",
+		AppSecret: "app_secret",
+		Audience:  "jitsi",
+		Subject:   "*",
+		Duration:  time.Hour,
+	}
+}
+
+func (s *ThatClientSuite) SetupTest() {
+	s.server, s.api = slackthattest.NewServer()
+
+	intraClient, err := intra.NewClient("id", "secret", s.intraServer.Client())
+	s.Require().NoError(err)
+	s.intra = intraClient
+	client, err := slack.New(intraClient, s.server.URL)
+	s.Require().NoError(err)
+	s.client = client
+	config.Conf.Jitsi.JWT.Enabled = false
+}
+
+// countingTransport counts the requests sent through the default transport.
+type countingTransport struct {
+	count int32
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	atomic.AddInt32(&t.count, 1)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// assertTokenLink asserts that the link is tokenized for the given login and role.
+func (s *ThatClientSuite) assertTokenLink(link, login string, moderator bool) {
+	parsed, err := url.Parse(link)
+	s.Require().NoError(err)
+	s.Equal("/bd28ee142ca5b46259f6e27fc3a4216f", parsed.Path)
+	claims, err := jitsi.Verify(parsed.Query().Get("jwt"), "app_secret")
+	s.Require().NoError(err)
+	s.Equal("bd28ee142ca5b46259f6e27fc3a4216f", claims.Room)
+	s.Equal("app_id", claims.Issuer)
+	s.Equal(login, claims.Context.User.ID)
+	s.Equal(moderator, claims.Moderator)
+}
+
+func (s *ThatClientSuite) Test00_GetHealth() {
+	health, err := s.client.GetHealth()
+	s.Require().NoError(err)
+	s.Equal(map[string]interface{}{"status": "ok"}, health, "the health endpoint answers 200 with its status")
+}
+
+func (s *ThatClientSuite) Test01_GetHealth_Unhealthy() {
+	s.api.SetHealthy(false)
+
+	health, err := s.client.GetHealth()
+	s.Error(err)
+	s.Nil(health)
+}
+
+func (s *ThatClientSuite) Test02_SendNotification() {
+	participants := []room.Participant{{Login: "xlogin", Moderator: true}}
+
+	s.Require().NoError(s.client.SendNotification(1, time.Now(), meeting, participants))
+	messages := s.api.Messages()
+	s.Require().Len(messages, 1)
+	s.Equal("testWorkspace", messages[0].Workspace)
+	s.Equal([]string{"xlogin@student.42campus.org"}, messages[0].UserEmails)
+	s.Equal(meeting.URL, messages[0].Attachments[0].TitleLink)
+}
+
+func (s *ThatClientSuite) Test03_SendNotification_JWT() {
+	config.Conf.Jitsi.JWT.Enabled = true
+	participants := []room.Participant{{Login: "xlogin", Moderator: true}, {Login: "ylogin"}}
+
+	s.Require().NoError(s.client.SendNotification(1, time.Now(), meeting, participants))
+	messages := s.api.Messages()
+	s.Require().Len(messages, 2)
+	s.Equal([]string{"xlogin@student.42campus.org"}, messages[0].UserEmails)
+	s.assertTokenLink(messages[0].Attachments[0].TitleLink, "xlogin", true)
+	s.Equal([]string{"ylogin@student.42campus.org"}, messages[1].UserEmails)
+	s.assertTokenLink(messages[1].Attachments[0].TitleLink, "ylogin", false)
+}
+
+func (s *ThatClientSuite) Test04_SendNotification_ServerError() {
+	participants := []room.Participant{{Login: "xlogin", Moderator: true}}
+
+	// The messages are not retried on a server error, as slack_that may have posted them anyway.
+	s.api.Fail(1, http.StatusBadGateway)
+	s.Error(s.client.SendNotification(1, time.Now(), meeting, participants))
+	s.Empty(s.api.Messages())
+	s.Equal(1, s.api.Posts())
+}
+
+func (s *ThatClientSuite) Test05_SendNotification_Unreachable() {
+	participants := []room.Participant{{Login: "xlogin", Moderator: true}}
+	baseURL, err := url.Parse(s.server.URL)
+	s.Require().NoError(err)
+	transport := &countingTransport{}
+	client := &slack.ThatClient{
+		HTTPClient: &http.Client{Transport: resilient.NewTransport(transport, resilient.BackoffOption(time.Millisecond, time.Millisecond))},
+		Intra:      s.intra,
+		BaseURL:    baseURL,
+	}
+	s.server.Close()
+
+	// The messages which could not be sent at all are retried.
+	s.Error(client.SendNotification(1, time.Now(), meeting, participants))
+	s.Equal(int32(1+resilient.DefaultMaxRetries), atomic.LoadInt32(&transport.count))
+}
+
+func (s *ThatClientSuite) Test06_SendNotification_NoParticipants() {
+	err := s.client.SendNotification(1, time.Now(), meeting, nil)
+	s.Error(err)
+	s.True(errors.Is(err, slack.NoParticipantsError))
+	s.Zero(s.api.Posts())
+}
+
+func (s *ThatClientSuite) Test07_SendNotification_UnknownUser() {
+	participants := []room.Participant{{Login: "xlogin", Moderator: true}, {Login: "unknownlogin"}}
+
+	// The other participants are notified anyway.
+	s.Require().NoError(s.client.SendNotification(1, time.Now(), meeting, participants))
+	messages := s.api.Messages()
+	s.Require().Len(messages, 1)
+	s.Equal([]string{"xlogin@student.42campus.org"}, messages[0].UserEmails)
+
+	err := s.client.SendNotification(1, time.Now(), meeting, []room.Participant{{Login: "unknownlogin"}})
+	s.Require().Error(err)
+	var notFoundErr *intra.UsersNotFoundError
+	s.Require().True(errors.As(err, &notFoundErr))
+	s.Equal([]string{"unknownlogin"}, notFoundErr.Logins)
+	s.Equal(1, s.api.Posts())
+}
+
+func (s *ThatClientSuite) Test08_SendNotification_JWT_PartialFailure() {
+	config.Conf.Jitsi.JWT.Enabled = true
+	participants := []room.Participant{{Login: "xlogin", Moderator: true}, {Login: "ylogin"}}
+
+	// The other participants are messaged anyway, but the evaluation is not notified until they all are.
+	s.api.Fail(1, http.StatusBadRequest)
+	err := s.client.SendNotification(1, time.Now(), meeting, participants)
+	var undeliveredErr *slack.UndeliveredError
+	s.Require().True(errors.As(err, &undeliveredErr))
+	s.Equal([]string{"xlogin"}, undeliveredErr.Logins)
+	messages := s.api.Messages()
+	s.Require().Len(messages, 1)
+	s.Equal([]string{"ylogin@student.42campus.org"}, messages[0].UserEmails)
+}
+
+func (s *ThatClientSuite) Test09_SendNotification_JWT_Failure() {
+	config.Conf.Jitsi.JWT.Enabled = true
+	participants := []room.Participant{{Login: "xlogin", Moderator: true}, {Login: "ylogin"}}
+
+	s.api.Fail(2, http.StatusBadRequest)
+	err := s.client.SendNotification(1, time.Now(), meeting, participants)
+	var undeliveredErr *slack.UndeliveredError
+	s.Require().True(errors.As(err, &undeliveredErr))
+	s.Equal([]string{"xlogin", "ylogin"}, undeliveredErr.Logins)
+	s.Empty(s.api.Messages())
+	s.Equal(2, s.api.Posts())
+}
+
+func (s *ThatClientSuite) Test10_SendCancellation() {
+	participants := []room.Participant{{Login: "xlogin", Moderator: true}, {Login: "ylogin"}}
+
+	s.Require().NoError(s.client.SendCancellation(1, time.Now(), participants))
+	messages := s.api.Messages()
+	s.Require().Len(messages, 1)
+	s.Equal("testWorkspace", messages[0].Workspace)
+	s.Equal([]string{"xlogin@student.42campus.org", "ylogin@student.42campus.org"}, messages[0].UserEmails)
+	s.Contains(messages[0].Text, "has been cancelled")
+}
+
+func (s *ThatClientSuite) Test11_SendCancellation_NoParticipants() {
+	err := s.client.SendCancellation(1, time.Now(), nil)
+	s.Error(err)
+	s.True(errors.Is(err, slack.NoParticipantsError))
+}
+
+func (s *ThatClientSuite) Test12_SendReschedule() {
+	participants := []room.Participant{{Login: "xlogin", Moderator: true}, {Login: "ylogin"}}
+
+	s.Require().NoError(s.client.SendReschedule(1, time.Now(), time.Now().Add(time.Hour), participants))
+	messages := s.api.Messages()
+	s.Require().Len(messages, 1)
+	s.Equal([]string{"xlogin@student.42campus.org", "ylogin@student.42campus.org"}, messages[0].UserEmails)
+	s.Contains(messages[0].Text, "was moved to")
+}
+
+func (s *ThatClientSuite) Test13_SendNoShowReport() {
+	noShows := []room.Participant{{Login: "xlogin", Moderator: true}}
+
+	s.Require().NoError(s.client.SendNoShowReport("#staff", 1, time.Now(), noShows))
+	messages := s.api.Messages()
+	s.Require().Len(messages, 1)
+	s.Equal("testWorkspace", messages[0].Workspace)
+	s.Equal("#staff", messages[0].Channel)
+	s.Empty(messages[0].UserEmails)
+}
+
+func (s *ThatClientSuite) Test14_SendNoShowReport_NoParticipants() {
+	err := s.client.SendNoShowReport("#staff", 1, time.Now(), nil)
+	s.Error(err)
+	s.True(errors.Is(err, slack.NoParticipantsError))
+}
+
+func (s *ThatClientSuite) Test15_Notifier() {
+	config.Conf.SlackThat.URL = s.server.URL
+	config.Conf.SlackThat.StaffChannel = "#staff"
+	defer func() {
+		config.Conf.SlackThat.URL = ""
+		config.Conf.SlackThat.StaffChannel = ""
+	}()
+	n, err := notify.New(slack.SlackThatNotifier, s.intra)
+	s.Require().NoError(err)
+
+	beginAt := time.Date(2020, 7, 15, 21, 0, 0, 0, time.UTC)
+	participants := []notify.Participant{{Login: "xlogin", Role: notify.Corrector}, {Login: "ylogin", Role: notify.Corrected}}
+	s.Require().NoError(n.Notify(notify.Message{Kind: notify.Cancel, ScaleTeamID: 21, BeginAt: beginAt, Participants: participants}))
+	s.Require().NoError(n.Notify(notify.Message{Kind: notify.NoShow, ScaleTeamID: 21, BeginAt: beginAt, Participants: participants[1:]}))
+
+	messages := s.api.Messages()
+	s.Require().Len(messages, 2)
+	s.Equal([]string{"xlogin@student.42campus.org", "ylogin@student.42campus.org"}, messages[0].UserEmails)
+	s.Equal("#staff", messages[1].Channel)
+	s.Contains(messages[1].Text, "corrected ylogin")
+}
+
+func (s *ThatClientSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *ThatClientSuite) TearDownSuite() {
+	s.intraServer.Close()
+	config.Conf.Intra.URL = ""
+	config.Conf.SlackThat = config.SlackThatConfig{}
+	config.Conf.Jitsi.JWT = config.JitsiJWT{}
+}
@@ -2,19 +2,56 @@ package slack
 
 import "github.com/gustavobelfort/42-jitsi/internal/config"
 
+// noticeTimeLayout is the layout of the beginning of the evaluations in the cancellation and reschedule notices.
+const noticeTimeLayout = "2006-01-02 at 15:04 UTC"
+
 func defaultPostMessageParameters() *PostMessageParameters {
 	return &PostMessageParameters{
-		Text:      "This is the link for your evaluation that will take place in 15 minutes.",
-		Username:  config.Conf.SlackThat.Username,
-		Workspace: config.Conf.SlackThat.Workspace,
-		Attachments: []Attachment{
-			{
-				Title:     "42 Evaluation",
-				Pretext:   "Make sure to arrive on time and follow the remote correction guidelines !",
-				TitleLink: "https://meet.jit.si/correction",
-				Color:     "#36a64f",
-			},
+		Text:        "This is the link for your evaluation.",
+		Username:    config.Conf.SlackThat.Username,
+		Workspace:   config.Conf.SlackThat.Workspace,
+		Attachments: notificationAttachments(),
+	}
+
+}
+
+func notificationAttachments() []Attachment {
+	return []Attachment{
+		{
+			Title:     "42 Evaluation",
+			Pretext:   "Make sure to arrive on time and follow the remote correction guidelines !",
+			TitleLink: config.Conf.Jitsi.URL,
+			Color:     "#36a64f",
+		},
+	}
+}
+
+func cancellationAttachments() []Attachment {
+	return []Attachment{
+		{
+			Title:   "42 Evaluation cancelled",
+			Pretext: "The link you may have received for this evaluation will not be used. You can leave the room.",
+			Color:   "#e01e5a",
+		},
+	}
+}
+
+func rescheduleAttachments() []Attachment {
+	return []Attachment{
+		{
+			Title:   "42 Evaluation rescheduled",
+			Pretext: "You will receive the link again before the evaluation begins.",
+			Color:   "#ecb22e",
 		},
 	}
+}
 
+func noShowAttachments() []Attachment {
+	return []Attachment{
+		{
+			Title:   "42 Evaluation no-show",
+			Pretext: "The attendance was checked from the events of the evaluation's meeting.",
+			Color:   "#e01e5a",
+		},
+	}
 }
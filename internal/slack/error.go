@@ -0,0 +1,41 @@
+package slack
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// NoParticipantsError is returned when trying to notify an evaluation without any participant.
+	NoParticipantsError = errors.New("the evaluation does not have any participant to notify")
+	// MissingTokenError is returned when the Slack Web API is used without any bot token configured.
+	MissingTokenError = errors.New("the slack bot token is not configured")
+)
+
+// APIError is returned when the Slack Web API answers a call with an error.
+type APIError struct {
+	Method string
+	Code   string
+}
+
+// Error formats the APIError with the called method and the error code of the API, e.g: "users_not_found".
+func (err *APIError) Error() string {
+	return fmt.Sprintf("slack api %s failed: %s", err.Method, err.Code)
+}
+
+// UndeliveredError is returned when some participants of an evaluation could not be sent their own message.
+type UndeliveredError struct {
+	Logins []string
+	// Errors holds the error of each participant who was not sent their message, by login.
+	Errors map[string]error
+}
+
+// Error formats the UndeliveredError with the error of each participant who was not sent their message.
+func (err *UndeliveredError) Error() string {
+	undelivered := make([]string, len(err.Logins))
+	for i, login := range err.Logins {
+		undelivered[i] = fmt.Sprintf("%s: %v", login, err.Errors[login])
+	}
+	return fmt.Sprintf("could not message every participant: %s", strings.Join(undelivered, "; "))
+}
@@ -1,6 +1,12 @@
 package slack
 
-import "bytes"
+import (
+	"bytes"
+	"time"
+
+	"github.com/gustavobelfort/42-jitsi/internal/db"
+	"github.com/gustavobelfort/42-jitsi/internal/room"
+)
 
 // PostMessageParameters is the structure used to create the PostMessage request's body.
 type PostMessageParameters struct {
@@ -32,6 +38,9 @@ type PostMessageOptions func(parameters *PostMessageParameters)
 
 // SlackThat will allow you to make prepared request to a slack_that server.
 type SlackThat interface {
-	SendNotification(scaleTeamID int, logins []string) error
+	SendNotification(scaleTeamID int, beginAt time.Time, meeting db.Meeting, participants []room.Participant) error
+	SendCancellation(scaleTeamID int, beginAt time.Time, participants []room.Participant) error
+	SendReschedule(scaleTeamID int, from, to time.Time, participants []room.Participant) error
+	SendNoShowReport(channel string, scaleTeamID int, beginAt time.Time, noShows []room.Participant) error
 	GetHealth() (map[string]interface{}, error)
 }
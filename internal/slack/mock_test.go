@@ -0,0 +1,34 @@
+package slack
+
+import (
+	"time"
+
+	"github.com/gustavobelfort/42-jitsi/internal/db"
+	"github.com/gustavobelfort/42-jitsi/internal/room"
+	"github.com/stretchr/testify/mock"
+)
+
+type SlackThatMock struct {
+	mock.Mock
+}
+
+func (m *SlackThatMock) GetHealth() (map[string]interface{}, error) {
+	toReturn := m.Called()
+	return toReturn.Get(0).(map[string]interface{}), toReturn.Error(1)
+}
+
+func (m *SlackThatMock) SendNotification(scaleTeamID int, beginAt time.Time, meeting db.Meeting, participants []room.Participant) error {
+	return m.Called(scaleTeamID, beginAt, meeting, participants).Error(0)
+}
+
+func (m *SlackThatMock) SendCancellation(scaleTeamID int, beginAt time.Time, participants []room.Participant) error {
+	return m.Called(scaleTeamID, beginAt, participants).Error(0)
+}
+
+func (m *SlackThatMock) SendReschedule(scaleTeamID int, from, to time.Time, participants []room.Participant) error {
+	return m.Called(scaleTeamID, from, to, participants).Error(0)
+}
+
+func (m *SlackThatMock) SendNoShowReport(channel string, scaleTeamID int, beginAt time.Time, noShows []room.Participant) error {
+	return m.Called(channel, scaleTeamID, beginAt, noShows).Error(0)
+}
@@ -2,31 +2,119 @@ package slack
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
 	"strings"
+	"time"
 
+	"github.com/gustavobelfort/42-jitsi/internal/config"
+	"github.com/gustavobelfort/42-jitsi/internal/db"
+	"github.com/gustavobelfort/42-jitsi/internal/intra"
 	"github.com/gustavobelfort/42-jitsi/internal/logging"
+	"github.com/gustavobelfort/42-jitsi/internal/room"
 	"github.com/sirupsen/logrus"
 )
 
-// SendNotification sends a notification to multiple users containing a link to a meet.jit.si server
-func (client *ThatClient) SendNotification(scaleTeamID int, logins []string) error {
+// SendNotification sends a notification to the participants of an evaluation containing the link to its meeting.
+//
+// If the jitsi token authentication is enabled, each participant receives their own tokenized link in a direct
+// message. Otherwise, a single message is sent to every participant.
+func (client *ThatClient) SendNotification(scaleTeamID int, beginAt time.Time, meeting db.Meeting, participants []room.Participant) error {
+	if len(participants) == 0 {
+		return logging.WithLog(NoParticipantsError, logrus.WarnLevel, logrus.Fields{"scale_team_id": scaleTeamID})
+	}
 
 	logrus.WithField("scale_team_id", scaleTeamID).Info("getting scale team users' emails")
-	userEmails, err := client.getUserEmails(logins)
-	roomName := formatRoomName(scaleTeamID, logins)
-	ctxfields := logrus.Fields{
-		"scale_team_id": scaleTeamID,
-		"room_name":     roomName,
+	participants, err := fillEmails(client.Intra, participants)
+	if err != nil {
+		return logging.WithLog(err, logrus.ErrorLevel, logrus.Fields{"scale_team_id": scaleTeamID})
+	}
+	links, err := room.Links(meeting, beginAt, participants)
+	if err != nil {
+		return logging.WithLog(err, logrus.ErrorLevel, logrus.Fields{"scale_team_id": scaleTeamID})
+	}
+
+	if !config.Conf.Jitsi.JWT.Enabled {
+		userEmails := make([]string, len(participants))
+		for i, participant := range participants {
+			userEmails[i] = participant.Email
+		}
+		return client.sendLink(scaleTeamID, beginAt, userEmails, links[participants[0].Login])
+	}
+
+	return sendEach(scaleTeamID, participants, func(_ int, participant room.Participant) error {
+		return client.sendLink(scaleTeamID, beginAt, []string{participant.Email}, links[participant.Login])
+	})
+}
+
+// SendCancellation notifies the participants of an evaluation that it was cancelled.
+func (client *ThatClient) SendCancellation(scaleTeamID int, beginAt time.Time, participants []room.Participant) error {
+	return client.sendNotice(scaleTeamID, participants, cancellationText(beginAt), cancellationAttachments())
+}
+
+// SendReschedule notifies the participants of an evaluation that it was moved from `from` to `to`.
+func (client *ThatClient) SendReschedule(scaleTeamID int, from, to time.Time, participants []room.Participant) error {
+	return client.sendNotice(scaleTeamID, participants, rescheduleText(from, to), rescheduleAttachments())
+}
+
+// SendNoShowReport reports to the channel the participants of an evaluation who never joined its meeting.
+func (client *ThatClient) SendNoShowReport(channel string, scaleTeamID int, beginAt time.Time, noShows []room.Participant) error {
+	ctxfields := logrus.Fields{"scale_team_id": scaleTeamID}
+	if len(noShows) == 0 {
+		return logging.WithLog(NoParticipantsError, logrus.WarnLevel, ctxfields)
 	}
+
+	logrus.WithFields(ctxfields).Info("posting no-show report to slack_that")
+	if err := client.postMessage(
+		PostMessageChannelOption(channel),
+		PostMessageTextOption(noShowText(scaleTeamID, beginAt, noShows)),
+		PostMessageAttachmentsOption(noShowAttachments()),
+	); err != nil {
+		return logging.WithLog(err, logrus.ErrorLevel, ctxfields)
+	}
+	return nil
+}
+
+// sendNotice sends a single message without any link to every participant of an evaluation.
+func (client *ThatClient) sendNotice(scaleTeamID int, participants []room.Participant, text string, attachments []Attachment) error {
+	ctxfields := logrus.Fields{"scale_team_id": scaleTeamID}
+	if len(participants) == 0 {
+		return logging.WithLog(NoParticipantsError, logrus.WarnLevel, ctxfields)
+	}
+
+	logrus.WithFields(ctxfields).Info("getting scale team users' emails")
+	participants, err := fillEmails(client.Intra, participants)
 	if err != nil {
 		return logging.WithLog(err, logrus.ErrorLevel, ctxfields)
 	}
+	userEmails := make([]string, len(participants))
+	for i, participant := range participants {
+		userEmails[i] = participant.Email
+	}
+
+	logrus.WithFields(ctxfields).Info("posting notice to slack_that")
+	if err := client.postMessage(
+		PostMessageUserEmailsOption(userEmails),
+		PostMessageTextOption(text),
+		PostMessageAttachmentsOption(attachments),
+	); err != nil {
+		return logging.WithLog(err, logrus.ErrorLevel, ctxfields)
+	}
+	return nil
+}
+
+func (client *ThatClient) sendLink(scaleTeamID int, beginAt time.Time, userEmails []string, link string) error {
+	ctxfields := logrus.Fields{
+		"scale_team_id": scaleTeamID,
+		"room_link":     link,
+	}
 
 	logrus.WithFields(ctxfields).Info("posting message to slack_that")
 	if err := client.postMessage(
 		PostMessageUserEmailsOption(userEmails),
-		PostMessageLinkOption(roomName),
+		PostMessageLinkOption(link),
+		PostMessageTextOption(notificationText(beginAt, time.Now())),
 	); err != nil {
 		return logging.WithLog(err, logrus.ErrorLevel, ctxfields)
 	}
@@ -34,21 +122,119 @@ func (client *ThatClient) SendNotification(scaleTeamID int, logins []string) err
 	return nil
 }
 
-func (client *ThatClient) getUserEmails(logins []string) ([]string, error) {
-	var userEmails []string
-	for _, login := range logins {
-		email, err := client.Intra.GetUserEmail(context.Background(), login)
-		if err != nil {
-			return nil, err
+// sendEach sends their own message to each participant, whose recipients must all have been resolved beforehand.
+//
+// Every participant is sent their message even if another one failed, but the evaluation only counts as notified once
+// all of them received it: the error is then an *UndeliveredError, so that the reminder is retried.
+func sendEach(scaleTeamID int, participants []room.Participant, send func(i int, participant room.Participant) error) error {
+	var undelivered *UndeliveredError
+	for i, participant := range participants {
+		if err := send(i, participant); err != nil {
+			if undelivered == nil {
+				undelivered = &UndeliveredError{Errors: make(map[string]error)}
+			}
+			undelivered.Logins = append(undelivered.Logins, participant.Login)
+			undelivered.Errors[participant.Login] = err
 		}
-		userEmails = append(userEmails, email)
 	}
-	return userEmails, nil
+	if undelivered != nil {
+		return logging.WithLog(undelivered, logrus.ErrorLevel, logrus.Fields{"scale_team_id": scaleTeamID})
+	}
+	return nil
 }
 
-func formatRoomName(scaleTeamID int, logins []string) string {
-	jitsiServer := "https://meet.jit.si/"
-	usernames := strings.Join(logins, "-")
+// fillEmails returns the participants with their email, all looked up with a single request.
+//
+// The participants whose email could not be looked up are left out, unless none of them could.
+func fillEmails(intraClient intra.Client, participants []room.Participant) ([]room.Participant, error) {
+	logins := make([]string, len(participants))
+	for i, participant := range participants {
+		logins[i] = participant.Login
+	}
+	emails, err := intraClient.GetUserEmails(context.Background(), logins)
+	var notFoundErr *intra.UsersNotFoundError
+	if err != nil && !errors.As(err, &notFoundErr) {
+		return nil, err
+	}
 
-	return fmt.Sprintf("%s%d-", jitsiServer, scaleTeamID) + usernames
+	filled := make([]room.Participant, 0, len(participants))
+	for _, participant := range participants {
+		email, ok := emails[participant.Login]
+		if !ok {
+			continue
+		}
+		participant.Email = email
+		filled = append(filled, participant)
+	}
+	if len(filled) == 0 {
+		if err == nil {
+			err = NoParticipantsError
+		}
+		return nil, err
+	}
+	if err != nil {
+		logging.LogError(logrus.StandardLogger(), err, "leaving out the participants without any email")
+	}
+	return filled, nil
+}
+
+// notificationText formats the text of the notification depending on how long before the evaluation it is sent.
+func notificationText(beginAt, now time.Time) string {
+	minutes := int(math.Round(beginAt.Sub(now).Minutes()))
+	if minutes <= 0 {
+		return "This is the link for your evaluation that is starting now."
+	}
+	return fmt.Sprintf("This is the link for your evaluation that will take place in %s.", formatMinutes(minutes))
+}
+
+// cancellationText formats the text of the cancellation notice of an evaluation beginning at `beginAt`.
+func cancellationText(beginAt time.Time) string {
+	return fmt.Sprintf("Your evaluation planned on %s has been cancelled.", beginAt.UTC().Format(noticeTimeLayout))
+}
+
+// rescheduleText formats the text of the notice of an evaluation moved from `from` to `to`.
+func rescheduleText(from, to time.Time) string {
+	return fmt.Sprintf(
+		"Your evaluation planned on %s was moved to %s.",
+		from.UTC().Format(noticeTimeLayout),
+		to.UTC().Format(noticeTimeLayout),
+	)
+}
+
+// noShowText formats the text of the report of the participants who never joined the evaluation `scaleTeamID`.
+func noShowText(scaleTeamID int, beginAt time.Time, noShows []room.Participant) string {
+	absentees := make([]string, len(noShows))
+	for i, noShow := range noShows {
+		status := db.Corrected
+		if noShow.Moderator {
+			status = db.Corrector
+		}
+		absentees[i] = fmt.Sprintf("%s %s", status, noShow.Login)
+	}
+	return fmt.Sprintf(
+		"The evaluation %d planned on %s was missed by: %s.",
+		scaleTeamID,
+		beginAt.UTC().Format(noticeTimeLayout),
+		strings.Join(absentees, ", "),
+	)
+}
+
+// formatMinutes formats a positive number of minutes in hours and minutes, e.g: "1 hour and 5 minutes".
+func formatMinutes(minutes int) string {
+	plural := func(n int, unit string) string {
+		if n == 1 {
+			return fmt.Sprintf("1 %s", unit)
+		}
+		return fmt.Sprintf("%d %ss", n, unit)
+	}
+
+	hours, minutes := minutes/60, minutes%60
+	switch {
+	case hours == 0:
+		return plural(minutes, "minute")
+	case minutes == 0:
+		return plural(hours, "hour")
+	default:
+		return fmt.Sprintf("%s and %s", plural(hours, "hour"), plural(minutes, "minute"))
+	}
 }
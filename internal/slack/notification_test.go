@@ -2,55 +2,113 @@ package slack
 
 import (
 	"context"
+	"net/url"
+	"strings"
 	"testing"
+	"time"
 
-	"github.com/gustavobelfort/42-jitsi/internal/config"
+	"github.com/gustavobelfort/42-jitsi/internal/db"
+	"github.com/gustavobelfort/42-jitsi/internal/intra"
+	"github.com/gustavobelfort/42-jitsi/internal/jitsi"
+	"github.com/gustavobelfort/42-jitsi/internal/room"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
-	"github.com/stretchr/testify/suite"
 )
 
-func TestSlackClient(t *testing.T) {
-	suite.Run(t, new(SlackClientSuite))
+var meeting = db.Meeting{
+	Provider: "jitsi",
+	RoomName: "bd28ee142ca5b46259f6e27fc3a4216f",
+	URL:      "https://meet.jit.si/bd28ee142ca5b46259f6e27fc3a4216f",
 }
 
-type SlackClientSuite struct {
-	suite.Suite
+type IntraMock struct {
+	mock.Mock
+}
 
-	mock   *ServerMock
-	client SlackThat
+func (m *IntraMock) GetTeamMembers(ctx context.Context, teamID int) ([]string, error) {
+	return nil, nil
 }
 
-type IntraMock struct {
-	mock.Mock
+func (m *IntraMock) GetUserEmail(ctx context.Context, login string) (string, error) {
+	return login + "@student.42campus.org", nil
+}
+
+// GetUserEmails returns the emails of the users, except the ones whose login begins with "unknown".
+func (m *IntraMock) GetUserEmails(ctx context.Context, logins []string) (map[string]string, error) {
+	emails := make(map[string]string, len(logins))
+	var unknown []string
+	for _, login := range logins {
+		if strings.HasPrefix(login, "unknown") {
+			unknown = append(unknown, login)
+			continue
+		}
+		emails[login] = login + "@student.42campus.org"
+	}
+	if len(unknown) > 0 {
+		return emails, &intra.UsersNotFoundError{Logins: unknown}
+	}
+	return emails, nil
 }
 
-func (m IntraMock) GetTeamMembers(ctx context.Context, teamID int) ([]string, error) {
+func (m *IntraMock) ListScaleTeams(ctx context.Context, campusID int, from, to time.Time) ([]intra.ScaleTeam, error) {
 	return nil, nil
 }
 
-func (m IntraMock) GetUserEmail(ctx context.Context, login string) (string, error) {
-	return "nil", nil
+func (m *IntraMock) GetScaleTeam(ctx context.Context, id int) (*intra.ScaleTeam, error) {
+	return nil, nil
 }
 
-func (s *SlackClientSuite) SetupSuite() {
-	s.Require().Implements((*SlackThat)(nil), &ThatClient{})
+// tokenLink returns a matcher verifying that the link is tokenized for the given login and role.
+func tokenLink(login string, moderator bool) interface{} {
+	return mock.MatchedBy(func(link string) bool {
+		parsed, err := url.Parse(link)
+		if err != nil || parsed.Path != "/bd28ee142ca5b46259f6e27fc3a4216f" {
+			return false
+		}
+		claims, err := jitsi.Verify(parsed.Query().Get("jwt"), "app_secret")
+		if err != nil {
+			return false
+		}
+		return claims.Room == "bd28ee142ca5b46259f6e27fc3a4216f" &&
+			claims.Issuer == "app_id" &&
+			claims.Context.User.ID == login &&
+			claims.Moderator == moderator
+	})
+}
 
-	config.Conf.SlackThat.Workspace = "testWorkspace"
-	s.mock = NewServerMock()
+func TestNotificationText(t *testing.T) {
+	now := time.Now()
 
-	client, err := New(IntraMock{}, s.mock.Server.URL)
-	s.client = client.(*ThatClient)
-	s.Require().NoError(err)
-	s.Require().NotNil(s.client)
+	tests := map[time.Duration]string{
+		-time.Minute:                "This is the link for your evaluation that is starting now.",
+		time.Second * 20:            "This is the link for your evaluation that is starting now.",
+		time.Minute:                 "This is the link for your evaluation that will take place in 1 minute.",
+		time.Minute * 15:            "This is the link for your evaluation that will take place in 15 minutes.",
+		time.Hour:                   "This is the link for your evaluation that will take place in 1 hour.",
+		time.Hour*2 + time.Minute*5: "This is the link for your evaluation that will take place in 2 hours and 5 minutes.",
+		time.Hour + time.Minute*59 + time.Second*40: "This is the link for your evaluation that will take place in 2 hours.",
+	}
+	for before, expected := range tests {
+		assert.Equal(t, expected, notificationText(now.Add(before), now), before.String())
+	}
+}
 
+func TestCancellationText(t *testing.T) {
+	beginAt := time.Date(2020, 7, 15, 23, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+
+	assert.Equal(t, "Your evaluation planned on 2020-07-15 at 21:00 UTC has been cancelled.", cancellationText(beginAt))
 }
 
-func (s *SlackClientSuite) Test00_SendNotification() {
+func TestRescheduleText(t *testing.T) {
+	from := time.Date(2020, 7, 15, 21, 0, 0, 0, time.UTC)
+	to := time.Date(2020, 7, 16, 9, 30, 0, 0, time.UTC)
+
+	assert.Equal(t, "Your evaluation planned on 2020-07-15 at 21:00 UTC was moved to 2020-07-16 at 09:30 UTC.", rescheduleText(from, to))
+}
 
-	expectedLogin := []string{"xlogin"}
-	expectedScaleTeamID := 1
+func TestNoShowText(t *testing.T) {
+	beginAt := time.Date(2020, 7, 15, 21, 0, 0, 0, time.UTC)
+	noShows := []room.Participant{{Login: "xlogin", Moderator: true}, {Login: "ylogin"}}
 
-	s.mock.On("SendNotification", expectedLogin, expectedScaleTeamID).Return(nil).Once()
-	err := s.client.SendNotification(1, expectedLogin)
-	s.NoError(err)
+	assert.Equal(t, "The evaluation 21 planned on 2020-07-15 at 21:00 UTC was missed by: corrector xlogin, corrected ylogin.", noShowText(21, beginAt, noShows))
 }
@@ -0,0 +1,79 @@
+package slack
+
+import (
+	"github.com/gustavobelfort/42-jitsi/internal/config"
+	"github.com/gustavobelfort/42-jitsi/internal/db"
+	"github.com/gustavobelfort/42-jitsi/internal/intra"
+	"github.com/gustavobelfort/42-jitsi/internal/notify"
+	"github.com/gustavobelfort/42-jitsi/internal/room"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// SlackThatNotifier is the notifier posting the messages through a slack_that deployment.
+	SlackThatNotifier = "slack_that"
+	// WebNotifier is the notifier posting the messages with the Slack Web API directly.
+	WebNotifier = "slack"
+)
+
+func init() {
+	notify.Register(SlackThatNotifier, func(intra intra.Client) (notify.Notifier, error) {
+		client, err := New(intra, config.Conf.SlackThat.URL)
+		if err != nil {
+			return nil, err
+		}
+		return NewNotifier(client, config.Conf.SlackThat.StaffChannel), nil
+	})
+	notify.Register(WebNotifier, func(intra intra.Client) (notify.Notifier, error) {
+		client, err := NewWebClient(intra, config.Conf.Slack.URL, config.Conf.Slack.Token)
+		if err != nil {
+			return nil, err
+		}
+		return NewNotifier(client, config.Conf.Slack.StaffChannel), nil
+	})
+}
+
+// notifier sends the messages about the evaluations with a slack client.
+type notifier struct {
+	client       SlackThat
+	staffChannel string
+}
+
+// NewNotifier returns a notifier sending the messages with the slack client. The no-show reports are sent to the staff
+// channel, and only if it is not empty.
+func NewNotifier(client SlackThat, staffChannel string) notify.Notifier {
+	return &notifier{client: client, staffChannel: staffChannel}
+}
+
+// Notify sends the message with the matching method of the slack client.
+func (n *notifier) Notify(message notify.Message) error {
+	participants := roomParticipants(message.Participants)
+	switch message.Kind {
+	case notify.Reminder:
+		return n.client.SendNotification(message.ScaleTeamID, message.BeginAt, db.Meeting(message.Meeting), participants)
+	case notify.Cancel:
+		return n.client.SendCancellation(message.ScaleTeamID, message.BeginAt, participants)
+	case notify.Reschedule:
+		return n.client.SendReschedule(message.ScaleTeamID, message.PreviousBeginAt, message.BeginAt, participants)
+	case notify.NoShow:
+		if n.staffChannel == "" {
+			logrus.WithField("scale_team_id", message.ScaleTeamID).Debug("no staff channel to report the no-shows to")
+			return nil
+		}
+		return n.client.SendNoShowReport(n.staffChannel, message.ScaleTeamID, message.BeginAt, participants)
+	default:
+		return &notify.UnsupportedKindError{Kind: message.Kind}
+	}
+}
+
+// roomParticipants returns the participants as joining the meeting, only the corrector being moderator.
+func roomParticipants(participants []notify.Participant) []room.Participant {
+	roomParticipants := make([]room.Participant, len(participants))
+	for i, participant := range participants {
+		roomParticipants[i] = room.Participant{
+			Login:     participant.Login,
+			Moderator: participant.Role == notify.Corrector,
+		}
+	}
+	return roomParticipants
+}
@@ -0,0 +1,119 @@
+package slack
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gustavobelfort/42-jitsi/internal/config"
+	"github.com/gustavobelfort/42-jitsi/internal/notify"
+	"github.com/gustavobelfort/42-jitsi/internal/room"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewFromConfig(t *testing.T) {
+	defer func() {
+		config.Conf.Notifier = ""
+		config.Conf.Slack = config.SlackConfig{}
+		config.Conf.SlackThat = config.SlackThatConfig{}
+	}()
+	config.Conf.SlackThat = config.SlackThatConfig{URL: "http://localhost:8080", StaffChannel: "#that-staff"}
+	config.Conf.Slack = config.SlackConfig{URL: "https://slack.com/api/", StaffChannel: "#web-staff"}
+
+	assert.Equal(t, []string{WebNotifier, SlackThatNotifier}, notify.Backends())
+
+	t.Run("SlackThat", func(t *testing.T) {
+		config.Conf.Notifier = SlackThatNotifier
+
+		n, err := notify.NewFromConfig(&IntraMock{})
+		require.NoError(t, err)
+		require.IsType(t, &notifier{}, n)
+		assert.IsType(t, &ThatClient{}, n.(*notifier).client)
+		assert.Equal(t, "#that-staff", n.(*notifier).staffChannel)
+	})
+
+	t.Run("Web", func(t *testing.T) {
+		config.Conf.Notifier = WebNotifier
+
+		_, err := notify.NewFromConfig(&IntraMock{})
+		assert.Equal(t, MissingTokenError, err)
+
+		config.Conf.Slack.Token = "xoxb-token"
+		n, err := notify.NewFromConfig(&IntraMock{})
+		require.NoError(t, err)
+		require.IsType(t, &notifier{}, n)
+		assert.IsType(t, &WebClient{}, n.(*notifier).client)
+		assert.Equal(t, "#web-staff", n.(*notifier).staffChannel)
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		config.Conf.Notifier = "email"
+
+		_, err := notify.NewFromConfig(&IntraMock{})
+		assert.Equal(t, &notify.UnknownNotifierError{Notifier: "email", Backends: []string{WebNotifier, SlackThatNotifier}}, err)
+	})
+}
+
+func TestNotifier(t *testing.T) {
+	beginAt := time.Date(2020, 7, 15, 21, 0, 0, 0, time.UTC)
+	previousBeginAt := beginAt.Add(-time.Hour)
+	participants := []notify.Participant{{Login: "xlogin", Role: notify.Corrector}, {Login: "ylogin", Role: notify.Corrected}}
+	roomParticipants := []room.Participant{{Login: "xlogin", Moderator: true}, {Login: "ylogin"}}
+
+	t.Run("Reminder", func(t *testing.T) {
+		client := &SlackThatMock{}
+		defer client.AssertExpectations(t)
+		client.On("SendNotification", 21, beginAt, meeting, roomParticipants).Return(nil).Once()
+
+		assert.NoError(t, NewNotifier(client, "").Notify(notify.Message{
+			Kind:         notify.Reminder,
+			ScaleTeamID:  21,
+			BeginAt:      beginAt,
+			Meeting:      notify.Meeting(meeting),
+			Participants: participants,
+		}))
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		client := &SlackThatMock{}
+		defer client.AssertExpectations(t)
+		client.On("SendCancellation", 21, beginAt, roomParticipants).Return(nil).Once()
+
+		assert.NoError(t, NewNotifier(client, "").Notify(notify.Message{
+			Kind:         notify.Cancel,
+			ScaleTeamID:  21,
+			BeginAt:      beginAt,
+			Participants: participants,
+		}))
+	})
+
+	t.Run("Reschedule", func(t *testing.T) {
+		client := &SlackThatMock{}
+		defer client.AssertExpectations(t)
+		client.On("SendReschedule", 21, previousBeginAt, beginAt, roomParticipants).Return(nil).Once()
+
+		assert.NoError(t, NewNotifier(client, "").Notify(notify.Message{
+			Kind:            notify.Reschedule,
+			ScaleTeamID:     21,
+			BeginAt:         beginAt,
+			PreviousBeginAt: previousBeginAt,
+			Participants:    participants,
+		}))
+	})
+
+	t.Run("NoShow", func(t *testing.T) {
+		client := &SlackThatMock{}
+		defer client.AssertExpectations(t)
+		client.On("SendNoShowReport", "#staff", 21, beginAt, roomParticipants[:1]).Return(nil).Once()
+
+		message := notify.Message{Kind: notify.NoShow, ScaleTeamID: 21, BeginAt: beginAt, Participants: participants[:1]}
+		assert.NoError(t, NewNotifier(client, "#staff").Notify(message))
+		// Without any staff channel, the no-shows are not reported.
+		assert.NoError(t, NewNotifier(client, "").Notify(message))
+	})
+
+	t.Run("UnsupportedKind", func(t *testing.T) {
+		err := NewNotifier(&SlackThatMock{}, "").Notify(notify.Message{Kind: "digest"})
+		assert.Equal(t, &notify.UnsupportedKindError{Kind: "digest"}, err)
+	})
+}
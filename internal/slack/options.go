@@ -7,9 +7,30 @@ func PostMessageUserEmailsOption(userEmails []string) PostMessageOptions {
 	}
 }
 
+// PostMessageChannelOption changes the channel in which to post the message.
+func PostMessageChannelOption(channel string) PostMessageOptions {
+	return func(parameters *PostMessageParameters) {
+		parameters.Channel = channel
+	}
+}
+
 // PostMessageLinkOption changes the users emails to whom post the message.
 func PostMessageLinkOption(link string) PostMessageOptions {
 	return func(parameters *PostMessageParameters) {
 		parameters.Attachments[0].TitleLink = link
 	}
 }
+
+// PostMessageTextOption changes the text of the message.
+func PostMessageTextOption(text string) PostMessageOptions {
+	return func(parameters *PostMessageParameters) {
+		parameters.Text = text
+	}
+}
+
+// PostMessageAttachmentsOption changes the attachments of the message.
+func PostMessageAttachmentsOption(attachments []Attachment) PostMessageOptions {
+	return func(parameters *PostMessageParameters) {
+		parameters.Attachments = attachments
+	}
+}
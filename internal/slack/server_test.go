@@ -1,44 +1,78 @@
 package slack
 
 import (
-	"encoding/json"
-	"net/http"
 	"net/http/httptest"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/mock"
 )
 
-type ServerMock struct {
+type WebServerMock struct {
 	mock.Mock
 
 	router *gin.Engine
 	Server *httptest.Server
 }
 
-func (m *ServerMock) initRouter() {
+func (m *WebServerMock) initRouter() {
 	m.router = gin.New()
 
-	m.router.POST("/", func(ctx *gin.Context) {
-		if !validateRequest(ctx.Request) {
+	m.router.Use(func(ctx *gin.Context) {
+		if ctx.GetHeader("Authorization") != "Bearer xoxb-token" {
+			ctx.AbortWithStatusJSON(200, gin.H{"ok": false, "error": "invalid_auth"})
+		}
+	})
+	m.router.GET("/auth.test", func(ctx *gin.Context) {
+		ctx.JSON(200, gin.H{"ok": true, "user_id": "UBOT"})
+	})
+	m.router.GET("/users.lookupByEmail", func(ctx *gin.Context) {
+		toReturn := m.MethodCalled("LookupByEmail", ctx.Query("email"))
+		m.respond(ctx, toReturn.String(0), gin.H{"user": gin.H{"id": toReturn.String(1)}})
+	})
+	m.router.POST("/conversations.open", func(ctx *gin.Context) {
+		var p struct {
+			Users string `json:"users"`
+		}
+		if err := ctx.ShouldBindJSON(&p); err != nil {
 			ctx.JSON(500, gin.H{})
+			return
 		}
-		ctx.JSON(201, gin.H{})
+		toReturn := m.MethodCalled("OpenConversation", p.Users)
+		m.respond(ctx, toReturn.String(0), gin.H{"channel": gin.H{"id": toReturn.String(1)}})
+	})
+	m.router.POST("/chat.postMessage", func(ctx *gin.Context) {
+		var p struct {
+			Channel string  `json:"channel"`
+			Text    string  `json:"text"`
+			Blocks  []block `json:"blocks"`
+		}
+		if err := ctx.ShouldBindJSON(&p); err != nil || p.Text == "" || len(p.Blocks) == 0 {
+			ctx.JSON(500, gin.H{})
+			return
+		}
+		link := ""
+		for _, b := range p.Blocks {
+			if b.Type == "actions" {
+				link = b.Elements[0].(map[string]interface{})["url"].(string)
+			}
+		}
+		toReturn := m.MethodCalled("PostMessage", p.Channel, link)
+		m.respond(ctx, toReturn.String(0), gin.H{})
 	})
 }
 
-func validateRequest(r *http.Request) bool {
-
-	var p PostMessageParameters
-	json.NewDecoder(r.Body).Decode(&p)
-	if p.Workspace != "testWorkspace" || p.Attachments[0].TitleLink != "https://meet.jit.si/1-xlogin" {
-		return false
+// respond answers like the Slack Web API, with the error code if it is not empty.
+func (m *WebServerMock) respond(ctx *gin.Context, code string, payload gin.H) {
+	if code != "" {
+		ctx.JSON(200, gin.H{"ok": false, "error": code})
+		return
 	}
-	return true
+	payload["ok"] = true
+	ctx.JSON(200, payload)
 }
 
-func NewServerMock() *ServerMock {
-	mock := &ServerMock{}
+func NewWebServerMock() *WebServerMock {
+	mock := &WebServerMock{}
 	mock.initRouter()
 	mock.Server = httptest.NewServer(mock.router)
 	return mock
@@ -0,0 +1,164 @@
+package slackthattest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gustavobelfort/42-jitsi/internal/slack"
+	"github.com/sirupsen/logrus"
+)
+
+// API is a fake of a slack_that server recording the messages posted to it, whose failures and latency can be
+// injected.
+type API struct {
+	handler http.Handler
+
+	mu        sync.Mutex
+	messages  []slack.PostMessageParameters
+	posts     int
+	failures  int
+	status    int
+	latency   time.Duration
+	unhealthy bool
+}
+
+// Option configures an API.
+type Option func(*API)
+
+// LatencyOption delays every response of the API.
+func LatencyOption(latency time.Duration) Option {
+	return func(api *API) {
+		api.latency = latency
+	}
+}
+
+// NewAPI returns a fake slack_that API.
+func NewAPI(options ...Option) *API {
+	api := &API{}
+	for _, option := range options {
+		option(api)
+	}
+
+	router := gin.New()
+	router.Use(gin.Recovery(), api.delay)
+	router.POST("/", api.postMessage)
+	router.GET("/health", api.health)
+	api.handler = router
+	return api
+}
+
+// NewServer starts a server serving a new fake API, which has to be closed.
+func NewServer(options ...Option) (*httptest.Server, *API) {
+	api := NewAPI(options...)
+	return httptest.NewServer(api), api
+}
+
+// ServeHTTP serves the request like slack_that would.
+func (api *API) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	api.handler.ServeHTTP(w, req)
+}
+
+// Messages returns the messages posted successfully, in the order they were received.
+func (api *API) Messages() []slack.PostMessageParameters {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	return append([]slack.PostMessageParameters(nil), api.messages...)
+}
+
+// Posts returns the number of messages posted, including the failed ones.
+func (api *API) Posts() int {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	return api.posts
+}
+
+// Reset forgets the posted messages and the injected failures.
+func (api *API) Reset() {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	api.messages, api.posts, api.failures, api.unhealthy = nil, 0, 0, false
+}
+
+// Fail answers the next `posts` messages with the status, e.g: 500 or 503, instead of posting them.
+func (api *API) Fail(posts, status int) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	api.failures, api.status = posts, status
+}
+
+// SetLatency delays every response of the API from now on. A latency longer than the clients' timeout makes their
+// requests time out.
+func (api *API) SetLatency(latency time.Duration) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	api.latency = latency
+}
+
+// SetHealthy makes the health endpoint report whether the API is healthy.
+func (api *API) SetHealthy(healthy bool) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	api.unhealthy = !healthy
+}
+
+// delay holds the requests for the API's latency.
+func (api *API) delay(ctx *gin.Context) {
+	api.mu.Lock()
+	latency := api.latency
+	api.mu.Unlock()
+
+	if latency <= 0 {
+		return
+	}
+	timer := time.NewTimer(latency)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Request.Context().Done():
+		ctx.Abort()
+	}
+}
+
+// postMessage records the message if it has recipients and a text, like slack_that would post it.
+func (api *API) postMessage(ctx *gin.Context) {
+	var params slack.PostMessageParameters
+	err := ctx.ShouldBindJSON(&params)
+
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	api.posts++
+
+	switch {
+	case api.failures > 0:
+		api.failures--
+		ctx.JSON(api.status, gin.H{"error": http.StatusText(api.status)})
+	case err != nil:
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case params.Channel == "" && len(params.UserEmails) == 0:
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "either channel or user_emails is required"})
+	case params.Text == "":
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "text is required"})
+	default:
+		api.messages = append(api.messages, params)
+		logrus.WithFields(logrus.Fields{
+			"channel":     params.Channel,
+			"user_emails": params.UserEmails,
+			"workspace":   params.Workspace,
+		}).Infof("slack_that message posted: %s", params.Text)
+		ctx.JSON(http.StatusCreated, gin.H{"ok": true})
+	}
+}
+
+func (api *API) health(ctx *gin.Context) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	if api.unhealthy {
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{"status": "ko"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
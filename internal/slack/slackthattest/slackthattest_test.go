@@ -0,0 +1,146 @@
+package slackthattest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gustavobelfort/42-jitsi/internal/config"
+	"github.com/gustavobelfort/42-jitsi/internal/db"
+	"github.com/gustavobelfort/42-jitsi/internal/intra"
+	"github.com/gustavobelfort/42-jitsi/internal/intra/intratest"
+	"github.com/gustavobelfort/42-jitsi/internal/room"
+	"github.com/gustavobelfort/42-jitsi/internal/slack"
+	"github.com/stretchr/testify/suite"
+)
+
+var meeting = db.Meeting{
+	Provider: "jitsi",
+	RoomName: "bd28ee142ca5b46259f6e27fc3a4216f",
+	URL:      "https://meet.jit.si/bd28ee142ca5b46259f6e27fc3a4216f",
+}
+
+func TestAPI(t *testing.T) {
+	suite.Run(t, new(APISuite))
+}
+
+type APISuite struct {
+	suite.Suite
+
+	intraServer *httptest.Server
+	intra       intra.Client
+
+	server *httptest.Server
+	api    *API
+	client slack.SlackThat
+}
+
+func (s *APISuite) SetupSuite() {
+	fixtures, err := intratest.LoadFixtures("../../intra/intratest/testdata/fixtures.json")
+	s.Require().NoError(err)
+	s.intraServer, _ = intratest.NewServer(fixtures)
+
+	config.Conf.Intra.URL = s.intraServer.URL
+	s.intra, err = intra.NewClient("id", "secret", s.intraServer.Client())
+	s.Require().NoError(err)
+
+	config.Conf.SlackThat.Workspace = "42campus"
+}
+
+func (s *APISuite) SetupTest() {
+	s.server, s.api = NewServer()
+
+	client, err := slack.New(s.intra, s.server.URL)
+	s.Require().NoError(err)
+	s.client = client
+}
+
+func (s *APISuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *APISuite) TearDownSuite() {
+	s.intraServer.Close()
+	config.Conf.Intra.URL = ""
+	config.Conf.SlackThat = config.SlackThatConfig{}
+}
+
+func (s *APISuite) Test00_SendNotification() {
+	participants := []room.Participant{{Login: "xcorrector", Moderator: true}, {Login: "xcorrected"}}
+	s.Require().NoError(s.client.SendNotification(1001, time.Now().Add(time.Minute*15), meeting, participants))
+
+	messages := s.api.Messages()
+	s.Require().Len(messages, 1)
+	s.Equal("42campus", messages[0].Workspace)
+	s.Equal([]string{"xcorrector@student.42.fr", "xcorrected@student.42.fr"}, messages[0].UserEmails)
+	s.Equal(meeting.URL, messages[0].Attachments[0].TitleLink)
+	s.Contains(messages[0].Text, "15 minutes")
+}
+
+func (s *APISuite) Test01_SendNoShowReport() {
+	s.Require().NoError(s.client.SendNoShowReport("#staff", 1001, time.Now(), []room.Participant{{Login: "xcorrected"}}))
+
+	messages := s.api.Messages()
+	s.Require().Len(messages, 1)
+	s.Equal("#staff", messages[0].Channel)
+	s.Empty(messages[0].UserEmails)
+}
+
+func (s *APISuite) Test02_Fail() {
+	participants := []room.Participant{{Login: "xcorrected"}}
+	s.api.Fail(2, http.StatusInternalServerError)
+
+	// The messages are not retried on a server error.
+	s.Error(s.client.SendCancellation(1001, time.Now(), participants))
+	s.Empty(s.api.Messages())
+	s.Equal(1, s.api.Posts())
+
+	s.Error(s.client.SendCancellation(1001, time.Now(), participants))
+	s.NoError(s.client.SendCancellation(1001, time.Now(), participants))
+	s.Len(s.api.Messages(), 1)
+	s.Equal(3, s.api.Posts())
+
+	s.api.Reset()
+	s.Empty(s.api.Messages())
+	s.Zero(s.api.Posts())
+}
+
+func (s *APISuite) Test03_Latency() {
+	baseURL, err := url.Parse(s.server.URL)
+	s.Require().NoError(err)
+	client := &slack.ThatClient{
+		HTTPClient: &http.Client{Timeout: time.Millisecond * 50},
+		Intra:      s.intra,
+		BaseURL:    baseURL,
+	}
+	participants := []room.Participant{{Login: "xcorrected"}}
+
+	s.api.SetLatency(time.Millisecond * 200)
+	s.Error(client.SendCancellation(1001, time.Now(), participants))
+
+	s.api.SetLatency(0)
+	s.NoError(client.SendCancellation(1001, time.Now(), participants))
+}
+
+func (s *APISuite) Test04_InvalidMessage() {
+	req, err := http.NewRequest(http.MethodPost, s.server.URL, &slack.PostMessageParameters{Text: "no recipient"})
+	s.Require().NoError(err)
+	resp, err := http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	resp.Body.Close()
+
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+	s.Empty(s.api.Messages())
+}
+
+func (s *APISuite) Test05_GetHealth() {
+	health, err := s.client.GetHealth()
+	s.Require().NoError(err)
+	s.Equal(map[string]interface{}{"status": "ok"}, health)
+
+	s.api.SetHealthy(false)
+	_, err = s.client.GetHealth()
+	s.Error(err)
+}
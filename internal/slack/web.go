@@ -0,0 +1,277 @@
+package slack
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gustavobelfort/42-jitsi/internal/config"
+	"github.com/gustavobelfort/42-jitsi/internal/db"
+	"github.com/gustavobelfort/42-jitsi/internal/intra"
+	"github.com/gustavobelfort/42-jitsi/internal/logging"
+	"github.com/gustavobelfort/42-jitsi/internal/resilient"
+	"github.com/gustavobelfort/42-jitsi/internal/room"
+	"github.com/sirupsen/logrus"
+)
+
+// WebClient notifies the participants of the evaluations with the Slack Web API directly, without slack_that.
+//
+// The participants of an evaluation share a multi-person conversation with the bot, unless the jitsi token
+// authentication is enabled: each participant then receives their own link in a direct message.
+type WebClient struct {
+	HTTPClient *http.Client
+	Intra      intra.Client
+	BaseURL    *url.URL
+	Token      string
+}
+
+// webResponse is the envelope of every response of the Slack Web API.
+type webResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error"`
+}
+
+// NewWebClient initiates a Slack Web API client ready to make requests to the base_url passed with the bot token.
+func NewWebClient(intra intra.Client, baseURL, token string) (SlackThat, error) {
+	if token == "" {
+		return nil, MissingTokenError
+	}
+	parsedURL, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WebClient{
+		HTTPClient: &http.Client{
+			Timeout:   time.Second * 5,
+			Transport: resilient.NewTransport(nil),
+		},
+		Intra:   intra,
+		BaseURL: parsedURL,
+		Token:   token,
+	}, nil
+}
+
+// SendNotification sends a notification to the participants of an evaluation containing the link to its meeting.
+func (client *WebClient) SendNotification(scaleTeamID int, beginAt time.Time, meeting db.Meeting, participants []room.Participant) error {
+	ctxfields := logrus.Fields{"scale_team_id": scaleTeamID}
+	if len(participants) == 0 {
+		return logging.WithLog(NoParticipantsError, logrus.WarnLevel, ctxfields)
+	}
+
+	logrus.WithFields(ctxfields).Info("getting scale team users' emails")
+	participants, err := fillEmails(client.Intra, participants)
+	if err != nil {
+		return logging.WithLog(err, logrus.ErrorLevel, ctxfields)
+	}
+	links, err := room.Links(meeting, beginAt, participants)
+	if err != nil {
+		return logging.WithLog(err, logrus.ErrorLevel, ctxfields)
+	}
+
+	userIDs, err := client.lookupUsers(scaleTeamID, participants)
+	if err != nil {
+		return err
+	}
+
+	text := notificationText(beginAt, time.Now())
+	attachment := notificationAttachments()[0]
+	if !config.Conf.Jitsi.JWT.Enabled {
+		return client.send(scaleTeamID, userIDs, text, attachment, links[participants[0].Login])
+	}
+
+	return sendEach(scaleTeamID, participants, func(i int, participant room.Participant) error {
+		return client.send(scaleTeamID, userIDs[i:i+1], text, attachment, links[participant.Login])
+	})
+}
+
+// SendCancellation notifies the participants of an evaluation that it was cancelled.
+func (client *WebClient) SendCancellation(scaleTeamID int, beginAt time.Time, participants []room.Participant) error {
+	return client.sendNotice(scaleTeamID, participants, cancellationText(beginAt), cancellationAttachments()[0])
+}
+
+// SendReschedule notifies the participants of an evaluation that it was moved from `from` to `to`.
+func (client *WebClient) SendReschedule(scaleTeamID int, from, to time.Time, participants []room.Participant) error {
+	return client.sendNotice(scaleTeamID, participants, rescheduleText(from, to), rescheduleAttachments()[0])
+}
+
+// SendNoShowReport reports to the channel the participants of an evaluation who never joined its meeting.
+func (client *WebClient) SendNoShowReport(channel string, scaleTeamID int, beginAt time.Time, noShows []room.Participant) error {
+	ctxfields := logrus.Fields{"scale_team_id": scaleTeamID}
+	if len(noShows) == 0 {
+		return logging.WithLog(NoParticipantsError, logrus.WarnLevel, ctxfields)
+	}
+
+	logrus.WithFields(ctxfields).Info("posting no-show report to slack")
+	text := noShowText(scaleTeamID, beginAt, noShows)
+	if err := client.postMessage(channel, text, messageBlocks(text, noShowAttachments()[0], "")); err != nil {
+		return logging.WithLog(err, logrus.ErrorLevel, ctxfields)
+	}
+	return nil
+}
+
+// GetHealth checks the bot token with the Slack Web API and returns the identity of the bot.
+func (client *WebClient) GetHealth() (map[string]interface{}, error) {
+	data := make(map[string]interface{})
+	if err := client.get("auth.test", nil, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// sendNotice sends a single message without any link to the conversation of every participant of an evaluation.
+func (client *WebClient) sendNotice(scaleTeamID int, participants []room.Participant, text string, attachment Attachment) error {
+	ctxfields := logrus.Fields{"scale_team_id": scaleTeamID}
+	if len(participants) == 0 {
+		return logging.WithLog(NoParticipantsError, logrus.WarnLevel, ctxfields)
+	}
+
+	logrus.WithFields(ctxfields).Info("getting scale team users' emails")
+	participants, err := fillEmails(client.Intra, participants)
+	if err != nil {
+		return logging.WithLog(err, logrus.ErrorLevel, ctxfields)
+	}
+	userIDs, err := client.lookupUsers(scaleTeamID, participants)
+	if err != nil {
+		return err
+	}
+	return client.send(scaleTeamID, userIDs, text, attachment, "")
+}
+
+// send posts a message to the conversation of the users, opened with the bot if it does not exist yet.
+func (client *WebClient) send(scaleTeamID int, userIDs []string, text string, attachment Attachment, link string) error {
+	ctxfields := logrus.Fields{
+		"scale_team_id": scaleTeamID,
+		"room_link":     link,
+	}
+
+	channel, err := client.openConversation(userIDs)
+	if err != nil {
+		return logging.WithLog(err, logrus.ErrorLevel, ctxfields)
+	}
+
+	logrus.WithFields(ctxfields).Info("posting message to slack")
+	if err := client.postMessage(channel, text, messageBlocks(text, attachment, link)); err != nil {
+		return logging.WithLog(err, logrus.ErrorLevel, ctxfields)
+	}
+	return nil
+}
+
+// lookupUsers returns the ids of the slack users of the participants, in the same order. It fails before anything is
+// sent if any of them cannot be found.
+func (client *WebClient) lookupUsers(scaleTeamID int, participants []room.Participant) ([]string, error) {
+	userIDs := make([]string, len(participants))
+	for i, participant := range participants {
+		userID, err := client.lookupUser(participant.Email)
+		if err != nil {
+			return nil, logging.WithLog(err, logrus.ErrorLevel, logrus.Fields{"scale_team_id": scaleTeamID, "login": participant.Login})
+		}
+		userIDs[i] = userID
+	}
+	return userIDs, nil
+}
+
+// lookupUser returns the id of the slack user with the email.
+func (client *WebClient) lookupUser(email string) (string, error) {
+	var resp struct {
+		User struct {
+			ID string `json:"id"`
+		} `json:"user"`
+	}
+	if err := client.get("users.lookupByEmail", url.Values{"email": {email}}, &resp); err != nil {
+		return "", err
+	}
+	return resp.User.ID, nil
+}
+
+// openConversation opens the conversation of the bot with the users and returns its id. It is the direct message of
+// the user if there is only one.
+func (client *WebClient) openConversation(userIDs []string) (string, error) {
+	var resp struct {
+		Channel struct {
+			ID string `json:"id"`
+		} `json:"channel"`
+	}
+	params := map[string]interface{}{"users": strings.Join(userIDs, ",")}
+	if err := client.post("conversations.open", params, &resp); err != nil {
+		return "", err
+	}
+	return resp.Channel.ID, nil
+}
+
+// postMessage posts the blocks to the channel, with the text as fallback for the notifications.
+func (client *WebClient) postMessage(channel, text string, blocks []block) error {
+	params := map[string]interface{}{
+		"channel": channel,
+		"text":    text,
+		"blocks":  blocks,
+	}
+	return client.post("chat.postMessage", params, nil)
+}
+
+// get calls a read method of the API with the query, so that it may be retried.
+func (client *WebClient) get(method string, query url.Values, v interface{}) error {
+	request, err := http.NewRequest(http.MethodGet, client.getURL(method), nil)
+	if err != nil {
+		return err
+	}
+	request.URL.RawQuery = query.Encode()
+	return client.call(method, request, v)
+}
+
+// post calls a write method of the API with the JSON encoded parameters.
+func (client *WebClient) post(method string, params map[string]interface{}, v interface{}) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequest(http.MethodPost, client.getURL(method), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json; charset=utf-8")
+	return client.call(method, request, v)
+}
+
+// call sends the request of the API method and decodes its response into v if it is not nil. It fails with an
+// *APIError if the API answers that the call failed.
+func (client *WebClient) call(method string, request *http.Request, v interface{}) error {
+	request.Header.Set("Authorization", fmt.Sprintf("Bearer %s", client.Token))
+	resp, err := client.HTTPClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return &APIError{Method: method, Code: resp.Status}
+	}
+	var body json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return err
+	}
+	var envelope webResponse
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return err
+	}
+	if !envelope.OK {
+		return &APIError{Method: method, Code: envelope.Error}
+	}
+	if v != nil {
+		return json.Unmarshal(body, v)
+	}
+	return nil
+}
+
+func (client *WebClient) getURL(method string) string {
+	urlCopy := &url.URL{}
+	*urlCopy = *client.BaseURL
+
+	urlCopy.Path = path.Join(urlCopy.Path, method)
+	return urlCopy.String()
+}
@@ -0,0 +1,177 @@
+package slack
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gustavobelfort/42-jitsi/internal/config"
+	"github.com/gustavobelfort/42-jitsi/internal/notify"
+	"github.com/gustavobelfort/42-jitsi/internal/room"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestWebClient(t *testing.T) {
+	suite.Run(t, new(WebClientSuite))
+}
+
+type WebClientSuite struct {
+	suite.Suite
+
+	mock   *WebServerMock
+	client *WebClient
+}
+
+func (s *WebClientSuite) SetupSuite() {
+	s.Require().Implements((*SlackThat)(nil), &WebClient{})
+
+	config.Conf.Jitsi.JWT = config.JitsiJWT{
+		AppID:     "app_id",
+		AppSecret: "app_secret",
+		Audience:  "jitsi",
+		Subject:   "*",
+		Duration:  time.Hour,
+	}
+	s.mock = NewWebServerMock()
+
+	client, err := NewWebClient(&IntraMock{}, s.mock.Server.URL, "xoxb-token")
+	s.Require().NoError(err)
+	s.client = client.(*WebClient)
+}
+
+func (s *WebClientSuite) SetupTest() {
+	s.mock.ExpectedCalls = []*mock.Call{}
+	s.mock.Calls = []mock.Call{}
+	config.Conf.Jitsi.JWT.Enabled = false
+}
+
+func (s *WebClientSuite) TearDownTest() {
+	s.mock.AssertExpectations(s.T())
+}
+
+func (s *WebClientSuite) TearDownSuite() {
+	s.mock.Server.Close()
+	config.Conf.Slack = config.SlackConfig{}
+}
+
+func (s *WebClientSuite) Test00_SendNotification() {
+	participants := []room.Participant{{Login: "xlogin", Moderator: true}, {Login: "ylogin"}}
+
+	s.mock.On("LookupByEmail", "xlogin@student.42campus.org").Return("", "UX").Once()
+	s.mock.On("LookupByEmail", "ylogin@student.42campus.org").Return("", "UY").Once()
+	s.mock.On("OpenConversation", "UX,UY").Return("", "G42").Once()
+	s.mock.On("PostMessage", "G42", meeting.URL).Return("").Once()
+	s.NoError(s.client.SendNotification(1, time.Now(), meeting, participants))
+}
+
+func (s *WebClientSuite) Test01_SendNotification_JWT() {
+	config.Conf.Jitsi.JWT.Enabled = true
+	participants := []room.Participant{{Login: "xlogin", Moderator: true}, {Login: "ylogin"}}
+
+	s.mock.On("LookupByEmail", "xlogin@student.42campus.org").Return("", "UX").Once()
+	s.mock.On("LookupByEmail", "ylogin@student.42campus.org").Return("", "UY").Once()
+	s.mock.On("OpenConversation", "UX").Return("", "DX").Once()
+	s.mock.On("OpenConversation", "UY").Return("", "DY").Once()
+	s.mock.On("PostMessage", "DX", tokenLink("xlogin", true)).Return("").Once()
+	s.mock.On("PostMessage", "DY", tokenLink("ylogin", false)).Return("").Once()
+	s.NoError(s.client.SendNotification(1, time.Now(), meeting, participants))
+}
+
+func (s *WebClientSuite) Test02_SendNotification_UserNotFound() {
+	participants := []room.Participant{{Login: "xlogin", Moderator: true}}
+
+	s.mock.On("LookupByEmail", "xlogin@student.42campus.org").Return("users_not_found", "").Once()
+	err := s.client.SendNotification(1, time.Now(), meeting, participants)
+	s.Require().Error(err)
+	var apiErr *APIError
+	s.Require().True(errors.As(err, &apiErr))
+	s.Equal(&APIError{Method: "users.lookupByEmail", Code: "users_not_found"}, apiErr)
+}
+
+func (s *WebClientSuite) Test03_SendNotification_NoParticipants() {
+	err := s.client.SendNotification(1, time.Now(), meeting, nil)
+	s.True(errors.Is(err, NoParticipantsError))
+}
+
+func (s *WebClientSuite) Test04_SendCancellation() {
+	participants := []room.Participant{{Login: "xlogin", Moderator: true}, {Login: "ylogin"}}
+
+	s.mock.On("LookupByEmail", "xlogin@student.42campus.org").Return("", "UX").Once()
+	s.mock.On("LookupByEmail", "ylogin@student.42campus.org").Return("", "UY").Once()
+	s.mock.On("OpenConversation", "UX,UY").Return("", "G42").Once()
+	s.mock.On("PostMessage", "G42", "").Return("").Once()
+	s.NoError(s.client.SendCancellation(1, time.Now(), participants))
+}
+
+func (s *WebClientSuite) Test05_SendReschedule_PostFailure() {
+	participants := []room.Participant{{Login: "xlogin", Moderator: true}}
+
+	s.mock.On("LookupByEmail", "xlogin@student.42campus.org").Return("", "UX").Once()
+	s.mock.On("OpenConversation", "UX").Return("", "DX").Once()
+	s.mock.On("PostMessage", "DX", "").Return("channel_not_found").Once()
+	err := s.client.SendReschedule(1, time.Now(), time.Now().Add(time.Hour), participants)
+	var apiErr *APIError
+	s.Require().True(errors.As(err, &apiErr))
+	s.Equal("chat.postMessage", apiErr.Method)
+}
+
+func (s *WebClientSuite) Test06_SendNoShowReport() {
+	s.mock.On("PostMessage", "#staff", "").Return("").Once()
+	s.NoError(s.client.SendNoShowReport("#staff", 1, time.Now(), []room.Participant{{Login: "xlogin"}}))
+}
+
+func (s *WebClientSuite) Test07_GetHealth() {
+	health, err := s.client.GetHealth()
+	s.Require().NoError(err)
+	s.Equal("UBOT", health["user_id"])
+
+	client, err := NewWebClient(&IntraMock{}, s.mock.Server.URL, "xoxb-wrong")
+	s.Require().NoError(err)
+	_, err = client.GetHealth()
+	s.Equal(&APIError{Method: "auth.test", Code: "invalid_auth"}, err)
+}
+
+func (s *WebClientSuite) Test08_SendNotification_JWT_UserNotFound() {
+	config.Conf.Jitsi.JWT.Enabled = true
+	participants := []room.Participant{{Login: "xlogin", Moderator: true}, {Login: "ylogin"}}
+
+	// Nobody is messaged until every participant is found.
+	s.mock.On("LookupByEmail", "xlogin@student.42campus.org").Return("", "UX").Once()
+	s.mock.On("LookupByEmail", "ylogin@student.42campus.org").Return("users_not_found", "").Once()
+	s.Error(s.client.SendNotification(1, time.Now(), meeting, participants))
+}
+
+func (s *WebClientSuite) Test09_SendNotification_JWT_PartialFailure() {
+	config.Conf.Jitsi.JWT.Enabled = true
+	participants := []room.Participant{{Login: "xlogin", Moderator: true}, {Login: "ylogin"}}
+
+	s.mock.On("LookupByEmail", "xlogin@student.42campus.org").Return("", "UX").Once()
+	s.mock.On("LookupByEmail", "ylogin@student.42campus.org").Return("", "UY").Once()
+	s.mock.On("OpenConversation", "UX").Return("", "DX").Once()
+	s.mock.On("OpenConversation", "UY").Return("", "DY").Once()
+	s.mock.On("PostMessage", "DX", tokenLink("xlogin", true)).Return("").Once()
+	s.mock.On("PostMessage", "DY", tokenLink("ylogin", false)).Return("is_archived").Once()
+	err := s.client.SendNotification(1, time.Now(), meeting, participants)
+	var undeliveredErr *UndeliveredError
+	s.Require().True(errors.As(err, &undeliveredErr))
+	s.Equal([]string{"ylogin"}, undeliveredErr.Logins)
+	var apiErr *APIError
+	s.Require().True(errors.As(undeliveredErr.Errors["ylogin"], &apiErr))
+	s.Equal("is_archived", apiErr.Code)
+}
+
+func (s *WebClientSuite) Test10_Notifier() {
+	config.Conf.Slack = config.SlackConfig{URL: s.mock.Server.URL, Token: "xoxb-token", StaffChannel: "#staff"}
+	n, err := notify.New(WebNotifier, &IntraMock{})
+	s.Require().NoError(err)
+
+	message := notify.Message{
+		Kind:         notify.NoShow,
+		ScaleTeamID:  21,
+		BeginAt:      time.Now(),
+		Participants: []notify.Participant{{Login: "ylogin", Role: notify.Corrected}},
+	}
+	s.mock.On("PostMessage", "#staff", "").Return("").Once()
+	s.NoError(n.Notify(message))
+}
@@ -0,0 +1,97 @@
+package tasks
+
+import (
+	"time"
+
+	"github.com/gustavobelfort/42-jitsi/internal/config"
+	"github.com/gustavobelfort/42-jitsi/internal/db"
+	"github.com/gustavobelfort/42-jitsi/internal/logging"
+	"github.com/gustavobelfort/42-jitsi/internal/notify"
+	"github.com/sirupsen/logrus"
+)
+
+// CheckAttendance stores the participants who never joined the meeting of the evaluations which began at least
+// `NoShowDelay` ago and reports them to the staff channel if one is configured.
+func (handler *tasksHandler) CheckAttendance() {
+	logrus.Debug("getting scale teams to check the attendance of")
+	scaleTeams, err := handler.scaleTeamManager.Get(handler.db,
+		db.ScaleTeamAttendanceCheckedOption(false),
+		db.ScaleTeamAttendanceTrackedOption(),
+		db.ScaleTeamBeginAtBeforeOption(time.Now().Add(-config.Conf.NoShowDelay)),
+	)
+	if err != nil {
+		logrus.WithError(err).Errorf("error getting scale teams to check the attendance of: %v", err)
+		return
+	}
+
+	if len(scaleTeams) == 0 {
+		logrus.Debugf("no attendance to be checked")
+		return
+	}
+
+	logrus.Infof("found %d scale teams to check the attendance of", len(scaleTeams))
+	for _, scaleTeam := range scaleTeams {
+		ctxlogger := logrus.WithField("scale_team_id", scaleTeam.GetID())
+
+		noShows, err := handler.checkAttendance(scaleTeam)
+		if err != nil {
+			logging.LogError(ctxlogger, err, "checking the attendance of the scale team")
+			continue
+		}
+		if len(noShows) == 0 {
+			ctxlogger.Info("every participant joined the meeting")
+			continue
+		}
+		ctxlogger.WithField("no_shows", len(noShows)).Info("found participants who never joined the meeting")
+
+		if err := handler.notifier.Notify(notify.Message{
+			Kind:         notify.NoShow,
+			ScaleTeamID:  scaleTeam.GetID(),
+			BeginAt:      scaleTeam.GetBeginAt(),
+			Participants: noShows,
+		}); err != nil {
+			logging.LogError(ctxlogger, err, "reporting the no-shows to the staff")
+		}
+	}
+}
+
+// checkAttendance stores a no-show for each participant of the scale team without any attendance and marks the scale
+// team as checked.
+func (handler *tasksHandler) checkAttendance(scaleTeam db.ScaleTeam) ([]notify.Participant, error) {
+	tx := handler.db.Begin()
+	defer tx.RollbackUnlessCommitted()
+
+	scaleTeamID := scaleTeam.GetID()
+	users, err := handler.userManager.Get(tx, db.UserScaleTeamOption(scaleTeamID))
+	if err != nil {
+		return nil, err
+	}
+	attendances, err := handler.attendanceManager.Get(tx, db.AttendanceScaleTeamOption(scaleTeamID))
+	if err != nil {
+		return nil, err
+	}
+
+	attended := make(map[string]bool, len(attendances))
+	for _, attendance := range attendances {
+		attended[attendance.GetLogin()] = true
+	}
+
+	var noShows []notify.Participant
+	for _, user := range users {
+		if attended[user.GetLogin()] {
+			continue
+		}
+		if _, err := handler.noShowManager.Create(tx, scaleTeamID, user.GetLogin(), user.GetStatus(), scaleTeam.GetBeginAt()); err != nil {
+			return nil, err
+		}
+		noShows = append(noShows, notify.Participant{
+			Login: user.GetLogin(),
+			Role:  notify.Role(user.GetStatus()),
+		})
+	}
+
+	if err := handler.scaleTeamManager.SetAttendanceChecked(tx, scaleTeamID); err != nil {
+		return nil, err
+	}
+	return noShows, tx.Commit().Error
+}
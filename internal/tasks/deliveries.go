@@ -0,0 +1,21 @@
+package tasks
+
+import (
+	"time"
+
+	"github.com/gustavobelfort/42-jitsi/internal/config"
+	"github.com/sirupsen/logrus"
+)
+
+// PurgeDeliveries forgets the webhook deliveries processed more than `DeliveryRetention` ago.
+func (handler *tasksHandler) PurgeDeliveries() {
+	before := time.Now().Add(-config.Conf.DeliveryRetention)
+
+	logrus.Debug("purging processed deliveries")
+	purged, err := handler.deliveryManager.Purge(handler.db, before)
+	if err != nil {
+		logrus.WithError(err).Errorf("error purging processed deliveries: %v", err)
+		return
+	}
+	logrus.WithField("processed_before", before).Infof("purged %d processed deliveries", purged)
+}
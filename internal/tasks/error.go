@@ -0,0 +1,8 @@
+package tasks
+
+import "errors"
+
+var (
+	// NotInDBError is returned when the scale team of a reminder is not present in the database.
+	NotInDBError = errors.New("the evaluation of the reminder was not in the database")
+)
@@ -6,6 +6,9 @@ import (
 	"time"
 
 	"github.com/gustavobelfort/42-jitsi/internal/db"
+	"github.com/gustavobelfort/42-jitsi/internal/handler"
+	"github.com/gustavobelfort/42-jitsi/internal/intra"
+	"github.com/gustavobelfort/42-jitsi/internal/notify"
 	"github.com/jinzhu/gorm"
 	"github.com/stretchr/testify/mock"
 )
@@ -18,15 +21,67 @@ func (m *TxManagerMock) BeginTx(ctx context.Context, options *sql.TxOptions) *go
 	return m.Called(ctx, options).Get(0).(*gorm.DB)
 }
 
-type ClientMock struct {
+type NotifierMock struct {
 	mock.Mock
 }
 
-func (m *ClientMock) GetHealth() (map[string]interface{}, error) {
-	return nil, nil
+func (m *NotifierMock) Notify(message notify.Message) error {
+	return m.Called(message).Error(0)
 }
-func (m *ClientMock) SendNotification(scaleTeamID int, logins []string) error {
-	return nil
+
+type IntraMock struct {
+	mock.Mock
+}
+
+func (m *IntraMock) GetTeamMembers(ctx context.Context, teamID int) ([]string, error) {
+	toReturn := m.Called(ctx, teamID)
+	return toReturn.Get(0).([]string), toReturn.Error(1)
+}
+
+func (m *IntraMock) GetUserEmail(ctx context.Context, login string) (string, error) {
+	toReturn := m.Called(ctx, login)
+	return toReturn.String(0), toReturn.Error(1)
+}
+
+func (m *IntraMock) GetUserEmails(ctx context.Context, logins []string) (map[string]string, error) {
+	toReturn := m.Called(ctx, logins)
+	emails, _ := toReturn.Get(0).(map[string]string)
+	return emails, toReturn.Error(1)
+}
+
+func (m *IntraMock) ListScaleTeams(ctx context.Context, campusID int, from, to time.Time) ([]intra.ScaleTeam, error) {
+	toReturn := m.Called(ctx, campusID, from, to)
+	return toReturn.Get(0).([]intra.ScaleTeam), toReturn.Error(1)
+}
+
+func (m *IntraMock) GetScaleTeam(ctx context.Context, id int) (*intra.ScaleTeam, error) {
+	toReturn := m.Called(ctx, id)
+	return toReturn.Get(0).(*intra.ScaleTeam), toReturn.Error(1)
+}
+
+type ScaleTeamHandlerMock struct {
+	mock.Mock
+}
+
+func (m *ScaleTeamHandlerMock) HandleCreate(ctx context.Context, data []byte) error {
+	return m.Called(ctx, data).Error(0)
+}
+
+func (m *ScaleTeamHandlerMock) HandleUpdate(ctx context.Context, data []byte) error {
+	return m.Called(ctx, data).Error(0)
+}
+
+func (m *ScaleTeamHandlerMock) HandleDestroy(ctx context.Context, data []byte) error {
+	return m.Called(ctx, data).Error(0)
+}
+
+func (m *ScaleTeamHandlerMock) HandleScaleTeam(ctx context.Context, st *intra.ScaleTeam) error {
+	return m.Called(ctx, st).Error(0)
+}
+
+func (m *ScaleTeamHandlerMock) Preview(ctx context.Context, st *intra.ScaleTeam) (handler.Change, error) {
+	toReturn := m.Called(ctx, st)
+	return toReturn.Get(0).(handler.Change), toReturn.Error(1)
 }
 
 type ScaleTeamManagerMock struct {
@@ -37,8 +92,8 @@ func (m *ScaleTeamManagerMock) DB() *gorm.DB {
 	return m.Called().Get(0).(*gorm.DB)
 }
 
-func (m *ScaleTeamManagerMock) Create(tx *gorm.DB, id int, beginAt time.Time, notified bool) (db.ScaleTeam, error) {
-	toReturn := m.Called(tx, id, beginAt, notified)
+func (m *ScaleTeamManagerMock) Create(tx *gorm.DB, id int, beginAt, intraUpdatedAt time.Time, meeting db.Meeting) (db.ScaleTeam, error) {
+	toReturn := m.Called(tx, id, beginAt, intraUpdatedAt, meeting)
 	return toReturn.Get(0).(db.ScaleTeam), toReturn.Error(1)
 }
 
@@ -46,6 +101,10 @@ func (m *ScaleTeamManagerMock) Update(tx *gorm.DB, scaleTeam db.ScaleTeam) error
 	return m.Called(tx, scaleTeam).Error(0)
 }
 
+func (m *ScaleTeamManagerMock) SetAttendanceChecked(tx *gorm.DB, id int) error {
+	return m.Called(tx, id).Error(0)
+}
+
 func (m *ScaleTeamManagerMock) Delete(tx *gorm.DB, scaleTeam db.ScaleTeam) error {
 	return m.Called(tx, scaleTeam).Error(0)
 }
@@ -81,6 +140,106 @@ func (m *UserManagerMock) Get(tx *gorm.DB, options ...db.GetOption) ([]db.User,
 	return toReturn.Get(0).([]db.User), toReturn.Error(1)
 }
 
+type ReminderManagerMock struct {
+	mock.Mock
+}
+
+func (m *ReminderManagerMock) DB() *gorm.DB {
+	return m.Called().Get(0).(*gorm.DB)
+}
+
+func (m *ReminderManagerMock) Create(tx *gorm.DB, scaleTeamID int, remindBefore time.Duration, remindAt time.Time) (db.Reminder, error) {
+	toReturn := m.Called(tx, scaleTeamID, remindBefore, remindAt)
+	return toReturn.Get(0).(db.Reminder), toReturn.Error(1)
+}
+
+func (m *ReminderManagerMock) Update(tx *gorm.DB, reminder db.Reminder) error {
+	return m.Called(tx, reminder).Error(0)
+}
+
+func (m *ReminderManagerMock) SetSent(tx *gorm.DB, id int, sentAt time.Time) error {
+	return m.Called(tx, id, sentAt).Error(0)
+}
+
+func (m *ReminderManagerMock) Delete(tx *gorm.DB, reminder db.Reminder) error {
+	return m.Called(tx, reminder).Error(0)
+}
+
+func (m *ReminderManagerMock) Get(tx *gorm.DB, options ...db.GetOption) ([]db.Reminder, error) {
+	toReturn := m.Called(tx, options)
+	return toReturn.Get(0).([]db.Reminder), toReturn.Error(1)
+}
+
+type AttendanceManagerMock struct {
+	mock.Mock
+}
+
+func (m *AttendanceManagerMock) DB() *gorm.DB {
+	return m.Called().Get(0).(*gorm.DB)
+}
+
+func (m *AttendanceManagerMock) Create(tx *gorm.DB, scaleTeamID int, login string, joinedAt time.Time) (db.Attendance, error) {
+	toReturn := m.Called(tx, scaleTeamID, login, joinedAt)
+	return toReturn.Get(0).(db.Attendance), toReturn.Error(1)
+}
+
+func (m *AttendanceManagerMock) Update(tx *gorm.DB, attendance db.Attendance) error {
+	return m.Called(tx, attendance).Error(0)
+}
+
+func (m *AttendanceManagerMock) Delete(tx *gorm.DB, attendance db.Attendance) error {
+	return m.Called(tx, attendance).Error(0)
+}
+
+func (m *AttendanceManagerMock) Get(tx *gorm.DB, options ...db.GetOption) ([]db.Attendance, error) {
+	toReturn := m.Called(tx, options)
+	return toReturn.Get(0).([]db.Attendance), toReturn.Error(1)
+}
+
+type NoShowManagerMock struct {
+	mock.Mock
+}
+
+func (m *NoShowManagerMock) DB() *gorm.DB {
+	return m.Called().Get(0).(*gorm.DB)
+}
+
+func (m *NoShowManagerMock) Create(tx *gorm.DB, scaleTeamID int, login string, status db.UserStatus, beginAt time.Time) (db.NoShow, error) {
+	toReturn := m.Called(tx, scaleTeamID, login, status, beginAt)
+	return toReturn.Get(0).(db.NoShow), toReturn.Error(1)
+}
+
+func (m *NoShowManagerMock) Update(tx *gorm.DB, noShow db.NoShow) error {
+	return m.Called(tx, noShow).Error(0)
+}
+
+func (m *NoShowManagerMock) Delete(tx *gorm.DB, noShow db.NoShow) error {
+	return m.Called(tx, noShow).Error(0)
+}
+
+func (m *NoShowManagerMock) Get(tx *gorm.DB, options ...db.GetOption) ([]db.NoShow, error) {
+	toReturn := m.Called(tx, options)
+	return toReturn.Get(0).([]db.NoShow), toReturn.Error(1)
+}
+
+type DeliveryManagerMock struct {
+	mock.Mock
+}
+
+func (m *DeliveryManagerMock) DB() *gorm.DB {
+	return m.Called().Get(0).(*gorm.DB)
+}
+
+func (m *DeliveryManagerMock) Record(tx *gorm.DB, id string, processedAt time.Time) (bool, error) {
+	toReturn := m.Called(tx, id, processedAt)
+	return toReturn.Bool(0), toReturn.Error(1)
+}
+
+func (m *DeliveryManagerMock) Purge(tx *gorm.DB, before time.Time) (int64, error) {
+	toReturn := m.Called(tx, before)
+	return toReturn.Get(0).(int64), toReturn.Error(1)
+}
+
 type ScaleTeamMock struct {
 	mock.Mock
 }
@@ -93,10 +252,18 @@ func (m *ScaleTeamMock) GetBeginAt() time.Time {
 	return m.Called().Get(0).(time.Time)
 }
 
-func (m *ScaleTeamMock) GetNotified() bool {
+func (m *ScaleTeamMock) GetMeeting() db.Meeting {
+	return m.Called().Get(0).(db.Meeting)
+}
+
+func (m *ScaleTeamMock) GetAttendanceChecked() bool {
 	return m.Called().Bool(0)
 }
 
+func (m *ScaleTeamMock) GetIntraUpdatedAt() time.Time {
+	return m.Called().Get(0).(time.Time)
+}
+
 func (m *ScaleTeamMock) Get(tx *gorm.DB, options ...db.GetOption) ([]db.User, error) {
 	toReturn := m.Called(tx, options)
 	return toReturn.Get(0).([]db.User), toReturn.Error(1)
@@ -110,8 +277,16 @@ func (m *ScaleTeamMock) SetBeginAt(beginAt time.Time) {
 	m.Called(beginAt)
 }
 
-func (m *ScaleTeamMock) SetNotified(notified bool) {
-	m.Called(notified)
+func (m *ScaleTeamMock) SetMeeting(meeting db.Meeting) {
+	m.Called(meeting)
+}
+
+func (m *ScaleTeamMock) SetAttendanceChecked(checked bool) {
+	m.Called(checked)
+}
+
+func (m *ScaleTeamMock) SetIntraUpdatedAt(updatedAt time.Time) {
+	m.Called(updatedAt)
 }
 
 func (m *ScaleTeamMock) Save(tx *gorm.DB) error {
@@ -161,3 +336,111 @@ func (m *UserMock) Save(tx *gorm.DB) error {
 func (m *UserMock) Delete(tx *gorm.DB) error {
 	return m.Called(tx).Error(0)
 }
+
+type ReminderMock struct {
+	mock.Mock
+}
+
+func (m *ReminderMock) GetID() int {
+	return m.Called().Int(0)
+}
+
+func (m *ReminderMock) GetScaleTeamID() int {
+	return m.Called().Int(0)
+}
+
+func (m *ReminderMock) GetRemindBefore() time.Duration {
+	return m.Called().Get(0).(time.Duration)
+}
+
+func (m *ReminderMock) GetRemindAt() time.Time {
+	return m.Called().Get(0).(time.Time)
+}
+
+func (m *ReminderMock) GetSentAt() *time.Time {
+	return m.Called().Get(0).(*time.Time)
+}
+
+func (m *ReminderMock) SetRemindAt(remindAt time.Time) {
+	m.Called(remindAt)
+}
+
+func (m *ReminderMock) SetSentAt(sentAt *time.Time) {
+	m.Called(sentAt)
+}
+
+func (m *ReminderMock) Save(tx *gorm.DB) error {
+	return m.Called(tx).Error(0)
+}
+
+func (m *ReminderMock) Delete(tx *gorm.DB) error {
+	return m.Called(tx).Error(0)
+}
+
+type AttendanceMock struct {
+	mock.Mock
+}
+
+func (m *AttendanceMock) GetID() int {
+	return m.Called().Int(0)
+}
+
+func (m *AttendanceMock) GetScaleTeamID() int {
+	return m.Called().Int(0)
+}
+
+func (m *AttendanceMock) GetLogin() string {
+	return m.Called().String(0)
+}
+
+func (m *AttendanceMock) GetJoinedAt() time.Time {
+	return m.Called().Get(0).(time.Time)
+}
+
+func (m *AttendanceMock) GetLeftAt() *time.Time {
+	return m.Called().Get(0).(*time.Time)
+}
+
+func (m *AttendanceMock) SetLeftAt(leftAt *time.Time) {
+	m.Called(leftAt)
+}
+
+func (m *AttendanceMock) Save(tx *gorm.DB) error {
+	return m.Called(tx).Error(0)
+}
+
+func (m *AttendanceMock) Delete(tx *gorm.DB) error {
+	return m.Called(tx).Error(0)
+}
+
+type NoShowMock struct {
+	mock.Mock
+}
+
+func (m *NoShowMock) GetID() int {
+	return m.Called().Int(0)
+}
+
+func (m *NoShowMock) GetScaleTeamID() int {
+	return m.Called().Int(0)
+}
+
+func (m *NoShowMock) GetLogin() string {
+	return m.Called().String(0)
+}
+
+func (m *NoShowMock) GetStatus() db.UserStatus {
+	return db.UserStatus(m.Called().String(0))
+}
+
+func (m *NoShowMock) GetBeginAt() time.Time {
+	return m.Called().Get(0).(time.Time)
+}
+
+func (m *NoShowMock) Save(tx *gorm.DB) error {
+	return m.Called(tx).Error(0)
+}
+
+func (m *NoShowMock) Delete(tx *gorm.DB) error {
+	return m.Called(tx).Error(0)
+}
@@ -0,0 +1,174 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gustavobelfort/42-jitsi/internal/config"
+	"github.com/gustavobelfort/42-jitsi/internal/db"
+	"github.com/gustavobelfort/42-jitsi/internal/handler"
+	"github.com/gustavobelfort/42-jitsi/internal/intra"
+	"github.com/gustavobelfort/42-jitsi/internal/logging"
+	"github.com/sirupsen/logrus"
+)
+
+// Reconciliation sums up the corrections made by a reconciliation between the database and intra.
+type Reconciliation struct {
+	Listed         int
+	Created        int
+	Rescheduled    int
+	MembersUpdated int
+	Deleted        int
+	Failed         int
+}
+
+func (summary *Reconciliation) fields() logrus.Fields {
+	return logrus.Fields{
+		"listed":          summary.Listed,
+		"created":         summary.Created,
+		"rescheduled":     summary.Rescheduled,
+		"members_updated": summary.MembersUpdated,
+		"deleted":         summary.Deleted,
+		"failed":          summary.Failed,
+	}
+}
+
+// Reconcile corrects the drift between the scale teams beginning within `ReconcileWindow` and the ones intra lists,
+// e.g: because of lost webhooks.
+func (handler *tasksHandler) Reconcile() {
+	from := time.Now()
+	to := from.Add(config.Conf.ReconcileWindow)
+	logger := logrus.WithFields(logrus.Fields{"from": from, "to": to})
+
+	logger.Debug("reconciling the scale teams with intra")
+	summary, err := handler.reconcile(context.Background(), from, to)
+	if err != nil {
+		logger.WithError(err).Errorf("error reconciling the scale teams with intra: %v", err)
+		return
+	}
+	logger.WithFields(summary.fields()).Info("reconciled the scale teams with intra")
+}
+
+// reconcile handles the scale teams listed by intra which differ from their records as updates. The stored scale
+// teams which are not listed anymore are destroyed if intra does not know them anymore, and corrected otherwise, as
+// they were moved out of the window.
+//
+// A scale team is only compared with its record if intra updated it since, as the comparison requests the members of
+// its team.
+func (handler *tasksHandler) reconcile(ctx context.Context, from, to time.Time) (*Reconciliation, error) {
+	listedScaleTeams, err := handler.intra.ListScaleTeams(ctx, config.Conf.Intra.CampusID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	scaleTeams, err := handler.scaleTeamManager.Get(handler.db,
+		db.ScaleTeamBeginAtAfterOption(from),
+		db.ScaleTeamBeginAtBeforeOption(to),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, len(scaleTeams))
+	records := make(map[int]db.ScaleTeam, len(scaleTeams))
+	for i, scaleTeam := range scaleTeams {
+		ids[i] = scaleTeam.GetID()
+		records[ids[i]] = scaleTeam
+	}
+
+	summary := &Reconciliation{Listed: len(listedScaleTeams)}
+	listed := make(map[int]bool, len(listedScaleTeams))
+	for i := range listedScaleTeams {
+		listed[listedScaleTeams[i].ID] = true
+		handler.correct(ctx, summary, &listedScaleTeams[i], records[listedScaleTeams[i].ID])
+	}
+
+	for _, scaleTeamID := range ids {
+		if listed[scaleTeamID] {
+			continue
+		}
+		ctxlogger := logrus.WithField("scale_team_id", scaleTeamID)
+
+		intraScaleTeam, err := handler.intra.GetScaleTeam(ctx, scaleTeamID)
+		if err == nil {
+			handler.correct(ctx, summary, intraScaleTeam, records[scaleTeamID])
+			continue
+		}
+		if !intra.IsNotFound(err) {
+			summary.Failed++
+			logging.LogError(ctxlogger, err, "getting the unlisted scale team from intra")
+			continue
+		}
+
+		stCtx := logging.ContextWithField(ctx, "scale_team_id", scaleTeamID)
+		if err := handler.scaleTeamHandler.HandleDestroy(stCtx, []byte(fmt.Sprintf(`{"id": %d}`, scaleTeamID))); err != nil {
+			summary.Failed++
+			logging.LogError(ctxlogger, err, "deleting the scale team unknown to intra")
+			continue
+		}
+		summary.Deleted++
+		ctxlogger.Info("deleted the scale team unknown to intra")
+	}
+	return summary, nil
+}
+
+// correct handles the scale team of intra as an update if it differs from its records. It is not compared with them if
+// its record within the window, nil if there is none, is as recent as it.
+func (handler *tasksHandler) correct(ctx context.Context, summary *Reconciliation, scaleTeam *intra.ScaleTeam, record db.ScaleTeam) {
+	ctx = logging.ContextWithField(ctx, "scale_team_id", scaleTeam.ID)
+	ctxlogger := logrus.WithField("scale_team_id", scaleTeam.ID)
+
+	if record != nil && !scaleTeam.UpdatedAt.After(record.GetIntraUpdatedAt()) {
+		ctxlogger.Debug("scale team's record is as recent as intra's: not comparing them")
+		return
+	}
+
+	change, err := handler.scaleTeamHandler.Preview(ctx, scaleTeam)
+	if err != nil {
+		summary.Failed++
+		logging.LogError(ctxlogger, err, "comparing the scale team with its records")
+		return
+	}
+	if !drifted(change) {
+		return
+	}
+
+	if err := handler.scaleTeamHandler.HandleScaleTeam(ctx, scaleTeam); err != nil {
+		summary.Failed++
+		logging.LogError(ctxlogger, err, "correcting the scale team's records")
+		return
+	}
+	countCorrection(summary, change)
+	ctxlogger.WithFields(logrus.Fields{
+		"action": change.Action,
+		"from":   change.From,
+		"to":     change.To,
+		"joined": len(change.Joined),
+		"left":   len(change.Left),
+	}).Info("corrected the scale team's records")
+}
+
+// drifted reports whether the records of the scale team differ from intra's.
+func drifted(change handler.Change) bool {
+	switch change.Action {
+	case handler.CreateAction, handler.RescheduleAction:
+		return true
+	case handler.DestroyedAction:
+		return false
+	default:
+		return change.MembersChanged()
+	}
+}
+
+func countCorrection(summary *Reconciliation, change handler.Change) {
+	switch change.Action {
+	case handler.CreateAction:
+		summary.Created++
+		return
+	case handler.RescheduleAction:
+		summary.Rescheduled++
+	}
+	if change.MembersChanged() {
+		summary.MembersUpdated++
+	}
+}
@@ -1,10 +1,14 @@
 package tasks
 
 import (
+	"time"
+
 	"github.com/gustavobelfort/42-jitsi/internal/config"
 	"github.com/gustavobelfort/42-jitsi/internal/db"
+	"github.com/gustavobelfort/42-jitsi/internal/handler"
+	"github.com/gustavobelfort/42-jitsi/internal/intra"
 	"github.com/gustavobelfort/42-jitsi/internal/logging"
-	"github.com/gustavobelfort/42-jitsi/internal/slack"
+	"github.com/gustavobelfort/42-jitsi/internal/notify"
 	"github.com/jinzhu/gorm"
 	"github.com/sirupsen/logrus"
 )
@@ -14,65 +18,109 @@ type tasksHandler struct {
 
 	scaleTeamManager db.ScaleTeamManager
 	userManager      db.UserManager
+	reminderManager  db.ReminderManager
+
+	attendanceManager db.AttendanceManager
+	noShowManager     db.NoShowManager
+
+	deliveryManager db.DeliveryManager
 
-	client slack.SlackThat
+	notifier         notify.Notifier
+	intra            intra.Client
+	scaleTeamHandler handler.ScaleTeamHandler
 }
 
 type TasksHandler interface {
 	Notify()
+	CheckAttendance()
+	PurgeDeliveries()
+	Reconcile()
 }
 
-func NewTasksHandler(client slack.SlackThat, dbInstance *gorm.DB) TasksHandler {
+func NewTasksHandler(notifier notify.Notifier, iClient intra.Client, stHandler handler.ScaleTeamHandler, dbInstance *gorm.DB) TasksHandler {
 	return &tasksHandler{
 		db: dbInstance,
 
 		scaleTeamManager: db.NewScaleTeamManager(dbInstance),
 		userManager:      db.NewUserManager(dbInstance),
-		client:           client,
+		reminderManager:  db.NewReminderManager(dbInstance),
+
+		attendanceManager: db.NewAttendanceManager(dbInstance),
+		noShowManager:     db.NewNoShowManager(dbInstance),
+
+		deliveryManager: db.NewDeliveryManager(dbInstance),
+
+		notifier:         notifier,
+		intra:            iClient,
+		scaleTeamHandler: stHandler,
 	}
 }
 
 func (handler *tasksHandler) Notify() {
-	logrus.Debug("getting notifiable scale teams")
-	scaleTeams, err := handler.getNotifiableScaleTeams()
+	logrus.Debug("getting due reminders")
+	reminders, err := handler.getDueReminders()
 	if err != nil {
-		logrus.WithError(err).Errorf("error getting notifiable scale teams: %v", err)
+		logrus.WithError(err).Errorf("error getting due reminders: %v", err)
 		return
 	}
 
-	if len(scaleTeams) == 0 {
-		logrus.Debugf("no scale teams to be notified")
+	if len(reminders) == 0 {
+		logrus.Debugf("no reminders to be sent")
 		return
 	}
 
-	logrus.Infof("found %d scale teams to notify", len(scaleTeams))
-	for _, scaleTeam := range scaleTeams {
-		scaleTeamID := scaleTeam.GetID()
-		ctxlogger := logrus.WithField("scale_team_id", scaleTeamID)
-
-		logins, err := handler.getScaleTeamUserLogins(scaleTeamID)
-		if err != nil {
-			logging.LogError(ctxlogger, err, "getting scale team users' logins")
+	logrus.Infof("found %d reminders to send", len(reminders))
+	for _, reminder := range reminders {
+		ctxlogger := logrus.WithFields(logrus.Fields{
+			"scale_team_id": reminder.GetScaleTeamID(),
+			"reminder_id":   reminder.GetID(),
+		})
+
+		if err := handler.sendReminder(reminder); err != nil {
+			if _, gone := err.(*db.GoneError); gone {
+				ctxlogger.Warn("reminder was sent but deleted in the meantime, e.g: by a reschedule")
+				continue
+			}
+			logging.LogError(ctxlogger, err, "sending reminder to the scale team")
 			continue
 		}
+		ctxlogger.Info("successfully sent reminder")
+	}
+}
 
-		err = handler.client.SendNotification(scaleTeamID, logins)
-		if err != nil {
-			logging.LogError(ctxlogger, err, "sending notification to the scale team")
-			continue
-		}
+func (handler *tasksHandler) sendReminder(reminder db.Reminder) error {
+	scaleTeamID := reminder.GetScaleTeamID()
 
-		scaleTeam.SetNotified(true)
-		if err := scaleTeam.Save(handler.db); err != nil {
-			logging.LogError(ctxlogger, err, "updating scale team notified field")
-			continue
-		}
-		ctxlogger.Info("successfully notified scale team")
+	scaleTeams, err := handler.scaleTeamManager.Get(handler.db, db.ScaleTeamIDOption(scaleTeamID))
+	if err != nil {
+		return err
+	}
+	if len(scaleTeams) == 0 {
+		return NotInDBError
+	}
+	scaleTeam := scaleTeams[0]
+
+	participants, err := handler.getScaleTeamParticipants(scaleTeamID)
+	if err != nil {
+		return err
+	}
+
+	err = handler.notifier.Notify(notify.Message{
+		Kind:         notify.Reminder,
+		ScaleTeamID:  scaleTeamID,
+		BeginAt:      scaleTeam.GetBeginAt(),
+		Meeting:      notify.Meeting(scaleTeam.GetMeeting()),
+		Participants: participants,
+	})
+	if err != nil {
+		return err
 	}
+
+	return handler.reminderManager.SetSent(handler.db, reminder.GetID(), time.Now())
 }
 
-func (handler *tasksHandler) getScaleTeamUserLogins(scaleTeamID int) ([]string, error) {
-	var logins []string
+func (handler *tasksHandler) getScaleTeamParticipants(scaleTeamID int) ([]notify.Participant, error) {
+	var participants []notify.Participant
 
 	users, err := handler.userManager.Get(handler.db, db.UserScaleTeamOption(scaleTeamID))
 	if err != nil {
@@ -80,20 +128,26 @@ func (handler *tasksHandler) getScaleTeamUserLogins(scaleTeamID int) ([]string,
 	}
 
 	for _, user := range users {
-		login := user.GetLogin()
-		logins = append(logins, login)
+		participants = append(participants, notify.Participant{
+			Login: user.GetLogin(),
+			Role:  notify.Role(user.GetStatus()),
+		})
 	}
 
-	return logins, nil
+	return participants, nil
 }
 
-func (handler *tasksHandler) getNotifiableScaleTeams() ([]db.ScaleTeam, error) {
-	scaleTeams, err := handler.scaleTeamManager.Get(handler.db,
-		db.ScaleTeamNotifiedOption(false),
-		db.ScaleTeamBeginAtInOption(config.Conf.WarnBefore),
+// getDueReminders returns the reminders to send. Those of the evaluations which began before the previous run, e.g:
+// while the daemon was down, expired: the participants would only be reminded late.
+func (handler *tasksHandler) getDueReminders() ([]db.Reminder, error) {
+	now := time.Now()
+	reminders, err := handler.reminderManager.Get(handler.db,
+		db.ReminderSentOption(false),
+		db.ReminderDueOption(now),
+		db.ReminderScaleTeamBeginAtAfterOption(now.Add(-config.Conf.NotifyInterval)),
 	)
 	if err != nil {
 		return nil, err
 	}
-	return scaleTeams, nil
+	return reminders, nil
 }
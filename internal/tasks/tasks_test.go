@@ -1,10 +1,21 @@
 package tasks
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gustavobelfort/42-jitsi/internal/config"
+	"github.com/gustavobelfort/42-jitsi/internal/db"
+	"github.com/gustavobelfort/42-jitsi/internal/handler"
+	"github.com/gustavobelfort/42-jitsi/internal/intra"
+	"github.com/gustavobelfort/42-jitsi/internal/notify"
 	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"gopkg.in/go-playground/assert.v1"
@@ -12,10 +23,12 @@ import (
 
 func TestScaleTeamHandler(t *testing.T) {
 	t.Run("NewTasksHandler", func(t *testing.T) {
-		client := &ClientMock{}
+		notifier := &NotifierMock{}
+		iClient := &IntraMock{}
+		stHandler := &ScaleTeamHandlerMock{}
 		db := &gorm.DB{}
 
-		handler := NewTasksHandler(client, db)
+		handler := NewTasksHandler(notifier, iClient, stHandler, db)
 		require.IsType(t, &tasksHandler{}, handler)
 
 		tHandler := handler.(*tasksHandler)
@@ -23,7 +36,13 @@ func TestScaleTeamHandler(t *testing.T) {
 		assert.Equal(t, db, tHandler.db)
 		assert.Equal(t, db, tHandler.scaleTeamManager.DB())
 		assert.Equal(t, db, tHandler.userManager.DB())
-		assert.Equal(t, client, tHandler.client)
+		assert.Equal(t, db, tHandler.reminderManager.DB())
+		assert.Equal(t, db, tHandler.attendanceManager.DB())
+		assert.Equal(t, db, tHandler.noShowManager.DB())
+		assert.Equal(t, db, tHandler.deliveryManager.DB())
+		assert.Equal(t, notifier, tHandler.notifier)
+		assert.Equal(t, iClient, tHandler.intra)
+		assert.Equal(t, stHandler, tHandler.scaleTeamHandler)
 	})
 
 	suite.Run(t, new(TasksHandlerSuite))
@@ -36,8 +55,343 @@ type TasksHandlerSuite struct {
 
 	stMock *ScaleTeamManagerMock
 	uMock  *UserManagerMock
-	cMock  *ClientMock
+	rMock  *ReminderManagerMock
+	aMock  *AttendanceManagerMock
+	nsMock *NoShowManagerMock
+	dMock  *DeliveryManagerMock
+	nMock  *NotifierMock
+	iMock  *IntraMock
+	hMock  *ScaleTeamHandlerMock
 
 	db     *gorm.DB
 	dbMock sqlmock.Sqlmock
 }
+
+var meeting = db.Meeting{
+	Provider: "jitsi",
+	RoomName: "bd28ee142ca5b46259f6e27fc3a4216f",
+	URL:      "https://meet.jit.si/bd28ee142ca5b46259f6e27fc3a4216f",
+}
+
+func (s *TasksHandlerSuite) SetupSuite() {
+	var (
+		db  *sql.DB
+		err error
+	)
+
+	db, s.dbMock, err = sqlmock.New()
+	s.Require().NoError(err)
+
+	s.db, err = gorm.Open("postgres", db)
+	s.Require().NoError(err)
+}
+
+func (s *TasksHandlerSuite) SetupTest() {
+	s.stMock = &ScaleTeamManagerMock{}
+	s.uMock = &UserManagerMock{}
+	s.rMock = &ReminderManagerMock{}
+	s.aMock = &AttendanceManagerMock{}
+	s.nsMock = &NoShowManagerMock{}
+	s.dMock = &DeliveryManagerMock{}
+	s.nMock = &NotifierMock{}
+	s.iMock = &IntraMock{}
+	s.hMock = &ScaleTeamHandlerMock{}
+
+	s.handler = &tasksHandler{
+		db:               s.db,
+		scaleTeamManager: s.stMock,
+		userManager:      s.uMock,
+		reminderManager:  s.rMock,
+
+		attendanceManager: s.aMock,
+		noShowManager:     s.nsMock,
+
+		deliveryManager: s.dMock,
+
+		notifier:         s.nMock,
+		intra:            s.iMock,
+		scaleTeamHandler: s.hMock,
+	}
+	config.Conf.DeliveryRetention = time.Hour * 72
+	config.Conf.ReconcileWindow = time.Hour * 24
+	config.Conf.Intra.CampusID = 21
+}
+
+// expectScaleTeam sets the expectations to get the scale team with the id 1 and its participants.
+func (s *TasksHandlerSuite) expectScaleTeam(beginAt time.Time) *ScaleTeamMock {
+	scaleTeam := &ScaleTeamMock{}
+	s.stMock.On("Get", mock.Anything, mock.Anything).Return([]db.ScaleTeam{scaleTeam}, nil).Once()
+	scaleTeam.On("GetBeginAt").Return(beginAt).Once()
+	scaleTeam.On("GetMeeting").Return(meeting).Once()
+
+	corrector := &UserMock{}
+	corrector.On("GetLogin").Return("xlogin").Once()
+	corrector.On("GetStatus").Return(string(db.Corrector)).Once()
+	s.uMock.On("Get", mock.Anything, mock.Anything).Return([]db.User{corrector}, nil).Once()
+
+	return scaleTeam
+}
+
+func (s *TasksHandlerSuite) Test00_Notify() {
+	beginAt := time.Now().Add(time.Minute * 15)
+	reminder := &ReminderMock{}
+	defer reminder.AssertExpectations(s.T())
+
+	s.rMock.On("Get", mock.Anything, mock.Anything).Return([]db.Reminder{reminder}, nil).Once()
+	reminder.On("GetID").Return(2).Twice()
+	reminder.On("GetScaleTeamID").Return(1).Twice()
+
+	scaleTeam := s.expectScaleTeam(beginAt)
+	defer scaleTeam.AssertExpectations(s.T())
+
+	s.nMock.On("Notify", notify.Message{
+		Kind:         notify.Reminder,
+		ScaleTeamID:  1,
+		BeginAt:      beginAt,
+		Meeting:      notify.Meeting(meeting),
+		Participants: []notify.Participant{{Login: "xlogin", Role: notify.Corrector}},
+	}).Return(nil).Once()
+
+	s.rMock.On("SetSent", mock.Anything, 2, mock.AnythingOfType("time.Time")).Return(nil).Once()
+
+	s.handler.Notify()
+}
+
+func (s *TasksHandlerSuite) Test01_Notify_SendError() {
+	beginAt := time.Now().Add(time.Minute * 15)
+	reminder := &ReminderMock{}
+	defer reminder.AssertExpectations(s.T())
+
+	s.rMock.On("Get", mock.Anything, mock.Anything).Return([]db.Reminder{reminder}, nil).Once()
+	reminder.On("GetID").Return(2).Once()
+	reminder.On("GetScaleTeamID").Return(1).Twice()
+
+	scaleTeam := s.expectScaleTeam(beginAt)
+	defer scaleTeam.AssertExpectations(s.T())
+
+	s.nMock.On("Notify", mock.Anything).Return(errors.New("testing")).Once()
+
+	s.handler.Notify()
+}
+
+func (s *TasksHandlerSuite) Test02_Notify_NotInDB() {
+	reminder := &ReminderMock{}
+	defer reminder.AssertExpectations(s.T())
+
+	s.rMock.On("Get", mock.Anything, mock.Anything).Return([]db.Reminder{reminder}, nil).Once()
+	reminder.On("GetID").Return(2).Once()
+	reminder.On("GetScaleTeamID").Return(1).Twice()
+
+	s.stMock.On("Get", mock.Anything, mock.Anything).Return([]db.ScaleTeam{}, nil).Once()
+
+	s.handler.Notify()
+}
+
+func (s *TasksHandlerSuite) Test03_Notify_GetError() {
+	s.rMock.On("Get", mock.Anything, mock.Anything).Return([]db.Reminder{}, errors.New("testing")).Once()
+
+	s.handler.Notify()
+}
+
+// expectAttendance sets the expectations to check the attendance of the scale team with the id 1, in which only the
+// corrected joined the meeting.
+func (s *TasksHandlerSuite) expectAttendance(beginAt time.Time) *ScaleTeamMock {
+	scaleTeam := &ScaleTeamMock{}
+	s.stMock.On("Get", mock.Anything, mock.Anything).Return([]db.ScaleTeam{scaleTeam}, nil).Once()
+	scaleTeam.On("GetID").Return(1)
+	scaleTeam.On("GetBeginAt").Return(beginAt)
+
+	corrector, corrected := &UserMock{}, &UserMock{}
+	corrector.On("GetLogin").Return("xlogin")
+	corrector.On("GetStatus").Return(string(db.Corrector))
+	corrected.On("GetLogin").Return("ylogin")
+	corrected.On("GetStatus").Return(string(db.Corrected))
+	s.uMock.On("Get", mock.Anything, mock.Anything).Return([]db.User{corrector, corrected}, nil).Once()
+
+	attendance := &AttendanceMock{}
+	attendance.On("GetLogin").Return("ylogin").Once()
+	s.aMock.On("Get", mock.Anything, mock.Anything).Return([]db.Attendance{attendance}, nil).Once()
+
+	s.nsMock.On("Create", mock.Anything, 1, "xlogin", db.Corrector, beginAt).Return(&NoShowMock{}, nil).Once()
+	return scaleTeam
+}
+
+func (s *TasksHandlerSuite) Test04_CheckAttendance() {
+	beginAt := time.Now().Add(-time.Minute * 15)
+	s.dbMock.ExpectBegin()
+	s.dbMock.ExpectCommit()
+
+	scaleTeam := s.expectAttendance(beginAt)
+	defer scaleTeam.AssertExpectations(s.T())
+	s.stMock.On("SetAttendanceChecked", mock.Anything, 1).Return(nil).Once()
+
+	s.nMock.On("Notify", notify.Message{
+		Kind:         notify.NoShow,
+		ScaleTeamID:  1,
+		BeginAt:      beginAt,
+		Participants: []notify.Participant{{Login: "xlogin", Role: notify.Corrector}},
+	}).Return(nil).Once()
+
+	s.handler.CheckAttendance()
+}
+
+func (s *TasksHandlerSuite) Test05_CheckAttendance_NotifyError() {
+	beginAt := time.Now().Add(-time.Minute * 15)
+	s.dbMock.ExpectBegin()
+	s.dbMock.ExpectCommit()
+
+	scaleTeam := s.expectAttendance(beginAt)
+	defer scaleTeam.AssertExpectations(s.T())
+	s.stMock.On("SetAttendanceChecked", mock.Anything, 1).Return(nil).Once()
+	s.nMock.On("Notify", mock.Anything).Return(errors.New("testing")).Once()
+
+	s.handler.CheckAttendance()
+}
+
+func (s *TasksHandlerSuite) Test06_CheckAttendance_SaveError() {
+	beginAt := time.Now().Add(-time.Minute * 15)
+	s.dbMock.ExpectBegin()
+	s.dbMock.ExpectRollback()
+
+	scaleTeam := s.expectAttendance(beginAt)
+	defer scaleTeam.AssertExpectations(s.T())
+	s.stMock.On("SetAttendanceChecked", mock.Anything, 1).Return(errors.New("testing")).Once()
+
+	s.handler.CheckAttendance()
+}
+
+func (s *TasksHandlerSuite) Test07_CheckAttendance_GetError() {
+	s.stMock.On("Get", mock.Anything, mock.Anything).Return([]db.ScaleTeam{}, errors.New("testing")).Once()
+
+	s.handler.CheckAttendance()
+}
+
+func (s *TasksHandlerSuite) Test08_PurgeDeliveries() {
+	before := mock.MatchedBy(func(before time.Time) bool {
+		return time.Since(before) >= time.Hour*72 && time.Since(before) < time.Hour*73
+	})
+	s.dMock.On("Purge", s.db, before).Return(int64(3), nil).Once()
+
+	s.handler.PurgeDeliveries()
+}
+
+func (s *TasksHandlerSuite) Test09_PurgeDeliveries_Error() {
+	s.dMock.On("Purge", s.db, mock.AnythingOfType("time.Time")).Return(int64(0), errors.New("testing")).Once()
+
+	s.handler.PurgeDeliveries()
+}
+
+// storedScaleTeam returns a scale team record with the given id.
+func storedScaleTeam(id int) *ScaleTeamMock {
+	scaleTeam := &ScaleTeamMock{}
+	scaleTeam.On("GetID").Return(id).Once()
+	return scaleTeam
+}
+
+// versionedScaleTeam returns a scale team record with the given id, last updated on intra at `updatedAt`.
+func versionedScaleTeam(id int, updatedAt time.Time) *ScaleTeamMock {
+	scaleTeam := storedScaleTeam(id)
+	scaleTeam.On("GetIntraUpdatedAt").Return(updatedAt).Once()
+	return scaleTeam
+}
+
+func (s *TasksHandlerSuite) Test10_reconcile() {
+	from := time.Date(2020, 7, 15, 0, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour * 24)
+	version := time.Date(2020, 7, 14, 10, 30, 0, 0, time.UTC)
+	created := &intra.ScaleTeam{ID: 21, UpdatedAt: version}
+	notUpdated := &intra.ScaleTeam{ID: 22, UpdatedAt: version}
+	rescheduled := &intra.ScaleTeam{ID: 23, UpdatedAt: version}
+	unchanged := &intra.ScaleTeam{ID: 26, UpdatedAt: version}
+	moved := &intra.ScaleTeam{ID: 25, UpdatedAt: version}
+
+	ctx := context.Background()
+	s.iMock.On("ListScaleTeams", ctx, 21, from, to).Return([]intra.ScaleTeam{*created, *notUpdated, *rescheduled, *unchanged}, nil).Once()
+	s.stMock.On("Get", s.db, mock.Anything).Return([]db.ScaleTeam{
+		versionedScaleTeam(22, version),
+		versionedScaleTeam(23, version.Add(-time.Hour)),
+		storedScaleTeam(24),
+		versionedScaleTeam(25, version.Add(-time.Hour)),
+		versionedScaleTeam(26, time.Time{}),
+	}, nil).Once()
+
+	s.hMock.On("Preview", mock.Anything, created).Return(handler.Change{ScaleTeamID: 21, Action: handler.CreateAction}, nil).Once()
+	s.hMock.On("HandleScaleTeam", mock.Anything, created).Return(nil).Once()
+	// The scale team 22 was not updated since it was stored: it is neither previewed nor handled.
+	s.hMock.On("Preview", mock.Anything, unchanged).Return(handler.Change{ScaleTeamID: 26, Action: handler.UnchangedAction}, nil).Once()
+	s.hMock.On("Preview", mock.Anything, rescheduled).Return(handler.Change{
+		ScaleTeamID: 23,
+		Action:      handler.RescheduleAction,
+		Joined:      []handler.Member{{Login: "ylogin", Status: db.Corrected}},
+	}, nil).Once()
+	s.hMock.On("HandleScaleTeam", mock.Anything, rescheduled).Return(nil).Once()
+
+	s.iMock.On("GetScaleTeam", ctx, 24).Return((*intra.ScaleTeam)(nil), &intra.HTTPError{Response: &http.Response{StatusCode: http.StatusNotFound}}).Once()
+	s.hMock.On("HandleDestroy", mock.Anything, []byte(`{"id": 24}`)).Return(nil).Once()
+	s.iMock.On("GetScaleTeam", ctx, 25).Return(moved, nil).Once()
+	s.hMock.On("Preview", mock.Anything, moved).Return(handler.Change{ScaleTeamID: 25, Action: handler.RescheduleAction}, nil).Once()
+	s.hMock.On("HandleScaleTeam", mock.Anything, moved).Return(errors.New("testing")).Once()
+
+	summary, err := s.handler.reconcile(ctx, from, to)
+	s.Require().NoError(err)
+	s.Equal(&Reconciliation{
+		Listed:         4,
+		Created:        1,
+		Rescheduled:    1,
+		MembersUpdated: 1,
+		Deleted:        1,
+		Failed:         1,
+	}, summary)
+}
+
+func (s *TasksHandlerSuite) Test11_reconcile_GetScaleTeamError() {
+	from := time.Date(2020, 7, 15, 0, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour * 24)
+
+	ctx := context.Background()
+	s.iMock.On("ListScaleTeams", ctx, 21, from, to).Return([]intra.ScaleTeam{}, nil).Once()
+	s.stMock.On("Get", s.db, mock.Anything).Return([]db.ScaleTeam{storedScaleTeam(24)}, nil).Once()
+	s.iMock.On("GetScaleTeam", ctx, 24).Return((*intra.ScaleTeam)(nil), errors.New("testing")).Once()
+
+	summary, err := s.handler.reconcile(ctx, from, to)
+	s.Require().NoError(err)
+	s.Equal(&Reconciliation{Failed: 1}, summary)
+}
+
+func (s *TasksHandlerSuite) Test12_Reconcile_ListError() {
+	s.iMock.On("ListScaleTeams", mock.Anything, 21, mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).Return([]intra.ScaleTeam{}, errors.New("testing")).Once()
+
+	s.handler.Reconcile()
+}
+
+func (s *TasksHandlerSuite) TearDownTest() {
+	s.stMock.AssertExpectations(s.T())
+	s.uMock.AssertExpectations(s.T())
+	s.rMock.AssertExpectations(s.T())
+	s.aMock.AssertExpectations(s.T())
+	s.nsMock.AssertExpectations(s.T())
+	s.dMock.AssertExpectations(s.T())
+	s.nMock.AssertExpectations(s.T())
+	s.iMock.AssertExpectations(s.T())
+	s.hMock.AssertExpectations(s.T())
+	s.NoError(s.dbMock.ExpectationsWereMet())
+}
+
+func (s *TasksHandlerSuite) Test13_Notify_ReminderGone() {
+	beginAt := time.Now().Add(time.Minute * 15)
+	reminder := &ReminderMock{}
+	defer reminder.AssertExpectations(s.T())
+
+	s.rMock.On("Get", mock.Anything, mock.Anything).Return([]db.Reminder{reminder}, nil).Once()
+	reminder.On("GetID").Return(2).Twice()
+	reminder.On("GetScaleTeamID").Return(1).Twice()
+
+	scaleTeam := s.expectScaleTeam(beginAt)
+	defer scaleTeam.AssertExpectations(s.T())
+
+	s.nMock.On("Notify", mock.Anything).Return(nil).Once()
+	// The reminder was deleted by a reschedule while it was being sent.
+	s.rMock.On("SetSent", mock.Anything, 2, mock.AnythingOfType("time.Time")).Return(&db.GoneError{Table: "reminders", ID: 2}).Once()
+
+	s.handler.Notify()
+}